@@ -0,0 +1,330 @@
+package arena
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/snake"
+	"go-game/snake/ai"
+)
+
+// testLevel 测试使用的空白面板
+var testLevel = &snake.Level{Name: "Arena", Width: 30, Height: 20}
+
+// entityLevel 带传送门和巡逻障碍的面板
+var entityLevel = &snake.Level{
+	Name: "Entities", Width: 30, Height: 20,
+	Portals:      []snake.Portal{{A: snake.Point{X: 2, Y: 2}, B: snake.Point{X: 27, Y: 17}}},
+	Hazards:      []snake.Hazard{{Pos: snake.Point{X: 5, Y: 10}, Dir: snake.Right}, {Pos: snake.Point{X: 15, Y: 1}, Dir: snake.Down}},
+	HazardPeriod: 2,
+}
+
+// newTestServer 在本机随机端口上启动服务器，返回服务器和地址
+// 自动推进的间隔设为一小时，测试通过 step 手动推进
+func newTestServer(t *testing.T, cfg Config) (*Server, string) {
+	t.Helper()
+	cfg.Tick = time.Hour
+	if cfg.Level == nil {
+		cfg.Level = testLevel
+	}
+	s := NewServer(cfg)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+	return s, l.Addr().String()
+}
+
+// sameFrame 比较两个 Frame（空切片与 nil 视为相同）
+func sameFrame(t *testing.T, got, want Frame) {
+	t.Helper()
+	if got.Seq != want.Seq || got.Width != want.Width || got.Height != want.Height || got.Wrap != want.Wrap ||
+		!slices.Equal(got.Walls, want.Walls) || !slices.Equal(got.Foods, want.Foods) || len(got.Snakes) != len(want.Snakes) ||
+		!slices.Equal(got.Portals, want.Portals) || !slices.Equal(got.Hazards, want.Hazards) {
+		t.Fatalf("frame differs:\ngot  %+v\nwant %+v", got, want)
+	}
+	for i, g := range got.Snakes {
+		w := want.Snakes[i]
+		if !slices.Equal(g.Body, w.Body) {
+			t.Fatalf("seq %d snake %d body = %v, want %v", want.Seq, i, g.Body, w.Body)
+		}
+		g.Body, w.Body = nil, nil
+		if !reflect.DeepEqual(g, w) {
+			t.Fatalf("seq %d snake %d = %+v, want %+v", want.Seq, i, g, w)
+		}
+	}
+}
+
+// TestDiffApply 客户端按收到的增量更新，每一步都与服务器的状态完全一致
+// 过程中有蛇撞死、复活、加入和离开，增量经过 JSON 编码和解码
+func TestDiffApply(t *testing.T) {
+	for _, lvl := range []*snake.Level{testLevel, entityLevel} {
+		t.Run(lvl.Name, func(t *testing.T) { testDiffApply(t, lvl) })
+	}
+}
+
+func testDiffApply(t *testing.T, lvl *snake.Level) {
+	s := NewServer(Config{Seed: 1, Level: lvl, Wrap: true})
+	bot := ai.NewGreedy()
+	client := s.frame.clone()
+	for step := 0; step < 600; step++ {
+		s.mu.Lock()
+		switch {
+		case step%60 == 0:
+			s.add("bot")
+		case step%150 == 75:
+			s.remove(step / 150)
+		}
+		st := s.game.State()
+		for id, sn := range st.Snakes {
+			switch {
+			case s.gone[id]:
+			case !sn.Alive:
+				s.game.Respawn(id)
+			default:
+				s.game.TurnPlayer(id, bot.Next(st, id))
+			}
+		}
+		s.mu.Unlock()
+
+		var m message
+		if err := json.Unmarshal(encode(message{Type: "tick", Delta: ptr(s.step())}), &m); err != nil {
+			t.Fatal(err)
+		}
+		client.apply(*m.Delta)
+		sameFrame(t, client, s.frame)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// TestServerClients 客户端通过 TCP 收到的状态与服务器一致；中途加入的蛇出现在空位上
+func TestServerClients(t *testing.T) {
+	s, addr := newTestServer(t, Config{Seed: 2})
+	var clients []*Client
+	for _, name := range []string{"alice", "bob"} {
+		c, err := Dial(addr, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		clients = append(clients, c)
+	}
+
+	bot := ai.NewGreedy()
+	for step := 0; step < 30; step++ {
+		if step == 10 {
+			late, err := Dial(addr, "carol")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer late.Close()
+			clients = append(clients, late)
+		}
+		s.step()
+		for _, c := range clients {
+			f, err := c.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.mu.Lock()
+			sameFrame(t, *f, s.frame)
+			s.mu.Unlock()
+
+			// 新加入的蛇的第一步：远离其他蛇头
+			if me, ok := c.Me(); ok && c.ID == 2 && step == 10 {
+				for id, other := range f.Snakes {
+					if id == c.ID || !other.Alive {
+						continue
+					}
+					if d := abs(other.Body[0].X-me.Body[0].X) + abs(other.Body[0].Y-me.Body[0].Y); d < 4 {
+						t.Errorf("late joiner spawned %d cells from snake %d", d, id)
+					}
+				}
+			}
+			if me, ok := c.Me(); ok {
+				if me.Alive {
+					c.Turn(bot.Next(f.State(), c.ID))
+				} else {
+					c.Respawn()
+				}
+			}
+		}
+		// 等服务器处理完这一步的请求
+		time.Sleep(5 * time.Millisecond)
+	}
+	if names := []string{clients[0].frame.Snakes[0].Name, clients[0].frame.Snakes[2].Name}; names[0] != "alice" || names[1] != "carol" {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// TestServerLeave 断开连接的玩家从面板上移除并标记为离开
+func TestServerLeave(t *testing.T) {
+	s, addr := newTestServer(t, Config{Seed: 3})
+	a, err := Dial(addr, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Dial(addr, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	s.step()
+	b.Next()
+
+	a.Close()
+	for i := 0; i < 100; i++ {
+		s.step()
+		f, err := b.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gone := f.Snakes[a.ID]; gone.Gone {
+			if gone.Alive || len(gone.Body) != 0 {
+				t.Errorf("departed snake still on the board: %+v", gone)
+			}
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("departure never reached the other client")
+}
+
+// TestServerReuseSlot 新玩家使用离开的玩家留下的下标，服务器记录的蛇不会越来越多
+func TestServerReuseSlot(t *testing.T) {
+	s, addr := newTestServer(t, Config{Seed: 3})
+	for i := range 5 {
+		c, err := Dial(addr, "visitor")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.ID != 0 {
+			t.Errorf("visitor %d got id %d, want the free slot 0", i, c.ID)
+		}
+		c.Close()
+		// 等服务器处理完断开
+		for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+			s.mu.Lock()
+			gone := s.gone[c.ID]
+			s.mu.Unlock()
+			if gone {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("server never noticed the disconnect")
+			}
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.names) != 1 || len(s.game.State().Snakes) != 1 {
+		t.Errorf("%d names and %d snakes after 5 visits, want 1", len(s.names), len(s.game.State().Snakes))
+	}
+}
+
+// TestJoinSlowClient 发送队列已满时欢迎消息不会阻塞服务器，而是断开这个客户端
+func TestJoinSlowClient(t *testing.T) {
+	s := NewServer(Config{Seed: 6, Level: testLevel})
+	server, client := net.Pipe()
+	defer client.Close()
+	c := &conn{Conn: server, id: -1, out: make(chan []byte)} // 没有人读取的发送队列
+
+	done := make(chan error)
+	go func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		done <- s.join(c, "slow")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("join blocked on a full send queue while holding the lock")
+	}
+	if _, err := client.Read(make([]byte, 1)); err == nil {
+		t.Error("slow client should be disconnected")
+	}
+}
+
+// TestServerFull 人数达到上限时拒绝加入
+func TestServerFull(t *testing.T) {
+	_, addr := newTestServer(t, Config{Seed: 4, MaxPlayers: 1})
+	a, err := Dial(addr, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	if _, err := Dial(addr, "bob"); err == nil || !strings.Contains(err.Error(), "full") {
+		t.Errorf("second Dial error = %v, want arena is full", err)
+	}
+}
+
+// TestProtocolErrors 有误的请求返回错误消息，连接保持可用
+func TestProtocolErrors(t *testing.T) {
+	_, addr := newTestServer(t, Config{Seed: 5})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	for _, tt := range []struct{ send, want string }{
+		{`not json`, "invalid request"},
+		{`{"cmd":"turn","dir":"left"}`, "join first"},
+		{`{"cmd":"join","name":"eve"}`, `"type":"welcome"`},
+		{`{"cmd":"join","name":"eve"}`, "already joined"},
+		{`{"cmd":"turn","dir":"sideways"}`, "unknown direction"},
+		{`{"cmd":"dance"}`, "unknown command"},
+	} {
+		if _, err := conn.Write([]byte(tt.send + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(line, tt.want) {
+			t.Errorf("%s -> %s, want %q", tt.send, strings.TrimSpace(line), tt.want)
+		}
+	}
+}
+
+// TestRenderGolden 竞技场客户端画面
+func TestRenderGolden(t *testing.T) {
+	s := NewServer(Config{Seed: 6, Level: &snake.Level{Name: "Arena", Width: 24, Height: 14}})
+	for _, name := range []string{"alice", "bob", "carol"} {
+		s.add(name)
+	}
+	bot := ai.NewGreedy()
+	for range 12 {
+		st := s.game.State()
+		for id := range st.Snakes {
+			s.game.TurnPlayer(id, bot.Next(st, id))
+		}
+		s.step()
+	}
+	s.remove(1)
+	s.step()
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewRenderer(screen, input.DefaultSnake(), 0).Render(&s.frame)
+	screentest.AssertScreen(t, "arena", screen)
+}
@@ -0,0 +1,153 @@
+package arena
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go-game/snake"
+)
+
+// ============================================
+// Client - 客户端和机器人接口
+// ============================================
+// 用法（机器人）：
+//
+//	c, err := arena.Dial("localhost:7777", "my-bot")
+//	for {
+//	    f, err := c.Next()        // 等待下一步，返回更新后的完整状态
+//	    me := f.Snakes[c.ID]
+//	    ...
+//	    c.Turn(snake.Left)
+//	}
+//
+// 也可以直接把 snake/ai 的控制器交给 RunBot
+
+// Client 一个已加入竞技场的连接
+type Client struct {
+	ID   int           // 自己的蛇的下标
+	Tick time.Duration // 服务器的移动间隔
+
+	conn  net.Conn
+	dec   *json.Decoder
+	mu    sync.Mutex // 保护 enc，Turn 可能与 Next 在不同的 goroutine 中调用
+	enc   *json.Encoder
+	frame Frame
+}
+
+// Dial 连接服务器并以 name 加入竞技场
+func Dial(addr, name string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn, dec: json.NewDecoder(conn), enc: json.NewEncoder(conn)}
+	if err := c.send(request{Cmd: "join", Name: name}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	m, err := c.read()
+	if err == nil && m.Welcome == nil {
+		err = fmt.Errorf("arena: unexpected %q message", m.Type)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c.ID, c.Tick, c.frame = m.ID, time.Duration(m.Tick)*time.Millisecond, m.Frame
+	return c, nil
+}
+
+// read 读取下一条消息，服务器返回的错误转换为 error
+func (c *Client) read() (message, error) {
+	var m message
+	if err := c.dec.Decode(&m); err != nil {
+		return m, err
+	}
+	if m.Type == "error" {
+		return m, errors.New("arena: " + m.Error)
+	}
+	return m, nil
+}
+
+// send 发送一个请求
+func (c *Client) send(req request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enc.Encode(req)
+}
+
+// Next 等待服务器推进一步，返回更新后的状态（每次返回同一个 Frame，下次调用时会被修改）
+// 连接关闭时返回 io.EOF
+func (c *Client) Next() (*Frame, error) {
+	for {
+		m, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		if m.Delta != nil {
+			c.frame.apply(*m.Delta)
+			return &c.frame, nil
+		}
+	}
+}
+
+// Frame 返回当前的状态
+func (c *Client) Frame() *Frame {
+	return &c.frame
+}
+
+// Me 返回自己的蛇，加入后的第一步之前还没有出现时返回 false
+func (c *Client) Me() (Snake, bool) {
+	if c.ID < len(c.frame.Snakes) {
+		return c.frame.Snakes[c.ID], true
+	}
+	return Snake{}, false
+}
+
+// Turn 请求转向
+func (c *Client) Turn(d snake.Direction) error {
+	return c.send(request{Cmd: "turn", Dir: dirNames[d]})
+}
+
+// Respawn 请求复活
+func (c *Client) Respawn() error {
+	return c.send(request{Cmd: "respawn"})
+}
+
+// Close 离开竞技场并断开连接
+func (c *Client) Close() error {
+	c.send(request{Cmd: "leave"})
+	return c.conn.Close()
+}
+
+// RunBot 让控制器驱动 c 的蛇：每一步选择方向，撞死后自动复活
+// 直到连接关闭（返回 nil）或出错
+func RunBot(c *Client, ctrl snake.Controller) error {
+	for {
+		f, err := c.Next()
+		if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		me, ok := c.Me()
+		switch {
+		case !ok:
+		case !me.Alive:
+			err = c.Respawn()
+		default:
+			if d := ctrl.Next(f.State(), c.ID); d != me.Direction {
+				err = c.Turn(d)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package arena
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/snake"
+)
+
+// ============================================
+// 终端客户端
+// ============================================
+
+// Play 在终端中游玩已加入的竞技场：方向键转向，R 复活，Esc 离开
+// 服务器断开连接时返回错误
+func Play(screen tcell.Screen, keys *input.Keymap, c *Client) error {
+	// 网络消息在单独的 goroutine 中接收，把状态的副本交给界面
+	frames := make(chan Frame, 16)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			f, err := c.Next()
+			if err != nil {
+				errc <- err
+				return
+			}
+			frames <- f.clone()
+		}
+	}()
+
+	r := NewRenderer(screen, keys, c.ID)
+	frame := c.Frame().clone()
+	r.Render(&frame)
+
+	for {
+		// ---------- 处理用户输入 ----------
+		for screen.HasPendingEvent() {
+			switch ev := screen.PollEvent().(type) {
+			case *tcell.EventKey:
+				action, ok := keys.Lookup(ev)
+				if !ok {
+					continue
+				}
+				var err error
+				switch action {
+				case input.Back:
+					return nil
+				case input.MoveUp, input.MoveDown, input.MoveLeft, input.MoveRight:
+					d, _ := snake.ParseDirection(action.String())
+					err = c.Turn(d)
+				case input.Restart:
+					err = c.Respawn()
+				}
+				if err != nil {
+					return err
+				}
+			case *tcell.EventResize:
+				r.Render(&frame)
+			case nil:
+				return nil
+			}
+		}
+
+		// ---------- 显示最新的状态 ----------
+		select {
+		case frame = <-frames:
+			for len(frames) > 0 {
+				frame = <-frames
+			}
+			r.Render(&frame)
+		case err := <-errc:
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return errors.New("arena: server closed the connection")
+			}
+			return err
+		default:
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// ============================================
+// Renderer - 竞技场画面
+// ============================================
+// 面板的画法与本地贪吃蛇相同，右侧是按得分排序的玩家列表
+
+// Renderer 竞技场客户端的渲染器
+type Renderer struct {
+	screen tcell.Screen
+	keys   *input.Keymap
+	id     int // 自己的蛇的下标
+}
+
+// NewRenderer 创建渲染器，id 为自己的蛇的下标
+func NewRenderer(screen tcell.Screen, keys *input.Keymap, id int) *Renderer {
+	return &Renderer{screen: screen, keys: keys, id: id}
+}
+
+// Render 绘制一帧
+func (r *Renderer) Render(f *Frame) {
+	r.screen.Clear()
+	r.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+	infoStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+
+	// 边框（穿墙模式为灰色虚线）
+	borderStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	vertical, horizontal := '|', '-'
+	if f.Wrap {
+		borderStyle = tcell.StyleDefault.Foreground(tcell.ColorGray)
+		vertical, horizontal = ':', '.'
+	}
+	for y := 0; y < f.Height+2; y++ {
+		r.screen.SetContent(2, y+1, vertical, nil, borderStyle)
+		r.screen.SetContent(f.Width*2+4, y+1, vertical, nil, borderStyle)
+	}
+	for x := 0; x < f.Width*2+1; x++ {
+		r.screen.SetContent(3+x, 1, horizontal, nil, borderStyle)
+		r.screen.SetContent(3+x, f.Height+2, horizontal, nil, borderStyle)
+	}
+
+	// 障碍物、传送门、蛇、食物和巡逻障碍
+	cell := func(p snake.Point, ch rune, style tcell.Style) {
+		r.screen.SetContent(4+p.X*2, p.Y+2, ch, nil, style)
+		r.screen.SetContent(5+p.X*2, p.Y+2, ' ', nil, style)
+	}
+	wallStyle := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	for _, p := range f.Walls {
+		r.screen.SetContent(4+p.X*2, p.Y+2, '█', nil, wallStyle)
+		r.screen.SetContent(5+p.X*2, p.Y+2, '█', nil, wallStyle)
+	}
+	for _, pt := range f.Portals {
+		cell(pt.A, '◎', tcell.StyleDefault.Foreground(tcell.ColorDodgerBlue))
+		cell(pt.B, '◎', tcell.StyleDefault.Foreground(tcell.ColorDodgerBlue))
+	}
+	for id, sn := range f.Snakes {
+		head, body := snake.PlayerColor(id)
+		for i, p := range sn.Body {
+			style := tcell.StyleDefault.Foreground(body)
+			if i == 0 {
+				style = tcell.StyleDefault.Foreground(head).Bold(id == r.id)
+			}
+			cell(p, '●', style)
+		}
+	}
+	for _, food := range f.Foods {
+		glyph, style := snake.FoodLook(food)
+		cell(food.Pos, glyph, style)
+	}
+	hazardStyle := tcell.StyleDefault.Foreground(tcell.ColorRed)
+	for _, h := range f.Hazards {
+		r.screen.SetContent(4+h.Pos.X*2, h.Pos.Y+2, '▒', nil, hazardStyle)
+		r.screen.SetContent(5+h.Pos.X*2, h.Pos.Y+2, '▒', nil, hazardStyle)
+	}
+
+	// 右侧面板：标题、自己的名字、按得分排序的玩家列表和操作说明
+	x := f.Width*2 + 8
+	title := "SNAKE ARENA"
+	if f.Wrap {
+		title += " (WRAP)"
+	}
+	drawText(r.screen, x, 2, title, infoStyle)
+	if r.id < len(f.Snakes) {
+		drawText(r.screen, x, 3, "YOU: "+f.Snakes[r.id].Name, infoStyle)
+	}
+
+	var ids []int
+	for id, sn := range f.Snakes {
+		if !sn.Gone {
+			ids = append(ids, id)
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return f.Snakes[ids[i]].Score > f.Snakes[ids[j]].Score })
+	row := 5
+	for _, id := range ids[:min(len(ids), max(f.Height-6, 3))] {
+		sn := f.Snakes[id]
+		head, _ := snake.PlayerColor(id)
+		style := tcell.StyleDefault.Foreground(head)
+		if !sn.Alive {
+			style = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+		}
+		mark := " "
+		if id == r.id {
+			mark = "►"
+		}
+		drawText(r.screen, x, row, fmt.Sprintf("%s%-10.10s %5d", mark, sn.Name, sn.Score), style)
+		row++
+	}
+
+	help := r.keys.HelpFor(input.MoveUp, input.MoveDown, input.MoveLeft, input.MoveRight, input.Restart, input.Back)
+	for i, line := range help {
+		drawText(r.screen, x, row+1+i, line, infoStyle)
+	}
+
+	// 自己撞死后提示复活
+	if r.id < len(f.Snakes) && !f.Snakes[r.id].Alive {
+		centerX, centerY := f.Width/2*2, f.Height/2+2
+		drawText(r.screen, centerX, centerY, "YOU DIED", infoStyle)
+		drawText(r.screen, centerX-4, centerY+2, fmt.Sprintf("Press %s to respawn", r.keys.Label(input.Restart)), infoStyle)
+	}
+
+	r.screen.Show()
+}
+
+// drawText 从 (x, y) 开始绘制一行文字
+func drawText(screen tcell.Screen, x, y int, text string, style tcell.Style) {
+	for i, ch := range []rune(text) {
+		screen.SetContent(x+i, y, ch, nil, style)
+	}
+}
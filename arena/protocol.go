@@ -0,0 +1,225 @@
+// Package arena 多人贪吃蛇竞技场：基于 TCP 的服务器、终端客户端和机器人接口
+//
+// 服务器运行一个权威的 snake 竞技场（见 snake.WithArena），按固定间隔推进，
+// 每一步只向客户端广播变化的部分。只依赖标准库，适合在本机或局域网内举办比赛。
+package arena
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-game/snake"
+)
+
+// ============================================
+// 协议 - 基于 TCP 的逐行 JSON
+// ============================================
+// 与 env 包一样每行一个 JSON 对象。客户端发送请求：
+//
+//   → {"cmd": "join", "name": "alice"}       加入竞技场（连接后的第一个请求）
+//   → {"cmd": "turn", "dir": "left"}         转向：up / down / left / right
+//   → {"cmd": "respawn"}                     撞死后复活（没有空位时忽略，可以稍后再试）
+//   → {"cmd": "leave"}                       离开竞技场（直接断开连接也可以）
+//
+// 服务器发送消息：
+//
+//   ← {"type": "welcome", "id": 3, "tick": 150, "frame": {...}}   加入成功：自己的下标和完整状态
+//   ← {"type": "tick", "seq": 42, "snakes": [...], "foods": [...]} 每移动一格：相对上一步的变化（关卡中的巡逻障碍移动时另有 "hazards"）
+//   ← {"type": "error", "error": "..."}                            请求有误，连接保持可用
+//
+// 新加入的蛇从下一个 tick 开始出现在 snakes 中，可能使用已经离开的蛇留下的下标
+
+// request 客户端的一行请求
+type request struct {
+	Cmd  string `json:"cmd"`
+	Name string `json:"name,omitempty"`
+	Dir  string `json:"dir,omitempty"`
+}
+
+// message 服务器的一行消息，根据 Type 只有对应的部分有内容
+type message struct {
+	Type  string `json:"type"` // welcome / tick / error
+	Error string `json:"error,omitempty"`
+	*Welcome
+	*Delta
+}
+
+// Welcome 加入成功后的第一条消息
+type Welcome struct {
+	ID    int   `json:"id"`    // 自己的蛇在 Frame.Snakes 中的下标
+	Tick  int   `json:"tick"`  // 移动间隔（毫秒）
+	Frame Frame `json:"frame"` // 当前的完整状态
+}
+
+// Delta 移动一格之后的变化
+type Delta struct {
+	Seq     int            `json:"seq"`               // 步数
+	Snakes  []SnakeDelta   `json:"snakes,omitempty"`  // 有变化的蛇
+	Foods   []snake.Food   `json:"foods,omitempty"`   // 食物有变化时的完整列表，省略表示没有变化
+	Hazards []snake.Hazard `json:"hazards,omitempty"` // 巡逻障碍移动时的完整列表，省略表示没有变化
+}
+
+// SnakeDelta 一条蛇的变化
+// 身体的变化有三种：复活或新加入时给出完整的 Body；移动一格时给出新的 Head 和蛇尾移除的格数 Cut；
+// 撞死或离开时 Alive 为 false，身体清空
+type SnakeDelta struct {
+	ID     int             `json:"id"`
+	Name   string          `json:"name,omitempty"` // 只在新加入（包括使用离开的蛇留下的下标）时给出
+	Body   []snake.Point   `json:"body,omitempty"`
+	Head   *snake.Point    `json:"head,omitempty"`
+	Cut    int             `json:"cut,omitempty"`
+	Dir    snake.Direction `json:"dir"`
+	Length int             `json:"length"`
+	Score  int             `json:"score"`
+	Alive  bool            `json:"alive"`
+	Gone   bool            `json:"gone,omitempty"`
+}
+
+// ============================================
+// Frame - 客户端看到的完整状态
+// ============================================
+
+// Frame 竞技场某一步的完整状态，客户端收到增量后就地更新
+type Frame struct {
+	Seq     int            `json:"seq"`
+	Width   int            `json:"width"`
+	Height  int            `json:"height"`
+	Wrap    bool           `json:"wrap"`
+	Walls   []snake.Point  `json:"walls,omitempty"`
+	Portals []snake.Portal `json:"portals,omitempty"`
+	Hazards []snake.Hazard `json:"hazards,omitempty"`
+	Snakes  []Snake        `json:"snakes"` // 下标即蛇的 ID，离开的蛇保留位置，直到新加入的蛇使用这个下标
+	Foods   []snake.Food   `json:"foods"`
+}
+
+// Snake 竞技场中的一条蛇
+type Snake struct {
+	Name      string          `json:"name"`
+	Body      []snake.Point   `json:"body"` // Body[0] 为头部，撞死后为空
+	Direction snake.Direction `json:"dir"`
+	Length    int             `json:"length"` // 目标长度（身体比它短时蛇尾不会移动）
+	Score     int             `json:"score"`
+	Alive     bool            `json:"alive"`
+	Gone      bool            `json:"gone,omitempty"` // 已经离开竞技场
+}
+
+// State 把 Frame 转换为 snake.State，可以直接交给 snake/ai 的控制器
+func (f *Frame) State() snake.State {
+	s := snake.State{
+		Width:   f.Width,
+		Height:  f.Height,
+		Walls:   f.Walls,
+		Portals: f.Portals,
+		Hazards: f.Hazards,
+		Foods:   f.Foods,
+		Wrap:    f.Wrap,
+	}
+	for _, sn := range f.Snakes {
+		s.Snakes = append(s.Snakes, snake.SnakeState{
+			Body:      sn.Body,
+			Direction: sn.Direction,
+			Score:     sn.Score,
+			Length:    sn.Length,
+			Alive:     sn.Alive,
+		})
+	}
+	return s
+}
+
+// clone 深拷贝
+func (f Frame) clone() Frame {
+	f.Walls = slices.Clone(f.Walls)
+	f.Portals = slices.Clone(f.Portals)
+	f.Hazards = slices.Clone(f.Hazards)
+	f.Foods = slices.Clone(f.Foods)
+	f.Snakes = slices.Clone(f.Snakes)
+	for i := range f.Snakes {
+		f.Snakes[i].Body = slices.Clone(f.Snakes[i].Body)
+	}
+	return f
+}
+
+// diff 计算从 prev 到 cur 的变化
+func diff(prev, cur Frame) Delta {
+	d := Delta{Seq: cur.Seq}
+	for id, sn := range cur.Snakes {
+		sd := SnakeDelta{ID: id, Dir: sn.Direction, Length: sn.Length, Score: sn.Score, Alive: sn.Alive, Gone: sn.Gone}
+		if id >= len(prev.Snakes) || prev.Snakes[id].Name != sn.Name || prev.Snakes[id].Gone && !sn.Gone {
+			// 新加入，或者新玩家使用了离开的蛇留下的下标
+			sd.Name, sd.Body = sn.Name, sn.Body
+			d.Snakes = append(d.Snakes, sd)
+			continue
+		}
+
+		old := prev.Snakes[id]
+		switch body := sn.Body; {
+		case slices.Equal(old.Body, body):
+			if old.Direction == sn.Direction && old.Length == sn.Length && old.Score == sn.Score &&
+				old.Alive == sn.Alive && old.Gone == sn.Gone {
+				continue
+			}
+		case len(body) == 0:
+			// 撞死或离开：Alive 为 false 即表示身体清空
+		case len(body)-1 <= len(old.Body) && slices.Equal(body[1:], old.Body[:len(body)-1]):
+			sd.Head, sd.Cut = &body[0], len(old.Body)-(len(body)-1)
+		default:
+			sd.Body = body
+		}
+		d.Snakes = append(d.Snakes, sd)
+	}
+	if !slices.Equal(prev.Foods, cur.Foods) {
+		d.Foods = cur.Foods
+	}
+	if !slices.Equal(prev.Hazards, cur.Hazards) {
+		d.Hazards = cur.Hazards
+	}
+	return d
+}
+
+// apply 把变化应用到 f 上
+func (f *Frame) apply(d Delta) {
+	f.Seq = d.Seq
+	for _, sd := range d.Snakes {
+		for sd.ID >= len(f.Snakes) {
+			f.Snakes = append(f.Snakes, Snake{})
+		}
+		sn := &f.Snakes[sd.ID]
+		if sd.Name != "" {
+			sn.Name = sd.Name
+		}
+		switch {
+		case !sd.Alive:
+			sn.Body = nil
+		case sd.Body != nil:
+			sn.Body = slices.Clone(sd.Body)
+		case sd.Head != nil:
+			keep := sn.Body[:max(len(sn.Body)-sd.Cut, 0)]
+			sn.Body = append([]snake.Point{*sd.Head}, keep...)
+		}
+		sn.Direction, sn.Length, sn.Score, sn.Alive, sn.Gone = sd.Dir, sd.Length, sd.Score, sd.Alive, sd.Gone
+	}
+	if d.Foods != nil {
+		f.Foods = slices.Clone(d.Foods)
+	}
+	if d.Hazards != nil {
+		f.Hazards = slices.Clone(d.Hazards)
+	}
+}
+
+// ============================================
+// 方向名称
+// ============================================
+
+// dirNames 协议中的方向名称，下标为 snake.Direction（与 env 的动作名一致）
+var dirNames = []string{"up", "down", "left", "right"}
+
+// parseDir 解析方向名称（不区分大小写）
+func parseDir(name string) (snake.Direction, error) {
+	for i, n := range dirNames {
+		if strings.EqualFold(n, name) {
+			return snake.Direction(i), nil
+		}
+	}
+	return snake.Up, fmt.Errorf("unknown direction %q (valid: %s)", name, strings.Join(dirNames, ", "))
+}
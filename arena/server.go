@@ -0,0 +1,352 @@
+package arena
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go-game/snake"
+)
+
+// ============================================
+// Server - 权威服务器
+// ============================================
+// 游戏状态只存在于服务器：客户端只发送转向请求，每一步的结果由服务器计算后广播。
+// 一个 goroutine 按固定间隔推进游戏，每个连接有自己的读写 goroutine，共享状态由 mu 保护
+
+const (
+	DefaultAddr       = ":7777" // 默认监听地址
+	DefaultMaxPlayers = 16      // 默认最多同时在线的玩家数
+
+	// outBuffer 每个客户端待发送消息的缓冲数，缓冲满（客户端跟不上）时断开连接
+	outBuffer = 64
+)
+
+// Config 服务器配置
+type Config struct {
+	Seed       int64         // 随机种子（食物和出生位置）
+	Wrap       bool          // 穿墙模式
+	Level      *snake.Level  // 面板尺寸和障碍物，nil 表示默认尺寸的空白面板
+	Tick       time.Duration // 移动间隔，0 表示 snake.SpeedNormal 毫秒
+	MaxPlayers int           // 最多同时在线的玩家数，0 表示 DefaultMaxPlayers
+	Log        io.Writer     // 加入、离开等事件的日志，nil 表示不输出
+}
+
+// Server 竞技场服务器
+type Server struct {
+	cfg Config
+
+	mu       sync.Mutex
+	game     *snake.Game
+	frame    Frame // 上一次广播时的状态，增量以它为基准，也是新玩家收到的完整状态
+	names    []string
+	gone     []bool
+	clients  map[*conn]bool
+	listener net.Listener
+	closed   bool
+}
+
+// conn 一个客户端连接
+type conn struct {
+	net.Conn
+	id  int         // 自己的蛇的下标，-1 表示还没有加入
+	out chan []byte // 待发送的消息（每条一行）
+}
+
+// NewServer 按配置创建服务器
+func NewServer(cfg Config) *Server {
+	if cfg.Tick <= 0 {
+		cfg.Tick = snake.SpeedNormal * time.Millisecond
+	}
+	if cfg.MaxPlayers <= 0 {
+		cfg.MaxPlayers = DefaultMaxPlayers
+	}
+	if cfg.Log == nil {
+		cfg.Log = io.Discard
+	}
+	s := &Server{
+		cfg:     cfg,
+		game:    snake.NewGame(snake.WithSeed(cfg.Seed), snake.WithWrap(cfg.Wrap), snake.WithArena(cfg.Level)),
+		clients: map[*conn]bool{},
+	}
+	s.frame = s.snapshot()
+	return s
+}
+
+// ListenAndServe 监听 addr 并开始服务，直到 Close
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve 在 l 上接受连接并推进游戏，直到 Close
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return nil
+	}
+	s.listener = l
+	s.mu.Unlock()
+	fmt.Fprintf(s.cfg.Log, "arena: listening on %s (%dx%d)\n", l.Addr(), s.frame.Width, s.frame.Height)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(s.cfg.Tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.step()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go s.handle(&conn{Conn: c, id: -1, out: make(chan []byte, outBuffer)})
+	}
+}
+
+// Close 停止服务并断开所有客户端
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for c := range s.clients {
+		c.Close()
+	}
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return nil
+}
+
+// snapshot 返回当前的完整状态（调用方持有 mu）
+func (s *Server) snapshot() Frame {
+	st := s.game.State()
+	f := Frame{
+		Seq:     s.frame.Seq,
+		Width:   st.Width,
+		Height:  st.Height,
+		Wrap:    st.Wrap,
+		Walls:   st.Walls,
+		Portals: st.Portals,
+		Hazards: st.Hazards,
+		Foods:   st.Foods,
+		Snakes:  []Snake{},
+	}
+	for id, sn := range st.Snakes {
+		f.Snakes = append(f.Snakes, Snake{
+			Name:      s.names[id],
+			Body:      sn.Body,
+			Direction: sn.Direction,
+			Length:    sn.Length,
+			Score:     sn.Score,
+			Alive:     sn.Alive,
+			Gone:      s.gone[id],
+		})
+	}
+	return f
+}
+
+// step 推进一步，向所有已加入的客户端广播变化并返回它
+func (s *Server) step() Delta {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.game.Step()
+	cur := s.snapshot()
+	cur.Seq++
+	d := diff(s.frame, cur)
+	s.frame = cur
+	s.broadcast(message{Type: "tick", Delta: &d})
+	return d
+}
+
+// broadcast 把消息放入每个已加入客户端的发送队列，跟不上的客户端被断开（调用方持有 mu）
+func (s *Server) broadcast(m message) {
+	line := encode(m)
+	for c := range s.clients {
+		if c.id >= 0 {
+			s.send(c, line)
+		}
+	}
+}
+
+// send 把一行消息放入已加入的客户端的发送队列，队列已满时断开连接（调用方持有 mu）
+// 不能阻塞：持有 mu 时等待一个不读取的客户端会卡住所有玩家
+func (s *Server) send(c *conn, line []byte) {
+	select {
+	case c.out <- line:
+	default:
+		fmt.Fprintf(s.cfg.Log, "arena: %s is too slow, disconnecting\n", s.names[c.id])
+		c.Close()
+	}
+}
+
+// encode 把消息编码为一行 JSON
+func encode(m message) []byte {
+	line, err := json.Marshal(m)
+	if err != nil {
+		line, _ = json.Marshal(message{Type: "error", Error: err.Error()})
+	}
+	return append(line, '\n')
+}
+
+// handle 处理一个客户端连接，直到连接断开或客户端离开
+func (s *Server) handle(c *conn) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		c.Close()
+		return
+	}
+	s.clients[c] = true
+	s.mu.Unlock()
+
+	go func() {
+		for line := range c.out {
+			if _, err := c.Write(line); err != nil {
+				c.Close()
+				return
+			}
+		}
+	}()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		if c.id >= 0 {
+			s.remove(c.id)
+		}
+		close(c.out)
+		s.mu.Unlock()
+		c.Close()
+	}()
+
+	scanner := bufio.NewScanner(c)
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			s.reply(c, message{Type: "error", Error: "invalid request: " + err.Error()})
+			continue
+		}
+		if req.Cmd == "leave" {
+			return
+		}
+		if err := s.do(c, req); err != nil {
+			s.reply(c, message{Type: "error", Error: err.Error()})
+		}
+	}
+}
+
+// reply 向一个客户端发送消息
+func (s *Server) reply(c *conn, m message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case c.out <- encode(m):
+	default:
+	}
+}
+
+// do 执行一个请求
+func (s *Server) do(c *conn, req request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.Cmd != "join" && c.id < 0 {
+		return errors.New("join first")
+	}
+	switch req.Cmd {
+	case "join":
+		if c.id >= 0 {
+			return errors.New("already joined")
+		}
+		return s.join(c, req.Name)
+	case "turn":
+		d, err := parseDir(req.Dir)
+		if err != nil {
+			return err
+		}
+		s.game.TurnPlayer(c.id, d)
+	case "respawn":
+		s.game.Respawn(c.id)
+	default:
+		return fmt.Errorf("unknown command %q", req.Cmd)
+	}
+	return nil
+}
+
+// add 在竞技场中加入一条名为 name 的蛇，返回它的下标（调用方持有 mu）
+func (s *Server) add(name string) (int, error) {
+	if name == "" {
+		name = "player"
+	}
+	id := s.game.Join()
+	if id < 0 {
+		return -1, errors.New("no free space on the board, try again later")
+	}
+	if id < len(s.names) {
+		// 重新使用离开的蛇留下的下标
+		s.names[id], s.gone[id] = name, false
+	} else {
+		s.names = append(s.names, name)
+		s.gone = append(s.gone, false)
+	}
+	return id, nil
+}
+
+// remove 让第 id 条蛇离开竞技场（调用方持有 mu）
+func (s *Server) remove(id int) {
+	s.game.Leave(id)
+	s.gone[id] = true
+	fmt.Fprintf(s.cfg.Log, "arena: %s left\n", s.names[id])
+}
+
+// join 为客户端加入一条新蛇并发送欢迎消息（调用方持有 mu）
+func (s *Server) join(c *conn, name string) error {
+	online := 0
+	for other := range s.clients {
+		if other.id >= 0 {
+			online++
+		}
+	}
+	if online >= s.cfg.MaxPlayers {
+		return fmt.Errorf("arena is full (%d players)", s.cfg.MaxPlayers)
+	}
+	id, err := s.add(name)
+	if err != nil {
+		return err
+	}
+	c.id = id
+	fmt.Fprintf(s.cfg.Log, "arena: %s joined from %s\n", s.names[id], c.RemoteAddr())
+
+	// 新蛇从下一个 tick 开始出现，欢迎消息中是上一次广播时的状态
+	s.send(c, encode(message{Type: "welcome", Welcome: &Welcome{
+		ID:    id,
+		Tick:  int(s.cfg.Tick / time.Millisecond),
+		Frame: s.frame.clone(),
+	}}))
+	return nil
+}
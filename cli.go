@@ -0,0 +1,641 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/arena"
+	"go-game/daily"
+	"go-game/env"
+	"go-game/input"
+	"go-game/replay"
+	"go-game/scores"
+	snakepkg "go-game/snake"
+	tetrispkg "go-game/tetris"
+)
+
+// ============================================
+// 命令行接口
+// ============================================
+// 用法：
+//   go-game                         打开主菜单
+//   go-game tetris [flags]          直接开始俄罗斯方块
+//   go-game snake [flags]           直接开始贪吃蛇
+//   go-game scores [game]           打印高分榜
+//   go-game daily [game]            打印每日挑战的历史成绩
+//   go-game fumen [--color] FUMEN   在终端中显示 fumen 面板
+//   go-game replay FILE             回放录像
+//   go-game env snake|tetris        通过 stdin/stdout 提供强化学习环境
+//   go-game arena serve|join|bot    多人贪吃蛇竞技场（TCP）
+//   go-game --version / --help
+
+const usageText = `Usage:
+  go-game                      open the main menu
+  go-game <command> [flags]
+
+Commands:
+  tetris    play Tetris directly
+  snake     play Snake directly
+  scores    print the high-score tables
+  daily     print the daily challenge results
+  fumen     render a Tetris fumen board in the terminal
+  replay    play back a recorded game
+  env       serve a reinforcement-learning environment over stdin/stdout
+  arena     host or join a multiplayer Snake arena over TCP
+
+Flags:
+  -h, --help       show this help
+  -v, --version    print the version
+
+Run 'go-game <command> --help' for the flags of a command.
+`
+
+// usageError 命令行用法错误，main 会额外提示查看帮助并以状态码 2 退出
+type usageError struct {
+	command string // 出错的子命令（附带结尾空格），顶层为空
+	msg     string
+}
+
+func (e usageError) Error() string { return e.msg }
+
+// newUsageError 创建用法错误
+func newUsageError(command, format string, args ...any) usageError {
+	if command != "" {
+		command += " "
+	}
+	return usageError{command: command, msg: fmt.Sprintf(format, args...)}
+}
+
+// runCLI 解析命令行参数并执行对应命令
+func runCLI(args []string) error {
+	if len(args) == 0 {
+		return withScreen(runMenu)
+	}
+
+	switch cmd, rest := args[0], args[1:]; cmd {
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usageText)
+		return nil
+	case "-v", "-version", "--version", "version":
+		fmt.Printf("go-game %s\n", version)
+		return nil
+	case "tetris":
+		return cmdTetris(rest)
+	case "snake":
+		return cmdSnake(rest)
+	case "scores":
+		return cmdScores(rest, os.Stdout)
+	case "daily":
+		return cmdDaily(rest, os.Stdout)
+	case "fumen":
+		return cmdFumen(rest, os.Stdout)
+	case "replay":
+		return cmdReplay(rest)
+	case "env":
+		return cmdEnv(rest)
+	case "arena":
+		return cmdArena(rest)
+	default:
+		if strings.HasPrefix(cmd, "-") {
+			return newUsageError("", "unknown flag %s", cmd)
+		}
+		return newUsageError("", "unknown command %q", cmd)
+	}
+}
+
+// command 子命令的参数解析器
+type command struct {
+	*flag.FlagSet
+	synopsis string // 用法摘要，--help 时显示
+}
+
+// newCommand 创建子命令的参数解析器
+// 解析错误由 parse 统一包装，因此这里关闭 flag 包默认的错误输出
+func newCommand(name, synopsis string) *command {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	return &command{FlagSet: fs, synopsis: synopsis}
+}
+
+// parse 解析参数，--help 时打印用法并返回 flag.ErrHelp
+func (c *command) parse(args []string) error {
+	err := c.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		c.printUsage()
+		return err
+	}
+	if err != nil {
+		return newUsageError(c.Name(), "%s: %v", c.Name(), err)
+	}
+	return nil
+}
+
+// isSet 报告命令行中是否给出了参数 name（而不是使用默认值）
+func (c *command) isSet(name string) bool {
+	set := false
+	c.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// printUsage 打印子命令的用法和参数说明
+func (c *command) printUsage() {
+	fmt.Printf("Usage:\n  go-game %s\n", c.synopsis)
+
+	n := 0
+	c.VisitAll(func(*flag.Flag) { n++ })
+	if n == 0 {
+		return
+	}
+	fmt.Printf("\nFlags:\n")
+	c.SetOutput(os.Stdout)
+	c.PrintDefaults()
+	c.SetOutput(io.Discard)
+}
+
+// ============================================
+// 子命令
+// ============================================
+
+// cmdTetris go-game tetris [--mode marathon|sprint] [--seed N] [--daily] [--puzzle NAME] [--practice] [--record FILE]
+func cmdTetris(args []string) error {
+	cmd := newCommand("tetris", "tetris [--mode marathon|sprint] [--seed N] [--daily] [--puzzle NAME] [--practice] [--record FILE]")
+	mode := cmd.String("mode", "marathon", "game mode: marathon or sprint (clear 40 lines)")
+	seed := cmd.Int64("seed", 0, "random seed for the piece sequence (0 = random)")
+	dailyFlag := cmd.Bool("daily", false, "play today's daily challenge (marathon with the piece sequence of the day)")
+	puzzle := cmd.String("puzzle", "", "play the puzzle `NAME` (see the puzzle list in the menu)")
+	practice := cmd.Bool("practice", false, "practice without gravity: undo and redo every placement (not scored)")
+	record := cmd.String("record", "", "save a replay of the session to `FILE`")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() > 0 {
+		return newUsageError("tetris", "tetris: unexpected argument %q", cmd.Arg(0))
+	}
+	m, err := tetrispkg.ParseMode(*mode)
+	if err != nil {
+		return newUsageError("tetris", "tetris: %v", err)
+	}
+
+	if *dailyFlag && (*seed != 0 || m != tetrispkg.ModeMarathon) {
+		return newUsageError("tetris", "tetris: --daily cannot be combined with --seed or --mode")
+	}
+	if *puzzle != "" {
+		if *dailyFlag || *seed != 0 || m != tetrispkg.ModeMarathon {
+			return newUsageError("tetris", "tetris: --puzzle cannot be combined with --daily, --seed or --mode")
+		}
+		if _, err := tetrispkg.FindPuzzle(*puzzle); err != nil {
+			return newUsageError("tetris", "tetris: %v", err)
+		}
+	}
+	if *practice && (*dailyFlag || *puzzle != "" || m != tetrispkg.ModeMarathon) {
+		return newUsageError("tetris", "tetris: --practice cannot be combined with --daily, --puzzle or --mode")
+	}
+
+	cfg := tetrispkg.Config{Mode: m, Seed: *seed, Puzzle: *puzzle, Practice: *practice, Record: *record}
+	if *dailyFlag {
+		cfg.Daily = daily.Today()
+	}
+	return withScreen(func(screen tcell.Screen, bindings *input.Bindings) error {
+		return tetrispkg.Run(screen, bindings.Tetris, cfg)
+	})
+}
+
+// cmdSnake go-game snake [--wrap] [--size SIZE] [--difficulty NAME] [--constant] [--mode NAME] [--target N] [--level NAME] [--players N] [--opponents N] [--shrink SECONDS] [--autopilot NAME] [--seed N] [--daily] [--record FILE] [--heatmap FILE]
+func cmdSnake(args []string) error {
+	cmd := newCommand("snake", "snake [--wrap] [--size SIZE] [--difficulty NAME] [--constant] [--mode NAME] [--target N] [--level NAME] [--players N] [--opponents N] [--shrink SECONDS] [--autopilot NAME] [--seed N] [--daily] [--record FILE] [--heatmap FILE]")
+	wrap := cmd.Bool("wrap", false, "borderless mode: leaving one edge enters from the opposite edge")
+	size := cmd.String("size", "", "board `SIZE` of open boards: "+strings.Join(snakepkg.BoardSizeNames(), ", ")+", auto (fit the terminal) or WxH")
+	difficulty := cmd.String("difficulty", "", "speed curve `NAME`: "+strings.Join(snakepkg.DifficultyNames(), ", ")+" (default normal)")
+	constant := cmd.Bool("constant", false, "keep the starting speed of the difficulty instead of speeding up")
+	mode := cmd.String("mode", "", "single-player challenge `NAME`: endless, time-attack (score in 60s) or length (race to --target)")
+	target := cmd.Int("target", snakepkg.DefaultLengthTarget, "target length `N` of the length challenge")
+	level := cmd.String("level", "", "play the level called `NAME`, or \"campaign\" for all levels in order")
+	players := cmd.Int("players", 1, "number of players on one keyboard (2 = arrows vs WASD)")
+	opponents := cmd.Int("opponents", 0, "number of computer-controlled snakes")
+	shrink := cmd.Int("shrink", 0, "battle royale: shrink the board by one ring every `SECONDS` (needs 2+ snakes)")
+	autopilot := cmd.String("autopilot", "", "let the computer play player 1 (`NAME`: "+strings.Join(snakepkg.ControllerNames(), ", ")+")")
+	seed := cmd.Int64("seed", 0, "random seed for food placement (0 = random)")
+	dailyFlag := cmd.Bool("daily", false, "play today's daily challenge (classic single player with the food sequence of the day; only --record and --heatmap can be added)")
+	record := cmd.String("record", "", "save a replay of the session to `FILE`")
+	heatmap := cmd.String("heatmap", "", "on exit, write the head heatmap of the last game to `FILE` as CSV")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() > 0 {
+		return newUsageError("snake", "snake: unexpected argument %q", cmd.Arg(0))
+	}
+	if *dailyFlag {
+		// 每日挑战的设置对所有人都相同，只能附加录像和热力图
+		var conflicts []string
+		cmd.Visit(func(f *flag.Flag) {
+			if f.Name != "daily" && f.Name != "record" && f.Name != "heatmap" {
+				conflicts = append(conflicts, "--"+f.Name)
+			}
+		})
+		if len(conflicts) > 0 {
+			return newUsageError("snake", "snake: --daily cannot be combined with %s", strings.Join(conflicts, ", "))
+		}
+	}
+	if *players < 1 || *players > snakepkg.MaxPlayers {
+		return newUsageError("snake", "snake: --players must be between 1 and %d", snakepkg.MaxPlayers)
+	}
+	if *opponents < 0 || *players+*opponents > snakepkg.MaxSnakes {
+		return newUsageError("snake", "snake: at most %d snakes (players + opponents)", snakepkg.MaxSnakes)
+	}
+	if *shrink < 0 {
+		return newUsageError("snake", "snake: --shrink needs a positive number of seconds")
+	}
+	if *autopilot != "" && !slices.Contains(snakepkg.ControllerNames(), *autopilot) {
+		return newUsageError("snake", "snake: unknown autopilot %q", *autopilot)
+	}
+
+	if _, err := snakepkg.FindLevels(*level); err != nil {
+		return newUsageError("snake", "snake: %v", err)
+	}
+	if _, _, err := snakepkg.ParseBoardSize(*size, 0, 0); err != nil {
+		return newUsageError("snake", "snake: %v", err)
+	}
+	if _, err := snakepkg.ParseDifficulty(*difficulty, *constant); err != nil {
+		return newUsageError("snake", "snake: %v", err)
+	}
+	if m, err := snakepkg.ParseMode(*mode); err != nil {
+		return newUsageError("snake", "snake: %v", err)
+	} else if cmd.isSet("target") {
+		if m != snakepkg.ModeLength {
+			return newUsageError("snake", "snake: --target needs --mode length")
+		}
+		if err := snakepkg.CheckTarget(*target); err != nil {
+			return newUsageError("snake", "snake: --target: %v", err)
+		}
+	}
+
+	cfg := snakepkg.Config{
+		Wrap: *wrap, Size: *size, Difficulty: *difficulty, Constant: *constant, Mode: *mode, Target: *target,
+		Level: *level, Players: *players, Opponents: *opponents, Shrink: *shrink, Autopilot: *autopilot,
+		Seed: *seed, Record: *record, Heatmap: *heatmap,
+	}
+	// 选项之间的组合与选项菜单使用同一个检查
+	if err := cfg.Validate(); err != nil {
+		return newUsageError("snake", "snake: %v", err)
+	}
+	if *dailyFlag {
+		cfg.Daily = daily.Today()
+	}
+	return withScreen(func(screen tcell.Screen, bindings *input.Bindings) error {
+		return snakepkg.Run(screen, bindings.Snake, cfg)
+	})
+}
+
+// cmdScores go-game scores [TABLE]
+// 不指定表名时打印所有高分榜
+func cmdScores(args []string, w io.Writer) error {
+	cmd := newCommand("scores", "scores [TABLE]")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() > 1 {
+		return newUsageError("scores", "scores: expected at most one table name")
+	}
+
+	tables, err := scores.Load()
+	if err != nil {
+		return err
+	}
+	if cmd.NArg() == 1 {
+		var matched []*scores.Table
+		for _, t := range tables {
+			if t.Name == cmd.Arg(0) || strings.HasPrefix(t.Name, cmd.Arg(0)+"-") {
+				matched = append(matched, t)
+			}
+		}
+		if len(matched) == 0 {
+			return fmt.Errorf("no scores recorded for %q", cmd.Arg(0))
+		}
+		tables = matched
+	}
+	if len(tables) == 0 {
+		fmt.Fprintln(w, "No scores recorded yet.")
+		return nil
+	}
+
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\n", strings.ToUpper(t.Name))
+		for rank, e := range t.Entries {
+			fmt.Fprintf(w, "%3d. %-10s %-20s %s  seed %d\n",
+				rank+1, e.Label, e.Detail, e.Date.Format("2006-01-02 15:04"), e.Seed)
+		}
+	}
+	return nil
+}
+
+// cmdDaily go-game daily [GAME]
+// 不指定游戏时打印所有游戏的每日成绩
+func cmdDaily(args []string, w io.Writer) error {
+	cmd := newCommand("daily", "daily [tetris|snake]")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() > 1 {
+		return newUsageError("daily", "daily: expected at most one game name")
+	}
+
+	games := dailyGames
+	if cmd.NArg() == 1 {
+		games = nil
+		for _, g := range dailyGames {
+			if g.name == cmd.Arg(0) {
+				games = append(games, g)
+			}
+		}
+		if len(games) == 0 {
+			return newUsageError("daily", "daily: unknown game %q (valid: tetris, snake)", cmd.Arg(0))
+		}
+	}
+
+	fmt.Fprintf(w, "Today's challenge: %s (UTC)\n", daily.Today())
+	for _, g := range games {
+		results, err := daily.History(g.name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n%s\n", g.title)
+		if len(results) == 0 {
+			fmt.Fprintln(w, "  No results yet.")
+		}
+		for _, r := range results {
+			fmt.Fprintf(w, "  %s  %-10s %s\n", r.Date, r.Label, r.Detail)
+		}
+	}
+	return nil
+}
+
+// cmdFumen go-game fumen [--color] FUMEN
+// 打印 fumen 第一页的面板（当前方块为小写字母）和注释
+func cmdFumen(args []string, w io.Writer) error {
+	cmd := newCommand("fumen", "fumen [--color] FUMEN")
+	color := cmd.Bool("color", false, "draw colored blocks with ANSI escape codes instead of letters")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() != 1 {
+		return newUsageError("fumen", "fumen: expected one fumen string (v115@...)")
+	}
+	f, err := tetrispkg.DecodeFumen(cmd.Arg(0))
+	if err != nil {
+		return newUsageError("fumen", "fumen: %v", err)
+	}
+	if f.Comment != "" {
+		fmt.Fprintln(w, f.Comment)
+	}
+	fmt.Fprint(w, f.Text(*color))
+	return nil
+}
+
+// cmdReplay go-game replay FILE
+func cmdReplay(args []string) error {
+	cmd := newCommand("replay", "replay FILE")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() != 1 {
+		return newUsageError("replay", "replay: expected exactly one replay file")
+	}
+
+	rp, err := replay.Load(cmd.Arg(0))
+	if err != nil {
+		return err
+	}
+	return withScreen(func(screen tcell.Screen, bindings *input.Bindings) error {
+		switch rp.Game {
+		case "tetris":
+			return tetrispkg.Replay(screen, bindings.Tetris, rp)
+		case "snake":
+			return snakepkg.Replay(screen, bindings.Snake, rp)
+		default:
+			return fmt.Errorf("%s: unknown game %q", cmd.Arg(0), rp.Game)
+		}
+	})
+}
+
+// cmdEnv go-game env snake|tetris
+// 协议说明见 env 包
+func cmdEnv(args []string) error {
+	cmd := newCommand("env", "env snake|tetris\n\nSpeaks line-delimited JSON on stdin/stdout:\n"+
+		`  {"cmd":"spec"}`+"\n"+
+		`  {"cmd":"reset","seed":42,"config":{"observation":"grid","reward":{"food":1}}}`+"\n"+
+		`  {"cmd":"step","action":"left"}`+"\n"+
+		`  {"cmd":"close"}`)
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() != 1 {
+		return newUsageError("env", "env: expected a game name (snake or tetris)")
+	}
+
+	e, err := env.New(cmd.Arg(0))
+	if err != nil {
+		return newUsageError("env", "env: %v", err)
+	}
+	return env.Serve(e, os.Stdin, os.Stdout)
+}
+
+// cmdArena go-game arena serve|join|bot [flags]
+func cmdArena(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "-help") {
+			fmt.Print("Usage:\n  go-game arena serve [flags]      host an arena\n" +
+				"  go-game arena join [flags] ADDR  play in a terminal\n" +
+				"  go-game arena bot [flags] ADDR   connect computer players\n")
+			return nil
+		}
+		return newUsageError("arena", "arena: expected serve, join or bot")
+	}
+	switch sub, rest := args[0], args[1:]; sub {
+	case "serve":
+		return cmdArenaServe(rest)
+	case "join":
+		return cmdArenaJoin(rest)
+	case "bot":
+		return cmdArenaBot(rest)
+	default:
+		return newUsageError("arena", "arena: unknown subcommand %q (expected serve, join or bot)", sub)
+	}
+}
+
+// cmdArenaServe go-game arena serve [--addr ADDR] [--size WxH] [--level NAME] [--wrap] [--tick MS] [--max-players N] [--seed N]
+func cmdArenaServe(args []string) error {
+	cmd := newCommand("arena serve", "arena serve [--addr ADDR] [--size WxH] [--level NAME] [--wrap] [--tick MS] [--max-players N] [--seed N]")
+	addr := cmd.String("addr", arena.DefaultAddr, "listen on `ADDR`")
+	size := cmd.String("size", "40x20", "board size `WxH` of the open arena")
+	level := cmd.String("level", "", "use the board and walls of the level called `NAME` (overrides --size)")
+	wrap := cmd.Bool("wrap", false, "borderless mode: leaving one edge enters from the opposite edge")
+	tick := cmd.Int("tick", snakepkg.SpeedNormal, "milliseconds per move")
+	maxPlayers := cmd.Int("max-players", arena.DefaultMaxPlayers, "maximum number of players online at once")
+	seed := cmd.Int64("seed", 0, "random seed for food and spawn points (0 = random)")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	if cmd.NArg() > 0 {
+		return newUsageError("arena serve", "arena serve: unexpected argument %q", cmd.Arg(0))
+	}
+	if *tick < 10 {
+		return newUsageError("arena serve", "arena serve: --tick must be at least 10")
+	}
+
+	lvl, err := arenaLevel(*size, *level)
+	if err != nil {
+		return newUsageError("arena serve", "arena serve: %v", err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	server := arena.NewServer(arena.Config{
+		Seed:       *seed,
+		Wrap:       *wrap,
+		Level:      lvl,
+		Tick:       time.Duration(*tick) * time.Millisecond,
+		MaxPlayers: *maxPlayers,
+		Log:        os.Stderr,
+	})
+	return server.ListenAndServe(*addr)
+}
+
+// arenaLevel 根据 --size 和 --level 返回竞技场的面板
+func arenaLevel(size, name string) (*snakepkg.Level, error) {
+	if name != "" {
+		levels, err := snakepkg.FindLevels(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(levels) != 1 {
+			return nil, fmt.Errorf("--level takes a single level, not %q", name)
+		}
+		return levels[0], nil
+	}
+	var w, h int
+	if _, err := fmt.Sscanf(size, "%dx%d", &w, &h); err != nil || w < 10 || h < 10 || w > 100 || h > 60 {
+		return nil, fmt.Errorf("invalid --size %q (WxH, from 10x10 to 100x60)", size)
+	}
+	return &snakepkg.Level{Name: "Arena", Width: w, Height: h}, nil
+}
+
+// cmdArenaJoin go-game arena join [--name NAME] [ADDR]
+func cmdArenaJoin(args []string) error {
+	cmd := newCommand("arena join", "arena join [--name NAME] [ADDR]")
+	name := cmd.String("name", defaultPlayerName(), "player `NAME` shown to the others")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	addr, err := arenaAddr(cmd)
+	if err != nil {
+		return err
+	}
+
+	c, err := arena.Dial(addr, *name)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return withScreen(func(screen tcell.Screen, bindings *input.Bindings) error {
+		return arena.Play(screen, bindings.Snake, c)
+	})
+}
+
+// cmdArenaBot go-game arena bot [--name NAME] [--ai NAME] [--count N] [ADDR]
+func cmdArenaBot(args []string) error {
+	cmd := newCommand("arena bot", "arena bot [--name NAME] [--ai NAME] [--count N] [ADDR]")
+	name := cmd.String("name", "bot", "player `NAME` (numbered when --count > 1)")
+	ai := cmd.String("ai", snakepkg.OpponentController, "controller `NAME`: "+strings.Join(snakepkg.ControllerNames(), ", "))
+	count := cmd.Int("count", 1, "number of bots to connect")
+	if err := cmd.parse(args); err != nil {
+		return ignoreHelp(err)
+	}
+	addr, err := arenaAddr(cmd)
+	if err != nil {
+		return err
+	}
+	if *count < 1 {
+		return newUsageError("arena bot", "arena bot: --count must be at least 1")
+	}
+	// 每个机器人一个控制器，连接服务器之前先检查 --ai
+	ctrls := make([]snakepkg.Controller, *count)
+	for i := range ctrls {
+		ctrl, err := snakepkg.NewController(*ai)
+		if err != nil {
+			return newUsageError("arena bot", "arena bot: unknown controller %q", *ai)
+		}
+		ctrls[i] = ctrl
+	}
+
+	// 全部连接成功后才开始运行，任意一个连接失败时关闭已经建立的连接
+	clients := make([]*arena.Client, 0, *count)
+	for i := 1; i <= *count; i++ {
+		botName := *name
+		if *count > 1 {
+			botName = fmt.Sprintf("%s-%d", *name, i)
+		}
+		c, err := arena.Dial(addr, botName)
+		if err != nil {
+			for _, c := range clients {
+				c.Close()
+			}
+			return err
+		}
+		clients = append(clients, c)
+	}
+
+	// 任意一个机器人出错时返回第一个错误
+	errc := make(chan error, *count)
+	for i, c := range clients {
+		go func() { errc <- arena.RunBot(c, ctrls[i]) }()
+	}
+	for range *count {
+		if err := <-errc; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arenaAddr 返回命令行中的服务器地址（可选的唯一参数），默认为本机
+func arenaAddr(cmd *command) (string, error) {
+	switch cmd.NArg() {
+	case 0:
+		return "localhost" + arena.DefaultAddr, nil
+	case 1:
+		return cmd.Arg(0), nil
+	default:
+		return "", newUsageError(cmd.Name(), "%s: expected at most one address", cmd.Name())
+	}
+}
+
+// defaultPlayerName 默认的玩家名称：当前用户名
+func defaultPlayerName() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "player"
+}
+
+// ignoreHelp --help 已打印用法，不算错误
+func ignoreHelp(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go-game/internal/store"
+)
+
+// TestCLIErrors 错误的命令行参数在打开终端之前就返回用法错误，错误信息指出问题所在
+func TestCLIErrors(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	tests := []struct {
+		name    string
+		args    []string
+		command string // usageError.command，决定提示查看哪个命令的 --help
+		want    string
+	}{
+		{"unknown command", []string{"chess"}, "", `unknown command "chess"`},
+		{"unknown flag", []string{"--fast"}, "", "unknown flag --fast"},
+		{"unknown subcommand flag", []string{"tetris", "--speed", "3"}, "tetris ", "tetris: flag provided but not defined: -speed"},
+		{"bad flag value", []string{"tetris", "--seed", "abc"}, "tetris ", `tetris: invalid value "abc" for flag -seed`},
+		{"bad mode", []string{"tetris", "--mode", "zen"}, "tetris ", `unknown mode "zen"`},
+		{"extra argument", []string{"tetris", "now"}, "tetris ", `tetris: unexpected argument "now"`},
+		{"tetris daily with seed", []string{"tetris", "--daily", "--seed", "7"}, "tetris ", "tetris: --daily cannot be combined with --seed or --mode"},
+		{"tetris puzzle with mode", []string{"tetris", "--puzzle", "T-Spin Double", "--mode", "sprint"}, "tetris ", "tetris: --puzzle cannot be combined with --daily, --seed or --mode"},
+		{"unknown puzzle", []string{"tetris", "--puzzle", "nope"}, "tetris ", `tetris: unknown puzzle "nope"`},
+		{"tetris practice with daily", []string{"tetris", "--practice", "--daily"}, "tetris ", "tetris: --practice cannot be combined with --daily, --puzzle or --mode"},
+		{"snake daily with seed", []string{"snake", "--daily", "--seed", "7"}, "snake ", "snake: --daily cannot be combined with --seed"},
+		{"snake daily with options", []string{"snake", "--daily", "--record", "a.json", "--wrap", "--size", "large"}, "snake ", "snake: --daily cannot be combined with --size, --wrap"},
+		{"too many players", []string{"snake", "--players", "5"}, "snake ", "snake: --players must be between 1 and"},
+		{"too many snakes", []string{"snake", "--opponents", "20"}, "snake ", "snake: at most"},
+		{"negative shrink", []string{"snake", "--shrink", "-1", "--opponents", "1"}, "snake ", "snake: --shrink needs a positive number of seconds"},
+		{"shrink alone", []string{"snake", "--shrink", "10"}, "snake ", "snake: battle royale needs at least 2 snakes"},
+		{"level with opponents", []string{"snake", "--level", "campaign", "--opponents", "1"}, "snake ", `snake: level "campaign" is single-player`},
+		{"unknown autopilot", []string{"snake", "--autopilot", "magic"}, "snake ", `snake: unknown autopilot "magic"`},
+		{"bad size", []string{"snake", "--size", "40by20"}, "snake ", `snake: invalid board size "40by20"`},
+		{"challenge with players", []string{"snake", "--mode", "time-attack", "--players", "2"}, "snake ", "snake: mode time-attack is a single-player challenge on an open board"},
+		{"target without mode", []string{"snake", "--target", "20"}, "snake ", "snake: --target needs --mode length"},
+		{"target with other mode", []string{"snake", "--mode", "time-attack", "--target", "20"}, "snake ", "snake: --target needs --mode length"},
+		{"target too short", []string{"snake", "--mode", "length", "--target", "3"}, "snake ", "snake: --target: target 3 must be longer than the starting length 3"},
+		{"scores extra", []string{"scores", "a", "b"}, "scores ", "scores: expected at most one table name"},
+		{"daily unknown game", []string{"daily", "chess"}, "daily ", `daily: unknown game "chess" (valid: tetris, snake)`},
+		{"fumen missing", []string{"fumen"}, "fumen ", "fumen: expected one fumen string (v115@...)"},
+		{"fumen invalid", []string{"fumen", "vhAAgH"}, "fumen ", "fumen: not a v115 fumen"},
+		{"replay missing", []string{"replay"}, "replay ", "replay: expected exactly one replay file"},
+		{"env unknown game", []string{"env", "chess"}, "env ", "env: "},
+		{"arena missing", []string{"arena"}, "arena ", "arena: expected serve, join or bot"},
+		{"arena unknown", []string{"arena", "watch"}, "arena ", `arena: unknown subcommand "watch" (expected serve, join or bot)`},
+		{"arena tick", []string{"arena", "serve", "--tick", "1"}, "arena serve ", "arena serve: --tick must be at least 10"},
+		{"arena size", []string{"arena", "serve", "--size", "5x5"}, "arena serve ", `arena serve: invalid --size "5x5" (WxH, from 10x10 to 100x60)`},
+		{"arena bot unknown ai", []string{"arena", "bot", "--ai", "magic"}, "arena bot ", `arena bot: unknown controller "magic"`},
+		{"arena bot count", []string{"arena", "bot", "--count", "0"}, "arena bot ", "arena bot: --count must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCLI(tt.args)
+			var usage usageError
+			if !errors.As(err, &usage) {
+				t.Fatalf("runCLI(%q) = %v, want a usage error", tt.args, err)
+			}
+			if usage.command != tt.command {
+				t.Errorf("command = %q, want %q", usage.command, tt.command)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/daily"
+	"go-game/input"
+	snakepkg "go-game/snake"
+	tetrispkg "go-game/tetris"
+)
+
+// ============================================
+// DailyScreen - 每日挑战
+// ============================================
+// 列出两个游戏今天的挑战和最近几天的成绩，选择游戏后开始当天的挑战
+
+// dailyGames 每日挑战中的游戏：历史记录中的名称和显示的标题
+var dailyGames = []struct {
+	name  string
+	title string
+}{
+	{tetrispkg.DailyGame, "TETRIS"},
+	{snakepkg.DailyGame, "SNAKE"},
+}
+
+// dailyHistoryDays 界面上显示的最近天数
+const dailyHistoryDays = 7
+
+type DailyScreen struct {
+	screen   tcell.Screen
+	bindings *input.Bindings           // 界面导航使用的按键
+	date     string                    // 今天的挑战日期
+	selected int                       // 选中的游戏（dailyGames 的下标）
+	history  map[string][]daily.Result // 各游戏的每日成绩，从新到旧
+	err      error                     // 读取存档失败的原因，界面上会显示出来
+}
+
+// NewDailyScreen 读取每日成绩并创建 date 这天的挑战界面
+func NewDailyScreen(screen tcell.Screen, bindings *input.Bindings, date string) *DailyScreen {
+	d := &DailyScreen{screen: screen, bindings: bindings, date: date, history: map[string][]daily.Result{}}
+	for _, g := range dailyGames {
+		results, err := daily.History(g.name)
+		if err != nil {
+			d.err = err
+		}
+		d.history[g.name] = results
+	}
+	return d
+}
+
+// result 返回游戏 game 在 date 这天的成绩
+func (d *DailyScreen) result(game, date string) (daily.Result, bool) {
+	for _, r := range d.history[game] {
+		if r.Date == date {
+			return r, true
+		}
+	}
+	return daily.Result{}, false
+}
+
+// dates 返回有成绩的日期，从新到旧，最多 dailyHistoryDays 天
+func (d *DailyScreen) dates() []string {
+	seen := map[string]bool{}
+	var dates []string
+	for _, results := range d.history {
+		for _, r := range results {
+			if !seen[r.Date] {
+				seen[r.Date] = true
+				dates = append(dates, r.Date)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+	return dates[:min(len(dates), dailyHistoryDays)]
+}
+
+// Render 绘制每日挑战：上方是今天的两个挑战，下方是最近几天的成绩
+func (d *DailyScreen) Render() {
+	d.screen.Clear()
+	d.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true)
+	normalStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorLime).Bold(true)
+	hintStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+
+	drawText(d.screen, 10, 3, "DAILY CHALLENGE", titleStyle)
+	drawText(d.screen, 10, 4, d.date+" (UTC)", hintStyle)
+
+	// 今天的挑战：已经有成绩时再玩都是练习
+	for i, g := range dailyGames {
+		status := "not played yet"
+		if r, ok := d.result(g.name, d.date); ok {
+			status = "today: " + r.Label + "  (practice only)"
+		}
+		marker, style := "  ", normalStyle
+		if i == d.selected {
+			marker, style = "► ", selectedStyle
+		}
+		drawText(d.screen, 8, 6+i*2, fmt.Sprintf("%s%-8s %s", marker, g.title, status), style)
+	}
+
+	// 最近几天的成绩
+	y := 11
+	drawText(d.screen, 8, y, "RECENT RESULTS", normalStyle)
+	header := fmt.Sprintf("%-12s", "DATE")
+	for _, g := range dailyGames {
+		header += fmt.Sprintf("%-10s", g.title)
+	}
+	drawText(d.screen, 8, y+2, header, hintStyle)
+	dates := d.dates()
+	for i, date := range dates {
+		row := fmt.Sprintf("%-12s", date)
+		for _, g := range dailyGames {
+			label := "--"
+			if r, ok := d.result(g.name, date); ok {
+				label = r.Label
+			}
+			row += fmt.Sprintf("%-10s", label)
+		}
+		drawText(d.screen, 8, y+3+i, row, normalStyle)
+	}
+	if len(dates) == 0 {
+		drawText(d.screen, 8, y+3, "None yet", hintStyle)
+	}
+	if d.err != nil {
+		drawText(d.screen, 8, y+4+dailyHistoryDays, "Could not read daily results: "+d.err.Error(), normalStyle)
+	}
+
+	hints := []string{
+		"The first game each day is scored, later games are practice",
+		"↑↓ : Select   Enter : Play   Esc : Back",
+	}
+	for i, hint := range hints {
+		drawText(d.screen, 8, 22+i, hint, hintStyle)
+	}
+
+	d.screen.Show()
+}
+
+// Run 显示界面直到玩家选择游戏或返回，返回所选游戏的名称（返回时 ok 为 false）
+func (d *DailyScreen) Run() (game string, ok bool) {
+	d.Render()
+	for {
+		switch ev := d.screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			if ev.Rune() == 'q' || ev.Rune() == 'Q' {
+				return "", false
+			}
+			switch d.bindings.Navigate(ev) {
+			case input.NavBack:
+				return "", false
+			case input.NavUp:
+				d.selected = max(d.selected-1, 0)
+				d.Render()
+			case input.NavDown:
+				d.selected = min(d.selected+1, len(dailyGames)-1)
+				d.Render()
+			case input.NavSelect:
+				return dailyGames[d.selected].name, true
+			}
+		case *tcell.EventResize:
+			d.Render()
+		case nil:
+			return "", false
+		}
+	}
+}
+
+// drawText 从 (x, y) 开始绘制一行文字
+func drawText(screen tcell.Screen, x, y int, text string, style tcell.Style) {
+	for i, ch := range []rune(text) {
+		screen.SetContent(x+i, y, ch, nil, style)
+	}
+}
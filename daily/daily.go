@@ -0,0 +1,104 @@
+package daily
+
+import (
+	"errors"
+	"hash/fnv"
+	"os"
+	"sort"
+	"time"
+
+	"go-game/internal/store"
+)
+
+// ============================================
+// 每日挑战 - 按日期决定种子
+// ============================================
+// 每个游戏每天有一个由日期算出的种子，同一天所有人拿到相同的食物/方块序列。
+// 每天只有第一局计入成绩（记入 daily.json 的历史），之后同一天再玩都是练习
+
+// historyFile 每日挑战历史在存档目录中的文件名
+const historyFile = "daily.json"
+
+// dateLayout 挑战日期的格式
+const dateLayout = "2006-01-02"
+
+// Date 返回 t 所在的挑战日期
+// 使用 UTC，保证不同时区的人在同一时刻玩的是同一个挑战
+func Date(t time.Time) string {
+	return t.UTC().Format(dateLayout)
+}
+
+// Today 返回今天的挑战日期
+func Today() string {
+	return Date(time.Now())
+}
+
+// Seed 返回游戏 game 在 date 这天的种子
+// 对 "game/date" 做 FNV-1a 哈希，不同游戏同一天的种子互不相关
+func Seed(game, date string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(game + "/" + date))
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+// Result 一天的成绩
+type Result struct {
+	Date   string `json:"date"`             // 挑战日期，例如 "2026-10-18"
+	Value  int64  `json:"value"`            // 成绩数值（得分）
+	Label  string `json:"label"`            // 数值的显示形式
+	Detail string `json:"detail,omitempty"` // 附加信息，例如 "LINES 12"
+}
+
+// ============================================
+// 读写
+// ============================================
+
+// History 读取游戏 game 的所有每日成绩，按日期从新到旧排列
+func History(game string) ([]Result, error) {
+	all, err := loadAll()
+	if err != nil {
+		return nil, err
+	}
+	results := all[game]
+	sort.Slice(results, func(i, j int) bool { return results[i].Date > results[j].Date })
+	return results, nil
+}
+
+// Get 读取游戏 game 在 date 这天的成绩，这天还没有成绩时 ok 为 false
+func Get(game, date string) (r Result, ok bool, err error) {
+	all, err := loadAll()
+	if err != nil {
+		return Result{}, false, err
+	}
+	for _, r := range all[game] {
+		if r.Date == date {
+			return r, true, nil
+		}
+	}
+	return Result{}, false, nil
+}
+
+// Record 记录游戏 game 一天的成绩
+// 这天已经有成绩时不覆盖（每天只有一次计分机会），返回 false
+func Record(game string, r Result) (bool, error) {
+	all, err := loadAll()
+	if err != nil {
+		return false, err
+	}
+	for _, old := range all[game] {
+		if old.Date == r.Date {
+			return false, nil
+		}
+	}
+	all[game] = append(all[game], r)
+	return true, store.Save(historyFile, all)
+}
+
+// loadAll 读取存档，文件不存在时返回空集合
+func loadAll() (map[string][]Result, error) {
+	all := map[string][]Result{}
+	if err := store.Load(historyFile, &all); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,83 @@
+package daily
+
+import (
+	"testing"
+	"time"
+
+	"go-game/internal/store"
+)
+
+// TestSeed 种子只取决于游戏和日期：改变哈希会让所有人当天的挑战和已有的录像对不上
+func TestSeed(t *testing.T) {
+	tests := []struct {
+		game, date string
+		want       int64
+	}{
+		{"snake", "2026-10-18", 2424789506353562094},
+		{"tetris", "2026-10-18", 3576962320106329707},
+	}
+	for _, tt := range tests {
+		if got := Seed(tt.game, tt.date); got != tt.want {
+			t.Errorf("Seed(%q, %q) = %d, want %d", tt.game, tt.date, got, tt.want)
+		}
+	}
+	if Seed("snake", "2026-10-19") == Seed("snake", "2026-10-18") {
+		t.Error("consecutive days should have different seeds")
+	}
+}
+
+// TestDate 挑战日期在 UTC 零点切换，与本地时区无关
+func TestDate(t *testing.T) {
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	newYork := time.FixedZone("UTC-4", -4*60*60)
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{"before utc midnight", time.Date(2026, 10, 18, 23, 59, 59, 0, time.UTC), "2026-10-18"},
+		{"at utc midnight", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), "2026-10-19"},
+		{"after local midnight east", time.Date(2026, 10, 19, 8, 59, 0, 0, tokyo), "2026-10-18"},
+		{"utc midnight east", time.Date(2026, 10, 19, 9, 0, 0, 0, tokyo), "2026-10-19"},
+		{"before local midnight west", time.Date(2026, 10, 18, 20, 0, 0, 0, newYork), "2026-10-19"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Date(tt.t); got != tt.want {
+				t.Errorf("Date(%v) = %q, want %q", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRecordKeepsFirst 每天只有第一局计分：同一天的第二个成绩不覆盖第一个
+func TestRecordKeepsFirst(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+
+	first := Result{Date: "2026-10-18", Value: 120, Label: "120"}
+	if ok, err := Record("snake", first); err != nil || !ok {
+		t.Fatalf("first Record = %v, %v, want true, nil", ok, err)
+	}
+	if ok, err := Record("snake", Result{Date: "2026-10-18", Value: 500, Label: "500"}); err != nil || ok {
+		t.Fatalf("second Record = %v, %v, want false, nil", ok, err)
+	}
+	if got, ok, err := Get("snake", "2026-10-18"); err != nil || !ok || got != first {
+		t.Errorf("Get = %+v, %v, %v, want %+v", got, ok, err, first)
+	}
+
+	// 其他日期和其他游戏互不影响
+	next := Result{Date: "2026-10-19", Value: 80, Label: "80"}
+	if ok, err := Record("snake", next); err != nil || !ok {
+		t.Fatalf("next day Record = %v, %v, want true, nil", ok, err)
+	}
+	if _, ok, err := Get("tetris", "2026-10-18"); err != nil || ok {
+		t.Errorf("tetris Get = %v, %v, want no result", ok, err)
+	}
+	history, err := History("snake")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 2 || history[0] != next || history[1] != first {
+		t.Errorf("History = %+v, want newest first", history)
+	}
+}
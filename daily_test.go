@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/daily"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+	snakepkg "go-game/snake"
+	tetrispkg "go-game/tetris"
+)
+
+// recordDaily 在临时存档中写入几天的每日成绩
+func recordDaily(t *testing.T) {
+	t.Helper()
+	t.Setenv(store.EnvHome, t.TempDir())
+	results := []struct {
+		game  string
+		date  string
+		score int
+	}{
+		{tetrispkg.DailyGame, "2026-10-16", 2400},
+		{snakepkg.DailyGame, "2026-10-17", 90},
+		{tetrispkg.DailyGame, "2026-10-18", 1200},
+	}
+	for _, r := range results {
+		if _, err := daily.Record(r.game, daily.Result{Date: r.date, Value: int64(r.score), Label: fmt.Sprint(r.score)}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	// 每天只有第一次的成绩有效
+	if ok, _ := daily.Record(tetrispkg.DailyGame, daily.Result{Date: "2026-10-18", Value: 9999, Label: "9999"}); ok {
+		t.Error("a second result for the same day should be rejected")
+	}
+}
+
+func TestDailyScreenGolden(t *testing.T) {
+	recordDaily(t)
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewDailyScreen(screen, input.DefaultBindings(), "2026-10-18").Render()
+	screentest.AssertScreen(t, "daily", screen)
+}
+
+// TestDailyScreenRun 用模拟按键选择当天的挑战
+func TestDailyScreenRun(t *testing.T) {
+	recordDaily(t)
+	tests := []struct {
+		name   string
+		keys   []*tcell.EventKey
+		want   string
+		wantOK bool
+	}{
+		{"tetris", []*tcell.EventKey{screentest.Key(tcell.KeyEnter)}, tetrispkg.DailyGame, true},
+		{"snake", []*tcell.EventKey{
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyEnter),
+		}, snakepkg.DailyGame, true},
+		{"back", []*tcell.EventKey{screentest.Key(tcell.KeyEscape)}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+			screentest.Type(screen, time.Millisecond, tt.keys...)
+			if got, ok := NewDailyScreen(screen, input.DefaultBindings(), "2026-10-18").Run(); got != tt.want || ok != tt.wantOK {
+				t.Errorf("Run() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
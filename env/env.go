@@ -0,0 +1,170 @@
+package env
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ============================================
+// 强化学习环境 - 基于 stdio 的逐行 JSON 协议
+// ============================================
+// 每行一个请求，每个请求对应一行响应：
+//
+//   → {"cmd": "spec"}
+//   ← {"actions": ["none", "up", ...], "observations": ["grid", "state"]}
+//
+//   → {"cmd": "reset", "seed": 42, "config": {"observation": "grid", "reward": {...}}}
+//   ← {"observation": [[0, 0, ...], ...], "info": {...}}
+//
+//   → {"cmd": "step", "action": "left"}        （也可以使用动作索引，例如 "action": 2）
+//   ← {"observation": ..., "reward": 1, "done": false, "truncated": false, "info": {...}}
+//
+//   → {"cmd": "close"}
+//
+// 出错时返回 {"error": "..."}，连接保持可用
+
+// Env 一个可被逐步驱动的游戏环境
+type Env interface {
+	// Actions 返回动作名称列表，动作索引即列表下标
+	Actions() []string
+	// Configure 在默认配置之上应用 JSON 配置（观测方式、奖励塑形等）
+	Configure(raw json.RawMessage) error
+	// Reset 用给定种子开始新的一局，返回初始观测和附加信息
+	Reset(seed int64) (obs any, info map[string]any)
+	// Step 执行一个动作（Actions 中的下标）
+	Step(action int) StepResult
+}
+
+// StepResult 一步的结果
+type StepResult struct {
+	Observation any            `json:"observation"`
+	Reward      float64        `json:"reward"`
+	Done        bool           `json:"done"`      // 游戏结束
+	Truncated   bool           `json:"truncated"` // 达到步数上限而被截断
+	Info        map[string]any `json:"info"`
+}
+
+// Observations 所有环境都支持的观测方式
+var Observations = []string{"grid", "state"}
+
+// New 根据游戏名称创建环境
+func New(game string) (Env, error) {
+	switch game {
+	case "snake":
+		return NewSnake(), nil
+	case "tetris":
+		return NewTetris(), nil
+	default:
+		return nil, fmt.Errorf("unknown game %q (valid games: snake, tetris)", game)
+	}
+}
+
+// ============================================
+// 协议处理
+// ============================================
+
+// request 一行请求
+type request struct {
+	Cmd    string          `json:"cmd"`
+	Seed   int64           `json:"seed"`
+	Config json.RawMessage `json:"config"`
+	Action json.RawMessage `json:"action"`
+}
+
+// Serve 从 r 逐行读取请求并将响应写入 w，直到收到 close 或输入结束
+func Serve(e Env, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	enc := json.NewEncoder(w)
+	started := false
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := enc.Encode(errorResponse(fmt.Errorf("invalid request: %w", err))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var resp any
+		switch req.Cmd {
+		case "spec":
+			resp = map[string]any{"actions": e.Actions(), "observations": Observations}
+		case "reset":
+			if len(req.Config) > 0 {
+				if err := e.Configure(req.Config); err != nil {
+					resp = errorResponse(err)
+					break
+				}
+			}
+			obs, info := e.Reset(req.Seed)
+			started = true
+			resp = map[string]any{"observation": obs, "info": info}
+		case "step":
+			if !started {
+				resp = errorResponse(fmt.Errorf("step before reset"))
+				break
+			}
+			action, err := parseAction(req.Action, e.Actions())
+			if err != nil {
+				resp = errorResponse(err)
+				break
+			}
+			resp = e.Step(action)
+		case "close":
+			return nil
+		default:
+			resp = errorResponse(fmt.Errorf("unknown cmd %q (valid: spec, reset, step, close)", req.Cmd))
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// parseAction 解析动作：可以是名称，也可以是下标
+func parseAction(raw json.RawMessage, actions []string) (int, error) {
+	var index int
+	if err := json.Unmarshal(raw, &index); err == nil {
+		if index < 0 || index >= len(actions) {
+			return 0, fmt.Errorf("action index %d out of range [0, %d)", index, len(actions))
+		}
+		return index, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return 0, fmt.Errorf("action must be a name or an index")
+	}
+	for i, a := range actions {
+		if a == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown action %q (valid actions: %v)", name, actions)
+}
+
+// errorResponse 错误响应
+func errorResponse(err error) map[string]string {
+	return map[string]string{"error": err.Error()}
+}
+
+// checkObservation 检查观测方式是否合法
+func checkObservation(name string) error {
+	for _, o := range Observations {
+		if o == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown observation %q (valid: %v)", name, Observations)
+}
@@ -0,0 +1,134 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// serve 把请求逐行交给 Serve，返回解码后的每一行响应
+func serve(t *testing.T, e Env, requests ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := Serve(e, strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var responses []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp map[string]any
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	if len(responses) != len(requests) {
+		t.Fatalf("got %d responses to %d requests: %v", len(responses), len(requests), responses)
+	}
+	return responses
+}
+
+// TestServeResetStep reset 返回初始观测，step 返回观测、奖励和结束标记
+func TestServeResetStep(t *testing.T) {
+	for _, game := range []string{"snake", "tetris"} {
+		t.Run(game, func(t *testing.T) {
+			e, err := New(game)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			resp := serve(t, e,
+				`{"cmd":"spec"}`,
+				`{"cmd":"reset","seed":42,"config":{"observation":"grid","reward":{"step":-0.5}}}`,
+				`{"cmd":"step","action":0}`,
+				fmt.Sprintf(`{"cmd":"step","action":%q}`, e.Actions()[1]),
+			)
+
+			if actions, ok := resp[0]["actions"].([]any); !ok || len(actions) != len(e.Actions()) {
+				t.Errorf("spec = %v, want the action list", resp[0])
+			}
+			if grid, ok := resp[1]["observation"].([]any); !ok || len(grid) == 0 {
+				t.Errorf("reset observation = %v, want a grid", resp[1]["observation"])
+			}
+			if _, ok := resp[1]["info"].(map[string]any); !ok {
+				t.Errorf("reset info = %v", resp[1]["info"])
+			}
+			for _, step := range resp[2:] {
+				if _, ok := step["error"]; ok {
+					t.Fatalf("step failed: %v", step["error"])
+				}
+				if _, ok := step["observation"].([]any); !ok {
+					t.Errorf("step observation = %v, want a grid", step["observation"])
+				}
+				if step["reward"] != -0.5 || step["done"] != false || step["truncated"] != false {
+					t.Errorf("step = reward %v, done %v, truncated %v; want -0.5, false, false", step["reward"], step["done"], step["truncated"])
+				}
+			}
+			if steps := resp[3]["info"].(map[string]any)["steps"]; steps != 2.0 {
+				t.Errorf("info.steps = %v, want 2", steps)
+			}
+		})
+	}
+}
+
+// TestServeErrors 错误的请求得到错误信息，之后的请求照常处理
+func TestServeErrors(t *testing.T) {
+	resp := serve(t, NewSnake(),
+		`{"cmd":"step","action":"up"}`,
+		`not json`,
+		`{"cmd":"jump"}`,
+		`{"cmd":"reset","config":{"observation":"pixels"}}`,
+		`{"cmd":"reset","seed":1}`,
+		`{"cmd":"step","action":"jump"}`,
+		`{"cmd":"step","action":9}`,
+		`{"cmd":"step","action":true}`,
+		`{"cmd":"step","action":"up"}`,
+	)
+	wants := []string{
+		"step before reset",
+		"invalid request",
+		`unknown cmd "jump"`,
+		`unknown observation "pixels"`,
+		"",
+		`unknown action "jump"`,
+		"action index 9 out of range",
+		"action must be a name or an index",
+		"",
+	}
+	for i, want := range wants {
+		got, _ := resp[i]["error"].(string)
+		if want == "" && got != "" || !strings.Contains(got, want) {
+			t.Errorf("response %d error = %q, want %q", i, got, want)
+		}
+	}
+}
+
+// TestServeDeterministic 同一个种子和同样的动作得到完全相同的一局
+func TestServeDeterministic(t *testing.T) {
+	for _, game := range []string{"snake", "tetris"} {
+		t.Run(game, func(t *testing.T) {
+			episode := func(seed int64) string {
+				e, _ := New(game)
+				requests := []string{fmt.Sprintf(`{"cmd":"reset","seed":%d,"config":{"observation":"state"}}`, seed)}
+				for i := range 60 {
+					requests = append(requests, fmt.Sprintf(`{"cmd":"step","action":%d}`, i*7%len(e.Actions())))
+				}
+				out, _ := json.Marshal(serve(t, e, requests...))
+				return string(out)
+			}
+			if episode(5) != episode(5) {
+				t.Error("the same seed should give the same episode")
+			}
+			if episode(5) == episode(6) {
+				t.Error("different seeds should give different episodes")
+			}
+		})
+	}
+}
+
+func TestNewUnknownGame(t *testing.T) {
+	if _, err := New("chess"); err == nil || !strings.Contains(err.Error(), `unknown game "chess"`) {
+		t.Errorf("err = %v", err)
+	}
+}
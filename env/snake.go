@@ -0,0 +1,191 @@
+package env
+
+import (
+	"encoding/json"
+
+	"go-game/snake"
+)
+
+// ============================================
+// 贪吃蛇环境
+// ============================================
+
+// 网格观测中的单元格取值
+const (
+	CellEmpty  = 0 // 空白
+	CellBody   = 1 // 蛇身 / 已锁定的方块
+	CellHead   = 2 // 蛇头 / 正在下落的方块
+	CellFood   = 3 // 食物
+	CellWall   = 4 // 障碍物
+	CellItem   = 5 // 特殊食物（奖励、缩小、加速、减速等）
+	CellPortal = 6 // 传送门：进入后从成对的另一个传送门出来
+	CellHazard = 7 // 巡逻障碍的当前位置：撞上即死亡
+)
+
+// SnakeReward 贪吃蛇的奖励塑形参数
+type SnakeReward struct {
+	Food   float64 `json:"food"`   // 吃到食物
+	Death  float64 `json:"death"`  // 游戏结束（获胜不算）
+	Step   float64 `json:"step"`   // 每一步（通常为小的负数，鼓励尽快吃到食物）
+	Closer float64 `json:"closer"` // 靠近食物 +closer，远离食物 -closer
+}
+
+// SnakeConfig 贪吃蛇环境配置
+type SnakeConfig struct {
+	Observation string      `json:"observation"` // "grid" 或 "state"
+	Wrap        bool        `json:"wrap"`        // 穿墙模式
+	Level       string      `json:"level"`       // 关卡名或 "campaign"，空表示空白面板
+	MaxSteps    int         `json:"max_steps"`   // 步数上限，0 表示不限制
+	Reward      SnakeReward `json:"reward"`
+}
+
+// Snake 贪吃蛇环境
+type Snake struct {
+	config SnakeConfig
+	levels []*snake.Level // 按 config.Level 加载的关卡
+	game   *snake.Game
+	steps  int
+}
+
+// snakeActions 动作列表："none" 保持当前方向
+var snakeActions = []string{"none", "up", "down", "left", "right"}
+
+// NewSnake 创建默认配置的贪吃蛇环境
+func NewSnake() *Snake {
+	return &Snake{
+		config: SnakeConfig{
+			Observation: "grid",
+			Reward:      SnakeReward{Food: 1, Death: -1},
+		},
+	}
+}
+
+// Actions 返回动作名称列表
+func (e *Snake) Actions() []string {
+	return snakeActions
+}
+
+// Configure 在当前配置之上应用 JSON 配置
+func (e *Snake) Configure(raw json.RawMessage) error {
+	config := e.config
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return err
+	}
+	if err := checkObservation(config.Observation); err != nil {
+		return err
+	}
+	levels, err := snake.FindLevels(config.Level)
+	if err != nil {
+		return err
+	}
+	e.config = config
+	e.levels = levels
+	return nil
+}
+
+// Reset 开始新的一局
+func (e *Snake) Reset(seed int64) (any, map[string]any) {
+	e.game = snake.NewGame(snake.WithSeed(seed), snake.WithWrap(e.config.Wrap), snake.WithLevels(e.levels...))
+	e.steps = 0
+	state := e.game.State()
+	return e.observe(state), e.info(state)
+}
+
+// Step 执行一个动作
+func (e *Snake) Step(action int) StepResult {
+	before := e.game.State()
+	if action > 0 {
+		e.game.Step(snake.Direction(action - 1))
+	} else {
+		e.game.Step()
+	}
+	e.steps++
+	after := e.game.State()
+
+	reward := e.config.Reward.Step
+	if after.Score > before.Score {
+		reward += e.config.Reward.Food
+	}
+	if after.GameOver {
+		// 填满面板或完成最后一关时获胜，不算死亡
+		if !after.Won {
+			reward += e.config.Reward.Death
+		}
+	} else if e.config.Reward.Closer != 0 && after.Score == before.Score {
+		// 按与食物的曼哈顿距离变化塑形
+		switch d0, d1 := distance(before.Snake[0], before.Food), distance(after.Snake[0], after.Food); {
+		case d1 < d0:
+			reward += e.config.Reward.Closer
+		case d1 > d0:
+			reward -= e.config.Reward.Closer
+		}
+	}
+
+	return StepResult{
+		Observation: e.observe(after),
+		Reward:      reward,
+		Done:        after.GameOver,
+		Truncated:   !after.GameOver && e.config.MaxSteps > 0 && e.steps >= e.config.MaxSteps,
+		Info:        e.info(after),
+	}
+}
+
+// observe 根据配置生成观测
+func (e *Snake) observe(state snake.State) any {
+	if e.config.Observation == "state" {
+		return state
+	}
+	grid := make([][]int, state.Height)
+	for y := range grid {
+		grid[y] = make([]int, state.Width)
+	}
+	for _, p := range state.Walls {
+		grid[p.Y][p.X] = CellWall
+	}
+	for _, pt := range state.Portals {
+		grid[pt.A.Y][pt.A.X] = CellPortal
+		grid[pt.B.Y][pt.B.X] = CellPortal
+	}
+	for _, f := range state.Foods {
+		if f.Kind == snake.Apple {
+			grid[f.Pos.Y][f.Pos.X] = CellFood
+		} else {
+			grid[f.Pos.Y][f.Pos.X] = CellItem
+		}
+	}
+	// 巡逻障碍可能移动到食物上，致命的障碍优先显示
+	for _, h := range state.Hazards {
+		grid[h.Pos.Y][h.Pos.X] = CellHazard
+	}
+	for i, p := range state.Snake {
+		if i == 0 {
+			grid[p.Y][p.X] = CellHead
+		} else {
+			grid[p.Y][p.X] = CellBody
+		}
+	}
+	return grid
+}
+
+// info 附加信息
+func (e *Snake) info(state snake.State) map[string]any {
+	return map[string]any{
+		"score":  state.Score,
+		"length": len(state.Snake),
+		"steps":  e.steps,
+		"won":    state.Won,
+	}
+}
+
+// distance 曼哈顿距离
+func distance(a, b snake.Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+// abs 绝对值
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,64 @@
+package env
+
+import (
+	"strings"
+	"testing"
+
+	"go-game/snake"
+)
+
+// TestSnakeWinReward 完成最后一关结束游戏时不扣除死亡奖励
+func TestSnakeWinReward(t *testing.T) {
+	// 唯一的空格在蛇头前面，吃到食物就达到目标长度
+	lvl, err := snake.ParseLevel("tiny", strings.NewReader("target: 4\n######\n#.<..#\n######\n"))
+	if err != nil {
+		t.Fatalf("ParseLevel: %v", err)
+	}
+	e := NewSnake()
+	e.levels = []*snake.Level{lvl}
+	e.Reset(1)
+
+	r := e.Step(0)
+	if !r.Done || r.Info["won"] != true {
+		t.Fatalf("step = done %v, info %v; want a win", r.Done, r.Info)
+	}
+	if r.Reward != e.config.Reward.Food {
+		t.Errorf("reward = %v, want only the food reward %v", r.Reward, e.config.Reward.Food)
+	}
+
+	// 撞墙结束仍然扣除死亡奖励
+	e.Reset(1)
+	r = e.Step(1) // up
+	if !r.Done || r.Info["won"] != false || r.Reward != e.config.Reward.Death {
+		t.Errorf("crash = done %v, reward %v, info %v; want the death reward", r.Done, r.Reward, r.Info)
+	}
+}
+
+// TestSnakeGridEntities 网格观测中标出传送门和巡逻障碍
+func TestSnakeGridEntities(t *testing.T) {
+	e := NewSnake()
+	if err := e.Configure([]byte(`{"level":"Warp"}`)); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	obs, _ := e.Reset(1)
+	grid := obs.([][]int)
+	state := e.game.State()
+	if len(state.Portals) == 0 || len(state.Hazards) == 0 {
+		t.Fatal("the Warp level should have portals and hazards")
+	}
+	for _, pt := range state.Portals {
+		for _, p := range []snake.Point{pt.A, pt.B} {
+			if grid[p.Y][p.X] != CellPortal {
+				t.Errorf("portal at %v = %d, want %d", p, grid[p.Y][p.X], CellPortal)
+			}
+		}
+	}
+	for range 5 {
+		for _, h := range e.game.State().Hazards {
+			if grid[h.Pos.Y][h.Pos.X] != CellHazard {
+				t.Errorf("hazard at %v = %d, want %d", h.Pos, grid[h.Pos.Y][h.Pos.X], CellHazard)
+			}
+		}
+		grid = e.Step(0).Observation.([][]int)
+	}
+}
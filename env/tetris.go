@@ -0,0 +1,167 @@
+package env
+
+import (
+	"encoding/json"
+
+	"go-game/tetris"
+)
+
+// ============================================
+// 俄罗斯方块环境
+// ============================================
+
+// TetrisReward 俄罗斯方块的奖励塑形参数
+type TetrisReward struct {
+	Score float64    `json:"score"` // 每 1 分得分的奖励
+	Lines [5]float64 `json:"lines"` // 一次消除 0-4 行的奖励
+	Death float64    `json:"death"` // 游戏结束（竞速模式完成目标不算）
+	Step  float64    `json:"step"`  // 每一步
+	Holes float64    `json:"holes"` // 每新增一个空洞（上方有方块的空格）
+}
+
+// TetrisConfig 俄罗斯方块环境配置
+type TetrisConfig struct {
+	Observation string       `json:"observation"` // "grid" 或 "state"
+	Mode        string       `json:"mode"`        // "marathon" 或 "sprint"
+	MaxSteps    int          `json:"max_steps"`   // 步数上限，0 表示不限制
+	Reward      TetrisReward `json:"reward"`
+}
+
+// Tetris 俄罗斯方块环境
+type Tetris struct {
+	config TetrisConfig
+	mode   tetris.Mode
+	game   *tetris.Game
+	steps  int
+}
+
+// tetrisActions 动作列表，顺序与 tetris.Action 一致
+var tetrisActions = []string{"none", "left", "right", "rotate", "soft_drop", "hard_drop"}
+
+// NewTetris 创建默认配置的俄罗斯方块环境
+func NewTetris() *Tetris {
+	return &Tetris{
+		config: TetrisConfig{
+			Observation: "grid",
+			Mode:        tetris.ModeMarathon.String(),
+			Reward: TetrisReward{
+				Lines: [5]float64{0, 1, 3, 5, 8},
+				Death: -1,
+			},
+		},
+	}
+}
+
+// Actions 返回动作名称列表
+func (e *Tetris) Actions() []string {
+	return tetrisActions
+}
+
+// Configure 在当前配置之上应用 JSON 配置
+func (e *Tetris) Configure(raw json.RawMessage) error {
+	config := e.config
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return err
+	}
+	if err := checkObservation(config.Observation); err != nil {
+		return err
+	}
+	mode, err := tetris.ParseMode(config.Mode)
+	if err != nil {
+		return err
+	}
+	e.config, e.mode = config, mode
+	return nil
+}
+
+// Reset 开始新的一局
+func (e *Tetris) Reset(seed int64) (any, map[string]any) {
+	e.game = tetris.NewSeededGame(e.mode, seed)
+	e.steps = 0
+	state := e.game.State()
+	return e.observe(state), e.info(state)
+}
+
+// Step 执行一个动作并推进一次重力
+func (e *Tetris) Step(action int) StepResult {
+	before := e.game.State()
+	e.game.Step(tetris.Action(action))
+	e.steps++
+	after := e.game.State()
+
+	r := e.config.Reward
+	reward := r.Step + r.Score*float64(after.Score-before.Score)
+	if cleared := after.Lines - before.Lines; cleared > 0 && cleared < len(r.Lines) {
+		reward += r.Lines[cleared]
+	}
+	reward -= r.Holes * float64(countHoles(after.Board)-countHoles(before.Board))
+	if after.GameOver && !after.Won {
+		reward += r.Death
+	}
+
+	return StepResult{
+		Observation: e.observe(after),
+		Reward:      reward,
+		Done:        after.GameOver,
+		Truncated:   !after.GameOver && e.config.MaxSteps > 0 && e.steps >= e.config.MaxSteps,
+		Info:        e.info(after),
+	}
+}
+
+// observe 根据配置生成观测
+// 网格观测：已锁定方块为 CellBody，正在下落的方块为 CellHead
+func (e *Tetris) observe(state tetris.State) any {
+	if e.config.Observation == "state" {
+		return state
+	}
+	grid := make([][]int, len(state.Board))
+	for y, row := range state.Board {
+		grid[y] = make([]int, len(row))
+		for x, cell := range row {
+			if cell != 0 {
+				grid[y][x] = CellBody
+			}
+		}
+	}
+	if !state.GameOver {
+		for y, row := range state.Shape {
+			for x, cell := range row {
+				by, bx := state.Y+y, state.X+x
+				if cell == 1 && by >= 0 && by < len(grid) {
+					grid[by][bx] = CellHead
+				}
+			}
+		}
+	}
+	return grid
+}
+
+// info 附加信息
+func (e *Tetris) info(state tetris.State) map[string]any {
+	return map[string]any{
+		"score": state.Score,
+		"lines": state.Lines,
+		"level": state.Level,
+		"next":  state.Next,
+		"steps": e.steps,
+	}
+}
+
+// countHoles 统计空洞数：每列最高方块之下的空格
+func countHoles(board [][]int) int {
+	holes := 0
+	if len(board) == 0 {
+		return 0
+	}
+	for x := range board[0] {
+		covered := false
+		for y := range board {
+			if board[y][x] != 0 {
+				covered = true
+			} else if covered {
+				holes++
+			}
+		}
+	}
+	return holes
+}
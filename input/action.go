@@ -0,0 +1,87 @@
+package input
+
+// ============================================
+// Action - 与具体按键无关的游戏动作
+// ============================================
+// 游戏逻辑只关心"要做什么"，按键到动作的映射由 Keymap 负责
+
+type Action int
+
+const (
+	MoveLeft    Action = iota // 左移
+	MoveRight                 // 右移
+	MoveUp                    // 上移（贪吃蛇）
+	MoveDown                  // 下移（贪吃蛇）
+	RotateCW                  // 顺时针旋转（俄罗斯方块）
+	SoftDrop                  // 软降（俄罗斯方块）
+	HardDrop                  // 硬降（俄罗斯方块）
+	Pause                     // 暂停/继续
+	Restart                   // 重新开始
+	Back                      // 返回主菜单
+	Quit                      // 退出程序
+	P2MoveUp                  // 二号玩家上移（双人贪吃蛇）
+	P2MoveDown                // 二号玩家下移
+	P2MoveLeft                // 二号玩家左移
+	P2MoveRight               // 二号玩家右移
+	Boost                     // 加速（贪吃蛇，按住时移动加快、得分翻倍）
+	Heatmap                   // 游戏结束后显示/隐藏热力图（贪吃蛇）
+	Undo                      // 撤销上一个方块（俄罗斯方块谜题和练习）
+	Redo                      // 重做撤销掉的方块（俄罗斯方块谜题和练习）
+	CopyFumen                 // 把当前面板以 fumen 格式复制到剪贴板（俄罗斯方块）
+)
+
+// actionInfo 动作的配置名与界面显示名
+var actionInfo = map[Action]struct {
+	name string // 配置文件中使用的名称
+	desc string // 操作说明面板中显示的文字
+}{
+	MoveLeft:  {"MoveLeft", "Left"},
+	MoveRight: {"MoveRight", "Right"},
+	MoveUp:    {"MoveUp", "Up"},
+	MoveDown:  {"MoveDown", "Down"},
+	RotateCW:  {"RotateCW", "Rotate"},
+	SoftDrop:  {"SoftDrop", "Soft Drop"},
+	HardDrop:  {"HardDrop", "Hard Drop"},
+	Pause:     {"Pause", "Pause"},
+	Restart:   {"Restart", "Restart"},
+	Back:      {"Back", "Menu"},
+	Quit:      {"Quit", "Quit"},
+
+	P2MoveUp:    {"P2MoveUp", "P2 Up"},
+	P2MoveDown:  {"P2MoveDown", "P2 Down"},
+	P2MoveLeft:  {"P2MoveLeft", "P2 Left"},
+	P2MoveRight: {"P2MoveRight", "P2 Right"},
+
+	Boost:   {"Boost", "Boost"},
+	Heatmap: {"Heatmap", "Heatmap"},
+	Undo:    {"Undo", "Undo"},
+	Redo:    {"Redo", "Redo"},
+
+	CopyFumen: {"CopyFumen", "Copy Fumen"},
+}
+
+// String 返回动作的配置名，例如 "MoveLeft"
+func (a Action) String() string {
+	if info, ok := actionInfo[a]; ok {
+		return info.name
+	}
+	return "Unknown"
+}
+
+// Desc 返回动作在操作说明中的显示文字
+func (a Action) Desc() string {
+	if info, ok := actionInfo[a]; ok {
+		return info.desc
+	}
+	return "?"
+}
+
+// ParseAction 根据配置名查找动作
+func ParseAction(name string) (Action, bool) {
+	for a, info := range actionInfo {
+		if info.name == name {
+			return a, true
+		}
+	}
+	return 0, false
+}
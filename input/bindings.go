@@ -0,0 +1,132 @@
+package input
+
+import (
+	"errors"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/internal/store"
+)
+
+// ============================================
+// Bindings - 所有游戏的按键映射
+// ============================================
+
+// bindingsFile 按键配置在存档目录中的文件名
+const bindingsFile = "keys.json"
+
+type Bindings struct {
+	Tetris *Keymap
+	Snake  *Keymap
+}
+
+// DefaultTetris 俄罗斯方块的默认按键（方向键 + vim 风格的 hjkl），谜题和练习中 u 撤销、
+// Ctrl-R 重做，c 把当前面板以 fumen 格式复制到剪贴板
+func DefaultTetris() *Keymap {
+	m := NewKeymap("Tetris", MoveLeft, MoveRight, RotateCW, SoftDrop, HardDrop, Pause, Restart, Undo, Redo, CopyFumen, Back, Quit)
+	m.Set(MoveLeft, KeyCode(tcell.KeyLeft), KeyRune('h'))
+	m.Set(MoveRight, KeyCode(tcell.KeyRight), KeyRune('l'))
+	m.Set(RotateCW, KeyCode(tcell.KeyUp), KeyRune('k'))
+	m.Set(SoftDrop, KeyCode(tcell.KeyDown), KeyRune('j'))
+	m.Set(HardDrop, KeyRune(' '))
+	m.Set(Pause, KeyRune('p'))
+	m.Set(Restart, KeyRune('r'))
+	m.Set(Undo, KeyRune('u'))
+	m.Set(Redo, KeyCode(tcell.KeyCtrlR))
+	m.Set(CopyFumen, KeyRune('c'))
+	m.Set(Back, KeyCode(tcell.KeyEscape))
+	m.Set(Quit, KeyRune('q'), KeyCode(tcell.KeyCtrlC))
+	return m
+}
+
+// DefaultSnake 贪吃蛇的默认按键（方向键 + vim 风格的 hjkl）
+// 空格加速，游戏结束后 m 显示热力图，双人模式下二号玩家使用 WASD
+func DefaultSnake() *Keymap {
+	m := NewKeymap("Snake", MoveUp, MoveDown, MoveLeft, MoveRight, Boost, Pause, Restart, Back, Heatmap,
+		P2MoveUp, P2MoveDown, P2MoveLeft, P2MoveRight)
+	m.Set(MoveUp, KeyCode(tcell.KeyUp), KeyRune('k'))
+	m.Set(MoveDown, KeyCode(tcell.KeyDown), KeyRune('j'))
+	m.Set(MoveLeft, KeyCode(tcell.KeyLeft), KeyRune('h'))
+	m.Set(MoveRight, KeyCode(tcell.KeyRight), KeyRune('l'))
+	m.Set(Boost, KeyRune(' '))
+	m.Set(Pause, KeyRune('p'))
+	m.Set(Restart, KeyRune('r'))
+	m.Set(Back, KeyCode(tcell.KeyEscape))
+	m.Set(Heatmap, KeyRune('m'))
+	m.Set(P2MoveUp, KeyRune('w'))
+	m.Set(P2MoveDown, KeyRune('s'))
+	m.Set(P2MoveLeft, KeyRune('a'))
+	m.Set(P2MoveRight, KeyRune('d'))
+	return m
+}
+
+// DefaultBindings 返回全部默认按键
+func DefaultBindings() *Bindings {
+	return &Bindings{
+		Tetris: DefaultTetris(),
+		Snake:  DefaultSnake(),
+	}
+}
+
+// Keymaps 按设置界面的显示顺序返回所有映射表
+func (b *Bindings) Keymaps() []*Keymap {
+	return []*Keymap{b.Tetris, b.Snake}
+}
+
+// Navigate 把按键事件解释为菜单导航（见 Keymap.Navigate）
+// 使用贪吃蛇的映射表：只有它为上下左右四个方向都设置了按键
+func (b *Bindings) Navigate(ev *tcell.EventKey) Nav {
+	return b.Snake.Navigate(ev)
+}
+
+// ============================================
+// 持久化
+// ============================================
+// 文件格式：{"Tetris": {"MoveLeft": ["Left", "h"], ...}, "Snake": {...}}
+
+type bindingsJSON map[string]map[string][]string
+
+// LoadBindings 读取用户的按键配置
+// 配置不存在时返回默认按键；配置中缺失的动作保留默认值
+func LoadBindings() (*Bindings, error) {
+	b := DefaultBindings()
+
+	var data bindingsJSON
+	if err := store.Load(bindingsFile, &data); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return b, nil
+		}
+		return b, err
+	}
+
+	// 按映射表中动作的顺序应用，同一个按键绑定到多个动作时结果是确定的：排在后面的动作得到这个按键
+	for _, m := range b.Keymaps() {
+		for _, a := range m.Actions {
+			var keys []Key
+			for _, s := range data[m.Name][a.String()] {
+				if k, err := ParseKey(s); err == nil {
+					keys = append(keys, k)
+				}
+			}
+			if len(keys) > 0 {
+				m.Set(a, keys...)
+			}
+		}
+	}
+	return b, nil
+}
+
+// Save 保存按键配置
+func (b *Bindings) Save() error {
+	data := bindingsJSON{}
+	for _, m := range b.Keymaps() {
+		actions := map[string][]string{}
+		for _, a := range m.Actions {
+			for _, k := range m.Keys(a) {
+				actions[a.String()] = append(actions[a.String()], k.String())
+			}
+		}
+		data[m.Name] = actions
+	}
+	return store.Save(bindingsFile, data)
+}
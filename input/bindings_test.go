@@ -0,0 +1,87 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/internal/store"
+)
+
+// TestLoadBindings 用户配置覆盖默认按键，配置中没有的动作保留默认值
+func TestLoadBindings(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want map[Action][]Key // 贪吃蛇映射表中要检查的动作
+	}{
+		{"missing file", "", map[Action][]Key{
+			MoveUp:   {KeyCode(tcell.KeyUp), KeyRune('k')},
+			MoveLeft: {KeyCode(tcell.KeyLeft), KeyRune('h')},
+		}},
+		{"override one action", `{"Snake": {"MoveUp": ["w"]}}`, map[Action][]Key{
+			MoveUp:   {KeyRune('w')},
+			MoveLeft: {KeyCode(tcell.KeyLeft), KeyRune('h')},
+			P2MoveUp: nil, // w 被一号玩家拿走
+		}},
+		{"unknown names ignored", `{"Snake": {"Fly": ["f"], "MoveDown": ["Hyper"], "Boost": ["Space", "Hyper", "b"]}}`, map[Action][]Key{
+			MoveDown: {KeyCode(tcell.KeyDown), KeyRune('j')},
+			Boost:    {KeyRune(' '), KeyRune('b')},
+		}},
+		// 同一个按键绑定到两个动作时，映射表中排在后面的动作得到它
+		{"duplicate key", `{"Snake": {"P2MoveUp": ["x"], "MoveUp": ["x", "Up"]}}`, map[Action][]Key{
+			MoveUp:   {KeyCode(tcell.KeyUp)},
+			P2MoveUp: {KeyRune('x')},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv(store.EnvHome, dir)
+			if tt.json != "" {
+				if err := os.WriteFile(filepath.Join(dir, bindingsFile), []byte(tt.json), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// 重复读取多次：结果不能依赖 map 的遍历顺序
+			for range 10 {
+				b, err := LoadBindings()
+				if err != nil {
+					t.Fatalf("LoadBindings: %v", err)
+				}
+				for a, want := range tt.want {
+					if got := b.Snake.Keys(a); !slices.Equal(got, want) {
+						t.Fatalf("Snake.Keys(%s) = %v, want %v", a, got, want)
+					}
+				}
+				if got, want := b.Tetris.Keys(HardDrop), DefaultTetris().Keys(HardDrop); !slices.Equal(got, want) {
+					t.Fatalf("Tetris.Keys(HardDrop) = %v, want the default %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestBindingsSaveLoad 保存后再读取得到相同的按键
+func TestBindingsSaveLoad(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	b := DefaultBindings()
+	b.Tetris.Set(HardDrop, KeyCode(tcell.KeyEnter))
+	b.Snake.Add(Boost, KeyRune('b'))
+	if err := b.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadBindings()
+	if err != nil {
+		t.Fatalf("LoadBindings: %v", err)
+	}
+	for i, m := range b.Keymaps() {
+		for _, a := range m.Actions {
+			if got, want := loaded.Keymaps()[i].Keys(a), m.Keys(a); !slices.Equal(got, want) {
+				t.Errorf("%s.Keys(%s) = %v, want %v", m.Name, a, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ============================================
+// Key - 单个按键
+// ============================================
+
+// Key 表示一个按键：特殊键使用 Code，字符键使用 Rune（Code 为 tcell.KeyRune）
+type Key struct {
+	Code tcell.Key
+	Rune rune
+}
+
+// KeyCode 构造一个特殊键（方向键、Esc 等）
+func KeyCode(code tcell.Key) Key {
+	return Key{Code: code}
+}
+
+// KeyRune 构造一个字符键，字母统一按小写保存
+func KeyRune(r rune) Key {
+	return Key{Code: tcell.KeyRune, Rune: unicode.ToLower(r)}
+}
+
+// KeyOf 从按键事件中提取按键
+func KeyOf(ev *tcell.EventKey) Key {
+	if ev.Key() == tcell.KeyRune {
+		return KeyRune(ev.Rune())
+	}
+	return KeyCode(ev.Key())
+}
+
+// keyLabels 操作说明面板中使用的简短符号
+var keyLabels = map[tcell.Key]string{
+	tcell.KeyUp:    "↑",
+	tcell.KeyDown:  "↓",
+	tcell.KeyLeft:  "←",
+	tcell.KeyRight: "→",
+}
+
+// Label 返回按键在界面上的简短显示，例如 "←"、"Space"、"h"
+func (k Key) Label() string {
+	if k.Code == tcell.KeyRune {
+		if k.Rune == ' ' {
+			return "Space"
+		}
+		return string(unicode.ToUpper(k.Rune))
+	}
+	if label, ok := keyLabels[k.Code]; ok {
+		return label
+	}
+	return k.String()
+}
+
+// String 返回按键的配置名，例如 "Left"、"Space"、"h"
+func (k Key) String() string {
+	if k.Code == tcell.KeyRune {
+		if k.Rune == ' ' {
+			return "Space"
+		}
+		return string(k.Rune)
+	}
+	if name, ok := tcell.KeyNames[k.Code]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key[%d]", k.Code)
+}
+
+// ParseKey 解析配置名（String 的逆操作）
+func ParseKey(s string) (Key, error) {
+	if s == "Space" {
+		return KeyRune(' '), nil
+	}
+	if r := []rune(s); len(r) == 1 {
+		return KeyRune(r[0]), nil
+	}
+	for code, name := range tcell.KeyNames {
+		if name == s {
+			return KeyCode(code), nil
+		}
+	}
+	return Key{}, fmt.Errorf("unknown key %q", s)
+}
+
+// ============================================
+// Keymap - 一个游戏的按键映射表
+// ============================================
+// 每个动作可以绑定一个或多个按键；同一按键只属于一个动作
+
+type Keymap struct {
+	Name    string           // 映射表名称（显示在设置界面）
+	Actions []Action         // 该游戏支持的动作，按显示顺序排列
+	binds   map[Action][]Key // 动作 -> 按键列表
+}
+
+// NewKeymap 创建空的按键映射表
+func NewKeymap(name string, actions ...Action) *Keymap {
+	return &Keymap{
+		Name:    name,
+		Actions: actions,
+		binds:   make(map[Action][]Key),
+	}
+}
+
+// Lookup 查找按键事件对应的动作
+func (m *Keymap) Lookup(ev *tcell.EventKey) (Action, bool) {
+	key := KeyOf(ev)
+	for _, a := range m.Actions {
+		for _, k := range m.binds[a] {
+			if k == key {
+				return a, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Keys 返回动作当前绑定的按键
+func (m *Keymap) Keys(a Action) []Key {
+	return m.binds[a]
+}
+
+// Set 将动作的绑定替换为给定按键
+// 如果某个按键已绑定到其他动作，会先从那个动作上解绑
+func (m *Keymap) Set(a Action, keys ...Key) {
+	m.binds[a] = nil
+	for _, k := range keys {
+		m.Add(a, k)
+	}
+}
+
+// Add 为动作追加一个按键
+func (m *Keymap) Add(a Action, k Key) {
+	for other, list := range m.binds {
+		for i, existing := range list {
+			if existing == k {
+				m.binds[other] = append(list[:i:i], list[i+1:]...)
+				break
+			}
+		}
+	}
+	m.binds[a] = append(m.binds[a], k)
+}
+
+// Label 返回动作所有按键的显示文字，例如 "←/H"
+func (m *Keymap) Label(a Action) string {
+	labels := make([]string, 0, len(m.binds[a]))
+	for _, k := range m.binds[a] {
+		labels = append(labels, k.Label())
+	}
+	return strings.Join(labels, "/")
+}
+
+// Help 生成操作说明面板的文字行
+// 格式与原先手写的面板一致："←/H  : Left"
+func (m *Keymap) Help() []string {
+	return m.HelpFor(m.Actions...)
+}
+
+// HelpFor 只为给定的动作生成操作说明（例如单人模式下不显示二号玩家的按键）
+func (m *Keymap) HelpFor(actions ...Action) []string {
+	lines := []string{"CONTROLS:"}
+	for _, a := range actions {
+		lines = append(lines, fmt.Sprintf("%-6s: %s", m.Label(a), a.Desc()))
+	}
+	return lines
+}
+
+// ============================================
+// Nav - 菜单导航
+// ============================================
+// 菜单、选项和设置界面不使用游戏动作，而是把按键解释为导航：
+// 方向键、Enter 和 Esc 总是有效（按键改坏了也能操作菜单），
+// 此外映射表中上下左右移动和返回的按键也可以使用，例如把移动改为其他按键后菜单中同样可用
+
+type Nav int
+
+const (
+	NavNone   Nav = iota // 不是导航按键
+	NavUp                // 上一项
+	NavDown              // 下一项
+	NavLeft              // 向左（切换选项的值、切换标签页）
+	NavRight             // 向右
+	NavSelect            // 确认
+	NavBack              // 返回
+)
+
+// navKeys 总是有效的导航按键
+var navKeys = map[tcell.Key]Nav{
+	tcell.KeyUp:     NavUp,
+	tcell.KeyDown:   NavDown,
+	tcell.KeyLeft:   NavLeft,
+	tcell.KeyRight:  NavRight,
+	tcell.KeyEnter:  NavSelect,
+	tcell.KeyEscape: NavBack,
+}
+
+// navActions 可以用于导航的动作
+var navActions = map[Action]Nav{
+	MoveUp:    NavUp,
+	MoveDown:  NavDown,
+	MoveLeft:  NavLeft,
+	MoveRight: NavRight,
+	Back:      NavBack,
+}
+
+// Navigate 把按键事件解释为菜单导航，不是导航按键时返回 NavNone
+func (m *Keymap) Navigate(ev *tcell.EventKey) Nav {
+	if nav, ok := navKeys[ev.Key()]; ok {
+		return nav
+	}
+	if a, ok := m.Lookup(ev); ok {
+		return navActions[a]
+	}
+	return NavNone
+}
@@ -0,0 +1,116 @@
+package input
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TestKeymapTakeover 同一按键只属于一个动作：Set 和 Add 会把按键从原来的动作中拿走
+func TestKeymapTakeover(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(m *Keymap)
+		want  map[Action][]Key
+	}{
+		{"set replaces", func(m *Keymap) { m.Set(MoveLeft, KeyRune('a')) }, map[Action][]Key{
+			MoveLeft:  {KeyRune('a')},
+			MoveRight: {KeyCode(tcell.KeyRight), KeyRune('l')},
+		}},
+		{"set takes over", func(m *Keymap) { m.Set(MoveLeft, KeyRune('l')) }, map[Action][]Key{
+			MoveLeft:  {KeyRune('l')},
+			MoveRight: {KeyCode(tcell.KeyRight)},
+		}},
+		{"add takes over", func(m *Keymap) { m.Add(MoveLeft, KeyCode(tcell.KeyRight)) }, map[Action][]Key{
+			MoveLeft:  {KeyCode(tcell.KeyLeft), KeyRune('h'), KeyCode(tcell.KeyRight)},
+			MoveRight: {KeyRune('l')},
+		}},
+		{"add takes last key", func(m *Keymap) {
+			m.Add(MoveLeft, KeyCode(tcell.KeyRight))
+			m.Add(MoveLeft, KeyRune('l'))
+		}, map[Action][]Key{
+			MoveLeft:  {KeyCode(tcell.KeyLeft), KeyRune('h'), KeyCode(tcell.KeyRight), KeyRune('l')},
+			MoveRight: nil,
+		}},
+		{"add is idempotent", func(m *Keymap) { m.Add(MoveLeft, KeyRune('h')) }, map[Action][]Key{
+			MoveLeft:  {KeyCode(tcell.KeyLeft), KeyRune('h')},
+			MoveRight: {KeyCode(tcell.KeyRight), KeyRune('l')},
+		}},
+		{"letters are lowercased", func(m *Keymap) { m.Set(MoveRight, KeyRune('H')) }, map[Action][]Key{
+			MoveLeft:  {KeyCode(tcell.KeyLeft)},
+			MoveRight: {KeyRune('h')},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewKeymap("Test", MoveLeft, MoveRight)
+			m.Set(MoveLeft, KeyCode(tcell.KeyLeft), KeyRune('h'))
+			m.Set(MoveRight, KeyCode(tcell.KeyRight), KeyRune('l'))
+			tt.apply(m)
+			for a, want := range tt.want {
+				if got := m.Keys(a); !slices.Equal(got, want) {
+					t.Errorf("Keys(%s) = %v, want %v", a, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseKeyRoundTrip 配置文件中的按键名可以解析回同一个按键
+func TestParseKeyRoundTrip(t *testing.T) {
+	keys := []Key{
+		KeyRune('a'),
+		KeyRune('1'),
+		KeyRune(' '),
+		KeyRune('é'),
+		KeyCode(tcell.KeyUp),
+		KeyCode(tcell.KeyEnter),
+		KeyCode(tcell.KeyEscape),
+		KeyCode(tcell.KeyCtrlR),
+		KeyCode(tcell.KeyF5),
+	}
+	for _, k := range keys {
+		t.Run(k.String(), func(t *testing.T) {
+			got, err := ParseKey(k.String())
+			if err != nil {
+				t.Fatalf("ParseKey(%q): %v", k.String(), err)
+			}
+			if got != k {
+				t.Errorf("ParseKey(%q) = %v, want %v", k.String(), got, k)
+			}
+		})
+	}
+
+	if _, err := ParseKey("Hyper"); err == nil {
+		t.Error(`ParseKey("Hyper") succeeded, want an error`)
+	}
+}
+
+// TestNavigate 方向键、Enter 和 Esc 总是可以导航，映射表中的移动和返回按键也可以
+func TestNavigate(t *testing.T) {
+	m := DefaultSnake()
+	m.Set(MoveDown, KeyRune('s'))
+	m.Set(Back, KeyRune('b'))
+	tests := []struct {
+		name string
+		ev   *tcell.EventKey
+		want Nav
+	}{
+		{"arrow", tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), NavDown},
+		{"enter", tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), NavSelect},
+		{"escape", tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), NavBack},
+		{"bound move", tcell.NewEventKey(tcell.KeyRune, 'k', tcell.ModNone), NavUp},
+		{"rebound move", tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone), NavDown},
+		{"rebound back", tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModNone), NavBack},
+		{"old binding", tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone), NavNone},
+		{"other action", tcell.NewEventKey(tcell.KeyRune, 'p', tcell.ModNone), NavNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Navigate(tt.ev); got != tt.want {
+				t.Errorf("Navigate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
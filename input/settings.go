@@ -0,0 +1,205 @@
+package input
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ============================================
+// Settings - 按键设置界面
+// ============================================
+// 操作方式：
+// - ←→ / Tab: 切换游戏
+// - ↑↓: 选择动作
+// （方向和返回也可以使用贪吃蛇映射表中的移动和返回按键）
+// - Enter: 重新绑定（替换原有按键）
+// - A: 追加一个按键
+// - D: 恢复当前游戏的默认按键
+// - Esc: 保存并返回
+
+type Settings struct {
+	screen   tcell.Screen
+	bindings *Bindings
+	tab      int    // 当前游戏的映射表索引
+	selected int    // 当前选中的动作索引
+	capture  bool   // 是否正在等待用户按下新按键
+	appendTo bool   // 捕获的按键是追加还是替换
+	message  string // 底部状态提示
+}
+
+// NewSettings 创建按键设置界面
+func NewSettings(screen tcell.Screen, bindings *Bindings) *Settings {
+	return &Settings{
+		screen:   screen,
+		bindings: bindings,
+	}
+}
+
+// keymap 返回当前标签页的映射表
+func (s *Settings) keymap() *Keymap {
+	return s.bindings.Keymaps()[s.tab]
+}
+
+// drawText 在指定位置绘制一行文字
+func (s *Settings) drawText(x, y int, text string, style tcell.Style) {
+	for i, ch := range []rune(text) {
+		s.screen.SetContent(x+i, y, ch, nil, style)
+	}
+}
+
+// Render 绘制设置界面
+func (s *Settings) Render() {
+	s.screen.Clear()
+	s.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true)
+	normalStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorLime).Bold(true)
+	hintStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+
+	s.drawText(10, 2, "KEY BINDINGS", titleStyle)
+
+	// 游戏标签页
+	x := 6
+	for i, m := range s.bindings.Keymaps() {
+		label := fmt.Sprintf("[ %s ]", m.Name)
+		style := normalStyle
+		if i == s.tab {
+			style = selectedStyle
+		}
+		s.drawText(x, 4, label, style)
+		x += len(label) + 2
+	}
+
+	// 动作列表
+	m := s.keymap()
+	for i, a := range m.Actions {
+		style := normalStyle
+		prefix := "  "
+		if i == s.selected {
+			style = selectedStyle
+			prefix = "► "
+		}
+		s.drawText(6, 6+i, fmt.Sprintf("%s%-10s %s", prefix, a.Desc(), m.Label(a)), style)
+	}
+
+	// 状态提示
+	y := 7 + len(m.Actions)
+	if s.capture {
+		s.drawText(6, y, fmt.Sprintf("Press a key for %s (Esc to cancel)", m.Actions[s.selected].Desc()), selectedStyle)
+	} else if s.message != "" {
+		s.drawText(6, y, s.message, normalStyle)
+	}
+
+	hints := []string{
+		"←→ : Switch game   ↑↓ : Select",
+		"Enter : Rebind     A : Add key",
+		"D : Defaults       Esc : Save & Back",
+	}
+	for i, hint := range hints {
+		s.drawText(6, y+2+i, hint, hintStyle)
+	}
+
+	s.screen.Show()
+}
+
+// Run 运行设置界面，按 Esc 保存并返回
+func (s *Settings) Run() {
+	s.Render()
+
+	for {
+		switch ev := s.screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			if s.capture {
+				s.handleCapture(ev)
+				s.Render()
+				continue
+			}
+
+			m := s.keymap()
+			s.message = ""
+			// 设置界面自己的热键优先，其余按键按映射表解释为导航
+			switch {
+			case ev.Key() == tcell.KeyRune && (ev.Rune() == 'a' || ev.Rune() == 'A'):
+				s.capture, s.appendTo = true, true
+			case ev.Key() == tcell.KeyRune && (ev.Rune() == 'd' || ev.Rune() == 'D'):
+				s.resetDefaults()
+			case ev.Key() == tcell.KeyTab:
+				s.switchTab(1)
+			default:
+				switch s.bindings.Navigate(ev) {
+				case NavBack:
+					if err := s.bindings.Save(); err != nil {
+						s.message = fmt.Sprintf("Save failed: %v", err)
+						s.Render()
+						continue
+					}
+					return
+				case NavUp:
+					if s.selected > 0 {
+						s.selected--
+					}
+				case NavDown:
+					if s.selected < len(m.Actions)-1 {
+						s.selected++
+					}
+				case NavLeft:
+					s.switchTab(-1)
+				case NavRight:
+					s.switchTab(1)
+				case NavSelect:
+					s.capture, s.appendTo = true, false
+				}
+			}
+			s.Render()
+
+		case *tcell.EventResize:
+			s.Render()
+
+		case nil:
+			return
+		}
+	}
+}
+
+// handleCapture 处理等待绑定状态下的按键
+func (s *Settings) handleCapture(ev *tcell.EventKey) {
+	s.capture = false
+	if ev.Key() == tcell.KeyEscape {
+		return
+	}
+
+	m := s.keymap()
+	a := m.Actions[s.selected]
+	if s.appendTo {
+		m.Add(a, KeyOf(ev))
+	} else {
+		m.Set(a, KeyOf(ev))
+	}
+
+	// 被抢走按键的动作不能没有任何绑定
+	for _, other := range m.Actions {
+		if len(m.Keys(other)) == 0 {
+			s.message = fmt.Sprintf("Warning: %s has no key", other.Desc())
+		}
+	}
+}
+
+// switchTab 切换到相邻的游戏
+func (s *Settings) switchTab(delta int) {
+	n := len(s.bindings.Keymaps())
+	s.tab = (s.tab + delta + n) % n
+	s.selected = 0
+}
+
+// resetDefaults 恢复当前游戏的默认按键
+func (s *Settings) resetDefaults() {
+	switch s.tab {
+	case 0:
+		s.bindings.Tetris = DefaultTetris()
+	case 1:
+		s.bindings.Snake = DefaultSnake()
+	}
+	s.message = "Defaults restored"
+}
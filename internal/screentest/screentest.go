@@ -0,0 +1,226 @@
+// Package screentest 提供基于 tcell.SimulationScreen 的渲染测试工具：
+// 把屏幕内容（字符和样式）转成稳定的文本，并与 testdata 下的 golden 文件比较。
+//
+// 使用 go test ./... -update 重新生成 golden 文件。
+package screentest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// update 为 true 时用当前输出覆盖 golden 文件
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// Width, Height 默认模拟屏幕尺寸（标准 80x24 终端再留一行余量）
+const (
+	Width  = 80
+	Height = 25
+)
+
+// NewScreen 创建并初始化模拟屏幕，测试结束时自动关闭
+func NewScreen(t testing.TB, width, height int) tcell.SimulationScreen {
+	t.Helper()
+	s := tcell.NewSimulationScreen("UTF-8")
+	if err := s.Init(); err != nil {
+		t.Fatalf("init simulation screen: %v", err)
+	}
+	s.SetSize(width, height)
+	t.Cleanup(s.Fini)
+	return s
+}
+
+// ============================================
+// 屏幕内容转文本
+// ============================================
+// 输出分三段：
+//   -- text --    每行的字符（去掉行尾空格）
+//   -- styles --  每个单元格的样式编号（与字符一一对应，'.' 表示默认样式的空格）
+//   -- legend --  样式编号对应的前景色/背景色/属性
+
+// Dump 将屏幕内容转为文本
+func Dump(s tcell.SimulationScreen) string {
+	cells, width, height := s.GetContents()
+
+	var text, styles strings.Builder
+	legend := []tcell.Style{}
+	index := map[tcell.Style]int{}
+
+	for y := 0; y < height; y++ {
+		var line, styleLine strings.Builder
+		for x := 0; x < width; x++ {
+			cell := cells[y*width+x]
+			ch := ' '
+			if len(cell.Runes) > 0 {
+				ch = cell.Runes[0]
+			}
+			line.WriteRune(ch)
+
+			if ch == ' ' && cell.Style == tcell.StyleDefault {
+				styleLine.WriteByte('.')
+				continue
+			}
+			i, ok := index[cell.Style]
+			if !ok {
+				i = len(legend)
+				index[cell.Style] = i
+				legend = append(legend, cell.Style)
+			}
+			styleLine.WriteByte(styleKey(i))
+		}
+		text.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+		styles.WriteString(strings.TrimRight(styleLine.String(), ".") + "\n")
+	}
+
+	var out strings.Builder
+	out.WriteString("-- text --\n")
+	out.WriteString(trimTrailingBlankLines(text.String()))
+	out.WriteString("-- styles --\n")
+	out.WriteString(trimTrailingBlankLines(styles.String()))
+	out.WriteString("-- legend --\n")
+	for i, style := range legend {
+		fmt.Fprintf(&out, "%c: %s\n", styleKey(i), describe(style))
+	}
+	return out.String()
+}
+
+// styleKey 样式编号对应的字符：0-9, a-z, A-Z
+func styleKey(i int) byte {
+	const keys = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	if i < len(keys) {
+		return keys[i]
+	}
+	return '?'
+}
+
+// describe 样式的稳定文字描述
+func describe(style tcell.Style) string {
+	fg, bg, attr := style.Decompose()
+	desc := fmt.Sprintf("fg=%s bg=%s", colorName(fg), colorName(bg))
+	for _, a := range []struct {
+		mask tcell.AttrMask
+		name string
+	}{
+		{tcell.AttrBold, "bold"},
+		{tcell.AttrDim, "dim"},
+		{tcell.AttrItalic, "italic"},
+		{tcell.AttrReverse, "reverse"},
+		{tcell.AttrBlink, "blink"},
+		{tcell.AttrStrikeThrough, "strikethrough"},
+	} {
+		if attr&a.mask != 0 {
+			desc += " " + a.name
+		}
+	}
+	return desc
+}
+
+// colorName 颜色名称
+// tcell 的颜色名表中同一颜色有多个名字（例如 aqua/cyan），这里统一使用十六进制
+func colorName(c tcell.Color) string {
+	if c == tcell.ColorDefault {
+		return "default"
+	}
+	return fmt.Sprintf("#%06x", c.Hex())
+}
+
+// trimTrailingBlankLines 去掉末尾的空行
+func trimTrailingBlankLines(s string) string {
+	return strings.TrimRight(s, "\n") + "\n"
+}
+
+// ============================================
+// Golden 文件比较
+// ============================================
+
+// AssertGolden 将 got 与 testdata/<name>.golden 比较
+// 带 -update 参数运行时改为写入 golden 文件
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch (run with -update to accept):\n%s", path, diff(string(want), got))
+	}
+}
+
+// AssertScreen 将屏幕内容与 golden 文件比较
+func AssertScreen(t testing.TB, name string, s tcell.SimulationScreen) {
+	t.Helper()
+	AssertGolden(t, name, Dump(s))
+}
+
+// diff 逐行对比，只列出不同的行
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Fprintf(&b, "line %d:\n  want: %q\n  got:  %q\n", i+1, w, g)
+		}
+	}
+	return b.String()
+}
+
+// ============================================
+// 脚本化输入
+// ============================================
+
+// Key 构造一个特殊键事件（方向键、Esc 等）
+func Key(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModNone)
+}
+
+// Rune 构造一个字符键事件
+func Rune(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+// Type 在后台依次注入按键，每个按键之间间隔 delay
+// 返回的 channel 在全部注入完成后关闭
+func Type(s tcell.SimulationScreen, delay time.Duration, keys ...*tcell.EventKey) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, k := range keys {
+			time.Sleep(delay)
+			s.InjectKey(k.Key(), k.Rune(), k.Modifiers())
+		}
+	}()
+	return done
+}
+
+// Contains 屏幕上是否出现了指定文字
+func Contains(s tcell.SimulationScreen, text string) bool {
+	dump := Dump(s)
+	return strings.Contains(dump[:strings.Index(dump, "-- styles --")], text)
+}
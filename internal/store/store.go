@@ -0,0 +1,72 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// ============================================
+// 本地存档 - 配置、记录等 JSON 文件的读写
+// ============================================
+
+// EnvHome 环境变量：覆盖默认存档目录（便于测试和多用户共用一台机器）
+const EnvHome = "GO_GAME_HOME"
+
+// Dir 返回存档目录，不存在时自动创建
+// 默认位于用户配置目录下的 go-game 子目录
+func Dir() (string, error) {
+	dir := os.Getenv(EnvHome)
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(base, "go-game")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Path 返回存档目录下指定文件的完整路径
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Load 读取 JSON 存档到 v
+// 文件不存在时返回的错误满足 errors.Is(err, os.ErrNotExist)
+func Load(name string, v any) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Save 将 v 以 JSON 格式写入存档
+// 先写临时文件再重命名，避免写到一半时损坏原存档
+func Save(name string, v any) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,114 @@
+package textfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-game/internal/store"
+)
+
+// ============================================
+// 文本文件 - 关卡、谜题等纯文本数据的读取
+// ============================================
+// 文件格式的共同部分：
+//
+//   # 以 # 加空格开头的行是注释，空行被忽略
+//   key: value
+//   其余行是数据（地图、面板等），由各自的解析函数处理
+//
+// 内置文件嵌入在程序中，用户文件放在存档目录的子目录下，两者一起加载
+
+// Scan 逐行读取 r，跳过空行和注释
+// "键: 值" 形式的行交给 prop，其余行交给 row；line 是从 1 开始的行号，
+// 回调返回的错误会加上行号
+func Scan(r io.Reader, prop func(key, value string) error, row func(text string) error) error {
+	scanner := bufio.NewScanner(r)
+	for line := 0; scanner.Scan(); {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if text == "" || text == "#" || strings.HasPrefix(text, "# ") {
+			continue
+		}
+
+		var err error
+		if key, value, ok := strings.Cut(text, ":"); ok {
+			err = prop(strings.TrimSpace(key), strings.TrimSpace(value))
+		} else {
+			err = row(text)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Load 加载全部文件：先是 builtin 中 builtinDir 目录下的内置文件，然后是存档目录 userDir 下的用户文件
+// 只读取扩展名为 ext 的文件，两组各自按文件名排序；parse 的 name 参数是去掉扩展名的文件名
+// 内置文件出错是程序缺陷（有测试保证不会发生），直接 panic；
+// 无法解析的用户文件会被跳过，错误合并后一起返回
+func Load[T any](builtin fs.FS, builtinDir, userDir, ext string, parse func(name string, r io.Reader) (T, error)) ([]T, error) {
+	var items []T
+	var errs []error
+
+	names, _ := fs.Glob(builtin, builtinDir+"/*"+ext)
+	for _, name := range names {
+		item, err := load(builtin, name, ext, parse)
+		if err != nil {
+			panic(err)
+		}
+		items = append(items, item)
+	}
+
+	dir, err := store.Path(userDir)
+	if err != nil {
+		return items, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	if err != nil {
+		return items, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		item, err := load(os.DirFS(dir), filepath.Base(file), ext, parse)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, errors.Join(errs...)
+}
+
+// load 从文件系统读取并解析一个文件，错误信息以文件名开头
+func load[T any](fsys fs.FS, name, ext string, parse func(name string, r io.Reader) (T, error)) (T, error) {
+	var zero T
+	f, err := fsys.Open(name)
+	if err != nil {
+		return zero, err
+	}
+	defer f.Close()
+	item, err := parse(strings.TrimSuffix(path.Base(name), ext), f)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", path.Base(name), err)
+	}
+	return item, nil
+}
+
+// Find 按名称查找（不区分大小写），nameOf 返回一项的名称；找不到时 ok 为 false
+func Find[T any](items []T, name string, nameOf func(T) string) (item T, ok bool) {
+	for _, item := range items {
+		if strings.EqualFold(nameOf(item), name) {
+			return item, true
+		}
+	}
+	return item, false
+}
@@ -1,169 +1,141 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/gdamore/tcell/v2"
+	"go-game/daily"
+	"go-game/input"
 	snakepkg "go-game/snake"
+	_ "go-game/snake/ai" // 注册贪吃蛇的电脑控制器（自动演示和电脑对手）
 	tetrispkg "go-game/tetris"
 )
 
-// ============================================
-// 游戏类型
-// ============================================
-
-type GameType int
-
-const (
-	GameTetris GameType = iota
-	GameSnake
-)
+// version 程序版本号，发布时通过 -ldflags "-X main.version=..." 注入
+var version = "dev"
 
 // ============================================
-// Menu - 主菜单
+// 主程序入口
 // ============================================
 
-type Menu struct {
-	screen   tcell.Screen
-	selected int
-	options  []string
-}
+func main() {
+	err := runCLI(os.Args[1:])
+	if err == nil {
+		return
+	}
 
-// NewMenu 创建新菜单
-func NewMenu(screen tcell.Screen) *Menu {
-	return &Menu{
-		screen:   screen,
-		selected: 0,
-		options: []string{
-			"► 俄罗斯方块",
-			"○ 贪吃蛇",
-			"  退出游戏",
-		},
+	fmt.Fprintf(os.Stderr, "go-game: %v\n", err)
+	var usage usageError
+	if errors.As(err, &usage) {
+		fmt.Fprintf(os.Stderr, "Run 'go-game %s--help' for usage.\n", usage.command)
+		os.Exit(2)
 	}
+	os.Exit(1)
 }
 
-// Render 绘制菜单
-func (m *Menu) Render() {
-	m.screen.Clear()
-	m.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
-
-	// 标题
-	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true)
-	title := "TERMINAL GAMES"
-	for i, ch := range title {
-		m.screen.SetContent(10+i, 3, ch, nil, titleStyle)
+// withScreen 初始化终端屏幕并执行 fn，结束后恢复终端
+func withScreen(fn func(screen tcell.Screen, bindings *input.Bindings) error) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("failed to create screen: %w", err)
 	}
-
-	// 副标题
-	subtitleStyle := tcell.StyleDefault.Foreground(tcell.ColorGray)
-	subtitle := "Select a game to play"
-	for i, ch := range subtitle {
-		m.screen.SetContent(7+i, 5, ch, nil, subtitleStyle)
+	if err := screen.Init(); err != nil {
+		return fmt.Errorf("failed to initialize screen: %w", err)
 	}
+	defer screen.Fini()
 
-	// 菜单选项
-	for i, option := range m.options {
-		var style tcell.Style
-		if i == m.selected {
-			style = tcell.StyleDefault.Foreground(tcell.ColorLime).Bold(true)
-		} else {
-			style = tcell.StyleDefault.Foreground(tcell.ColorWhite)
-		}
-		for j, ch := range option {
-			m.screen.SetContent(8+j, 10+i*2, ch, nil, style)
-		}
-	}
+	screen.EnablePaste()
+	screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
 
-	// 操作提示
-	hintStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
-	hints := []string{
-		"↑↓ : Select",
-		"Enter : Confirm",
-		"Q : Quit",
-	}
-	for i, hint := range hints {
-		for j, ch := range hint {
-			m.screen.SetContent(8+j, 20+i, ch, nil, hintStyle)
-		}
+	// 加载按键配置（读取失败时使用默认按键）
+	bindings, err := input.LoadBindings()
+	if err != nil {
+		bindings = input.DefaultBindings()
 	}
 
-	m.screen.Show()
+	return fn(screen, bindings)
 }
 
-// Run 运行菜单，返回选择的游戏类型
-func (m *Menu) Run() GameType {
-	m.Render()
-
+// runMenu 显示主菜单，循环进入所选游戏
+func runMenu(screen tcell.Screen, bindings *input.Bindings) error {
 	for {
-		if m.screen.HasPendingEvent() {
-			event := m.screen.PollEvent()
-			if event != nil {
-				switch ev := event.(type) {
-				case *tcell.EventKey:
-					if ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' || ev.Rune() == 'Q' {
-						os.Exit(0)
-					}
+		menu := NewMenu(screen, bindings)
+		gameType := menu.Run()
 
-					switch ev.Key() {
-					case tcell.KeyUp:
-						if m.selected > 0 {
-							m.selected--
-							m.Render()
-						}
-					case tcell.KeyDown:
-						if m.selected < len(m.options)-1 {
-							m.selected++
-							m.Render()
-						}
-					case tcell.KeyEnter:
-						switch m.selected {
-						case 0:
-							return GameTetris
-						case 1:
-							return GameSnake
-						case 2:
-							os.Exit(0)
-						}
-					}
-				case *tcell.EventResize:
-					m.Render()
-				}
-			}
+		var err error
+		switch gameType {
+		case GameTetris:
+			err = tetrispkg.Run(screen, bindings.Tetris, tetrispkg.Config{})
+		case GameSnake:
+			err = runSnake(screen, bindings)
+		case GameSettings:
+			input.NewSettings(screen, bindings).Run()
+		case GameStats:
+			snakepkg.NewStatsScreen(screen).Run()
+		case GameDaily:
+			err = runDaily(screen, bindings)
+		case GamePuzzles:
+			err = runPuzzles(screen, bindings)
+		}
+		if err != nil {
+			return err
 		}
 	}
 }
 
-// ============================================
-// 主程序入口
-// ============================================
-
-func main() {
-	// 初始化屏幕
-	screen, err := tcell.NewScreen()
+// runSnake 显示贪吃蛇选项菜单，每局结束后回到选项菜单，直到玩家返回主菜单
+func runSnake(screen tcell.Screen, bindings *input.Bindings) error {
+	cfg, err := snakepkg.LoadConfig()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create screen: %v\n", err)
-		os.Exit(1)
+		cfg = snakepkg.Config{}
 	}
-	if err := screen.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize screen: %v\n", err)
-		os.Exit(1)
+	for {
+		start, err := snakepkg.NewOptionsMenu(screen, bindings.Snake, &cfg).Run()
+		if err != nil || !start {
+			return err
+		}
+		if err := snakepkg.Run(screen, bindings.Snake, cfg); err != nil {
+			return err
+		}
 	}
-	defer screen.Fini()
-
-	screen.EnablePaste()
-	screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+}
 
-	// 主循环
+// runDaily 显示每日挑战界面，每局结束后回到该界面，直到玩家返回主菜单
+func runDaily(screen tcell.Screen, bindings *input.Bindings) error {
 	for {
-		menu := NewMenu(screen)
-		gameType := menu.Run()
+		date := daily.Today()
+		game, ok := NewDailyScreen(screen, bindings, date).Run()
+		if !ok {
+			return nil
+		}
+		var err error
+		switch game {
+		case tetrispkg.DailyGame:
+			err = tetrispkg.Run(screen, bindings.Tetris, tetrispkg.Config{Daily: date})
+		case snakepkg.DailyGame:
+			err = snakepkg.Run(screen, bindings.Snake, snakepkg.Config{Daily: date})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
 
-		switch gameType {
-		case GameTetris:
-			tetrispkg.Run(screen)
-		case GameSnake:
-			snakepkg.Run(screen)
+// runPuzzles 显示谜题列表，每局结束后回到列表（保持选中刚玩的谜题），直到玩家返回主菜单
+func runPuzzles(screen tcell.Screen, bindings *input.Bindings) error {
+	selected := 0
+	for {
+		puzzles := NewPuzzleScreen(screen, bindings, selected)
+		index, ok := puzzles.Run()
+		if !ok {
+			return nil
+		}
+		selected = index
+		cfg := tetrispkg.Config{Puzzle: puzzles.Puzzle(index).Name}
+		if err := tetrispkg.Run(screen, bindings.Tetris, cfg); err != nil {
+			return err
 		}
 	}
 }
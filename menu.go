@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+)
+
+// ============================================
+// 游戏类型
+// ============================================
+
+type GameType int
+
+const (
+	GameTetris GameType = iota
+	GameSnake
+	GameSettings // 按键设置（不是游戏，但同样由菜单选择）
+	GameStats    // 贪吃蛇累计统计
+	GameDaily    // 每日挑战
+	GamePuzzles  // 俄罗斯方块谜题
+)
+
+// ============================================
+// Menu - 主菜单
+// ============================================
+
+type Menu struct {
+	screen   tcell.Screen
+	bindings *input.Bindings // 菜单导航使用的按键
+	selected int
+	options  []string
+}
+
+// NewMenu 创建新菜单
+func NewMenu(screen tcell.Screen, bindings *input.Bindings) *Menu {
+	return &Menu{
+		screen:   screen,
+		bindings: bindings,
+		selected: 0,
+		options: []string{
+			"► 俄罗斯方块",
+			"○ 贪吃蛇",
+			"  按键设置",
+			"  贪吃蛇统计",
+			"  每日挑战",
+			"  方块谜题",
+			"  退出游戏",
+		},
+	}
+}
+
+// Render 绘制菜单
+func (m *Menu) Render() {
+	m.screen.Clear()
+	m.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+
+	// 标题
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true)
+	title := "TERMINAL GAMES"
+	for i, ch := range title {
+		m.screen.SetContent(10+i, 3, ch, nil, titleStyle)
+	}
+
+	// 副标题
+	subtitleStyle := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	subtitle := "Select a game to play"
+	for i, ch := range subtitle {
+		m.screen.SetContent(7+i, 5, ch, nil, subtitleStyle)
+	}
+
+	// 菜单选项
+	for i, option := range m.options {
+		var style tcell.Style
+		if i == m.selected {
+			style = tcell.StyleDefault.Foreground(tcell.ColorLime).Bold(true)
+		} else {
+			style = tcell.StyleDefault.Foreground(tcell.ColorWhite)
+		}
+		for j, ch := range option {
+			m.screen.SetContent(8+j, 7+i*2, ch, nil, style)
+		}
+	}
+
+	// 操作提示
+	hintStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	hints := []string{
+		"↑↓ : Select",
+		"Enter : Confirm",
+		"Q : Quit",
+	}
+	for i, hint := range hints {
+		for j, ch := range []rune(hint) {
+			m.screen.SetContent(8+j, 8+len(m.options)*2+i, ch, nil, hintStyle)
+		}
+	}
+
+	m.screen.Show()
+}
+
+// Run 运行菜单，返回选择的游戏类型
+func (m *Menu) Run() GameType {
+	m.Render()
+
+	for {
+		if m.screen.HasPendingEvent() {
+			event := m.screen.PollEvent()
+			if event != nil {
+				switch ev := event.(type) {
+				case *tcell.EventKey:
+					if ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' || ev.Rune() == 'Q' {
+						m.screen.Fini()
+						os.Exit(0)
+					}
+
+					switch m.bindings.Navigate(ev) {
+					case input.NavUp:
+						if m.selected > 0 {
+							m.selected--
+							m.Render()
+						}
+					case input.NavDown:
+						if m.selected < len(m.options)-1 {
+							m.selected++
+							m.Render()
+						}
+					case input.NavSelect:
+						switch m.selected {
+						case 0:
+							return GameTetris
+						case 1:
+							return GameSnake
+						case 2:
+							return GameSettings
+						case 3:
+							return GameStats
+						case 4:
+							return GameDaily
+						case 5:
+							return GamePuzzles
+						case 6:
+							m.screen.Fini()
+							os.Exit(0)
+						}
+					}
+				case *tcell.EventResize:
+					m.Render()
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+)
+
+func TestMenuRenderGolden(t *testing.T) {
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewMenu(screen, input.DefaultBindings()).Render()
+	screentest.AssertScreen(t, "menu", screen)
+}
+
+// TestMenuRunSelection 用模拟按键在菜单中选择
+func TestMenuRunSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []*tcell.EventKey
+		want GameType
+	}{
+		{"tetris", []*tcell.EventKey{screentest.Key(tcell.KeyEnter)}, GameTetris},
+		{"snake", []*tcell.EventKey{screentest.Key(tcell.KeyDown), screentest.Key(tcell.KeyEnter)}, GameSnake},
+		{"settings", []*tcell.EventKey{
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyEnter),
+		}, GameSettings},
+		{"stats", []*tcell.EventKey{
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyEnter),
+		}, GameStats},
+		{"daily", []*tcell.EventKey{
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyEnter),
+		}, GameDaily},
+		{"puzzles", []*tcell.EventKey{
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyEnter),
+		}, GamePuzzles},
+		{"up stops at top", []*tcell.EventKey{
+			screentest.Key(tcell.KeyUp),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyEnter),
+		}, GameSnake},
+		{"keymap movement", []*tcell.EventKey{
+			screentest.Rune('j'),
+			screentest.Rune('j'),
+			screentest.Rune('k'),
+			screentest.Key(tcell.KeyEnter),
+		}, GameSnake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+			screentest.Type(screen, time.Millisecond, tt.keys...)
+			if got := NewMenu(screen, input.DefaultBindings()).Run(); got != tt.want {
+				t.Errorf("Run() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
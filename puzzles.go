@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	tetrispkg "go-game/tetris"
+)
+
+// ============================================
+// PuzzleScreen - 方块谜题
+// ============================================
+// 列出全部谜题（内置谜题包和存档目录中的用户谜题），标出已经解开的谜题，
+// 选择谜题后开始游戏
+
+// puzzleRows 界面上同时显示的谜题行数，更多谜题时随选中项滚动
+const puzzleRows = 12
+
+type PuzzleScreen struct {
+	screen   tcell.Screen
+	bindings *input.Bindings // 界面导航使用的按键，俄罗斯方块的按键还用于提示撤销、重做和重来
+	puzzles  []*tetrispkg.Puzzle
+	solved   map[string]time.Time // 已解开的谜题：谜题名 -> 第一次解开的时间
+	selected int
+	err      error // 读取谜题或解题记录失败的原因，界面上会显示出来
+}
+
+// NewPuzzleScreen 读取谜题和解题记录并创建谜题界面，selected 是默认选中的谜题
+func NewPuzzleScreen(screen tcell.Screen, bindings *input.Bindings, selected int) *PuzzleScreen {
+	p := &PuzzleScreen{screen: screen, bindings: bindings}
+	p.puzzles, p.err = tetrispkg.LoadPuzzles()
+	solved, err := tetrispkg.LoadSolved()
+	if err != nil {
+		p.err = err
+	}
+	p.solved = solved
+	p.selected = max(min(selected, len(p.puzzles)-1), 0)
+	return p
+}
+
+// Render 绘制谜题列表：每行是解开标记、谜题名、目标和方块数
+func (p *PuzzleScreen) Render() {
+	p.screen.Clear()
+	p.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true)
+	normalStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorLime).Bold(true)
+	hintStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+
+	solved := 0
+	for _, puzzle := range p.puzzles {
+		if _, ok := p.solved[puzzle.Name]; ok {
+			solved++
+		}
+	}
+	drawText(p.screen, 10, 3, "TETRIS PUZZLES", titleStyle)
+	drawText(p.screen, 10, 4, fmt.Sprintf("Solved %d/%d", solved, len(p.puzzles)), hintStyle)
+
+	// 选中项超出可见范围时滚动
+	first := max(p.selected-puzzleRows+1, 0)
+	for i := first; i < min(len(p.puzzles), first+puzzleRows); i++ {
+		puzzle := p.puzzles[i]
+		check := " "
+		if _, ok := p.solved[puzzle.Name]; ok {
+			check = "✓"
+		}
+		marker, style := "  ", normalStyle
+		if i == p.selected {
+			marker, style = "► ", selectedStyle
+		}
+		row := fmt.Sprintf("%s%s %-18s %-20s %d pcs", marker, check, puzzle.Name, puzzle.Goal, len(puzzle.Pieces))
+		drawText(p.screen, 6, 6+i-first, row, style)
+	}
+	if len(p.puzzles) == 0 {
+		drawText(p.screen, 8, 6, "No puzzles found", hintStyle)
+	}
+	if p.err != nil {
+		drawText(p.screen, 6, 7+puzzleRows, "Could not load puzzles: "+p.err.Error(), normalStyle)
+	}
+
+	hints := []string{
+		"No gravity: pieces fall only when you drop them",
+		fmt.Sprintf("%s / %s : Undo / Redo a piece   %s : Retry", p.bindings.Tetris.Label(input.Undo), p.bindings.Tetris.Label(input.Redo), p.bindings.Tetris.Label(input.Restart)),
+		"↑↓ : Select   Enter : Play   Esc : Back",
+	}
+	for i, hint := range hints {
+		drawText(p.screen, 6, 21+i, hint, hintStyle)
+	}
+
+	p.screen.Show()
+}
+
+// Run 显示界面直到玩家选择谜题或返回，返回所选谜题的下标（返回时 ok 为 false）
+func (p *PuzzleScreen) Run() (index int, ok bool) {
+	p.Render()
+	for {
+		switch ev := p.screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			if ev.Rune() == 'q' || ev.Rune() == 'Q' {
+				return 0, false
+			}
+			switch p.bindings.Navigate(ev) {
+			case input.NavBack:
+				return 0, false
+			case input.NavUp:
+				p.selected = max(p.selected-1, 0)
+				p.Render()
+			case input.NavDown:
+				p.selected = max(min(p.selected+1, len(p.puzzles)-1), 0)
+				p.Render()
+			case input.NavSelect:
+				if len(p.puzzles) > 0 {
+					return p.selected, true
+				}
+			}
+		case *tcell.EventResize:
+			p.Render()
+		case nil:
+			return 0, false
+		}
+	}
+}
+
+// Puzzle 返回下标为 index 的谜题
+func (p *PuzzleScreen) Puzzle(index int) *tetrispkg.Puzzle {
+	return p.puzzles[index]
+}
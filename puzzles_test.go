@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+	tetrispkg "go-game/tetris"
+)
+
+// solveWarmUp 在临时存档中通过界面解开第一个谜题
+func solveWarmUp(t *testing.T) {
+	t.Helper()
+	t.Setenv(store.EnvHome, t.TempDir())
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	keys := []*tcell.EventKey{screentest.Key(tcell.KeyUp)}
+	for i := 0; i < 6; i++ {
+		keys = append(keys, screentest.Key(tcell.KeyRight))
+	}
+	keys = append(keys, screentest.Rune(' '), screentest.Key(tcell.KeyEscape))
+	screentest.Type(screen, time.Millisecond, keys...)
+	if err := tetrispkg.Run(screen, input.DefaultTetris(), tetrispkg.Config{Puzzle: "Warm Up"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestPuzzleScreenGolden(t *testing.T) {
+	solveWarmUp(t)
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewPuzzleScreen(screen, input.DefaultBindings(), 1).Render()
+	screentest.AssertScreen(t, "puzzles", screen)
+}
+
+// TestPuzzleScreenRun 用模拟按键选择谜题，选择不会越过列表两端
+func TestPuzzleScreenRun(t *testing.T) {
+	solveWarmUp(t)
+	tests := []struct {
+		name   string
+		keys   []*tcell.EventKey
+		want   string
+		wantOK bool
+	}{
+		{"first", []*tcell.EventKey{screentest.Key(tcell.KeyUp), screentest.Key(tcell.KeyEnter)}, "Warm Up", true},
+		{"last", []*tcell.EventKey{
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyDown),
+			screentest.Key(tcell.KeyEnter),
+		}, "T-Spin Triple", true},
+		{"back", []*tcell.EventKey{screentest.Key(tcell.KeyEscape)}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+			screentest.Type(screen, time.Millisecond, tt.keys...)
+			p := NewPuzzleScreen(screen, input.DefaultBindings(), 0)
+			index, ok := p.Run()
+			got := ""
+			if ok {
+				got = p.Puzzle(index).Name
+			}
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Run() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.json")
+	r := New("snake", 42)
+	r.Record(100*time.Millisecond, Tick)
+	r.Record(250*time.Millisecond, "MoveLeft")
+	if err := r.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Version != Version || loaded.Seed != 42 || len(loaded.Events) != 2 || loaded.Duration() != 250*time.Millisecond {
+		t.Errorf("loaded = %+v", loaded)
+	}
+}
+
+// TestLoadVersions 旧版本的俄罗斯方块录像仍然可以回放，旧版本的贪吃蛇录像和未来的版本给出明确的错误
+func TestLoadVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string // 期望的错误，空表示可以读取
+	}{
+		{"tetris v1", `{"version":1,"game":"tetris","seed":1,"events":[]}`, ""},
+		{"snake v1", `{"version":1,"game":"snake","seed":1,"events":[]}`, "snake replay version 1 was recorded by an older release"},
+		{"snake current", `{"version":2,"game":"snake","seed":1,"events":[]}`, ""},
+		{"future", `{"version":99,"game":"tetris","seed":1,"events":[]}`, "unsupported replay version 99"},
+		{"missing version", `{"game":"tetris","seed":1,"events":[]}`, "unsupported replay version 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "game.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("Load = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package scores
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"go-game/internal/store"
+)
+
+// ============================================
+// 高分榜 - 每个游戏/模式一张表
+// ============================================
+
+// scoresFile 高分榜在存档目录中的文件名
+const scoresFile = "scores.json"
+
+// MaxEntries 每张表保留的记录数
+const MaxEntries = 10
+
+// Order 排行方式
+type Order int
+
+const (
+	HigherIsBetter Order = iota // 得分类：数值越大越好
+	LowerIsBetter               // 计时类：数值越小越好
+)
+
+// Entry 一条记录
+type Entry struct {
+	Value  int64     `json:"value"`            // 用于排序的数值（得分或毫秒数）
+	Label  string    `json:"label"`            // 数值的显示形式，例如 "1200" 或 "1:05.300"
+	Detail string    `json:"detail,omitempty"` // 附加信息，例如 "LINES 12"
+	Seed   int64     `json:"seed"`             // 对局使用的随机种子
+	Date   time.Time `json:"date"`
+}
+
+// Table 一张高分榜
+type Table struct {
+	Name    string  `json:"name"`
+	Order   Order   `json:"order"`
+	Entries []Entry `json:"entries"`
+}
+
+// better 判断 a 是否排在 b 前面
+func (t *Table) better(a, b Entry) bool {
+	if t.Order == LowerIsBetter {
+		return a.Value < b.Value
+	}
+	return a.Value > b.Value
+}
+
+// insert 插入记录并保持排序，返回名次（从 1 开始，未上榜返回 0）
+func (t *Table) insert(e Entry) int {
+	t.Entries = append(t.Entries, e)
+	sort.SliceStable(t.Entries, func(i, j int) bool {
+		return t.better(t.Entries[i], t.Entries[j])
+	})
+	if len(t.Entries) > MaxEntries {
+		t.Entries = t.Entries[:MaxEntries]
+	}
+	for i := range t.Entries {
+		if t.Entries[i] == e {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Best 返回表中的最好成绩
+func (t *Table) Best() (Entry, bool) {
+	if t == nil || len(t.Entries) == 0 {
+		return Entry{}, false
+	}
+	return t.Entries[0], true
+}
+
+// ============================================
+// 读写
+// ============================================
+
+// Load 读取所有高分榜，按表名排序返回
+func Load() ([]*Table, error) {
+	all, err := loadAll()
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]*Table, 0, len(all))
+	for _, t := range all {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables, nil
+}
+
+// Get 读取指定名称的高分榜，不存在时返回 nil
+func Get(name string) (*Table, error) {
+	all, err := loadAll()
+	if err != nil {
+		return nil, err
+	}
+	return all[name], nil
+}
+
+// Submit 提交一条记录，返回名次（未上榜返回 0）
+func Submit(name string, order Order, e Entry) (int, error) {
+	all, err := loadAll()
+	if err != nil {
+		return 0, err
+	}
+	t, ok := all[name]
+	if !ok {
+		t = &Table{Name: name, Order: order}
+		all[name] = t
+	}
+	if e.Date.IsZero() {
+		e.Date = time.Now()
+	}
+	e.Date = e.Date.Truncate(time.Second)
+	rank := t.insert(e)
+	if rank == 0 {
+		return 0, nil
+	}
+	return rank, store.Save(scoresFile, all)
+}
+
+// loadAll 读取存档，文件不存在时返回空集合
+func loadAll() (map[string]*Table, error) {
+	all := map[string]*Table{}
+	if err := store.Load(scoresFile, &all); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,171 @@
+// Package ai 贪吃蛇的寻路控制器
+//
+// 控制器只读取 snake.State 快照，不依赖游戏内部状态，
+// 既可以驱动自动演示和电脑对手，也可以在测试中大量模拟对局：
+//
+//	g := snake.NewGame(snake.WithSeed(42))
+//	bot := ai.NewGreedy()
+//	for !g.Over() {
+//	    g.Step(bot.Next(g.State(), 0))
+//	}
+//
+// 导入本包即会把控制器注册到 snake 包（"greedy" 和 "hamiltonian"），
+// 供选项菜单和 --autopilot 参数按名称使用
+package ai
+
+import "go-game/snake"
+
+func init() {
+	snake.RegisterController("greedy", func() snake.Controller { return NewGreedy() })
+	snake.RegisterController("hamiltonian", func() snake.Controller { return NewHamiltonian() })
+}
+
+// directions 按固定顺序尝试的方向，保证相同的状态总是得到相同的选择
+var directions = []snake.Direction{snake.Up, snake.Down, snake.Left, snake.Right}
+
+// horizontal 是否为水平方向
+func horizontal(d snake.Direction) bool {
+	return d == snake.Left || d == snake.Right
+}
+
+// ============================================
+// grid - 寻路用的面板
+// ============================================
+
+// grid 面板上的占用情况，下标为 y*width+x
+type grid struct {
+	width, height int
+	wrap          bool
+	blocked       []bool
+}
+
+// newGrid 根据快照创建面板：障碍物和所有蛇的身体都不可通行
+// 控制器不利用传送门，把它们当作障碍物；巡逻障碍连同它沿巡逻方向两侧的格子都视为不可通行
+func newGrid(s snake.State) *grid {
+	g := &grid{width: s.Width, height: s.Height, wrap: s.Wrap, blocked: make([]bool, s.Width*s.Height)}
+	for _, p := range s.Walls {
+		g.set(p, true)
+	}
+	for _, pt := range s.Portals {
+		g.set(pt.A, true)
+		g.set(pt.B, true)
+	}
+	for _, h := range s.Hazards {
+		g.set(h.Pos, true)
+		for _, d := range directions {
+			if n, ok := g.step(h.Pos, d); ok && horizontal(d) == horizontal(h.Dir) {
+				g.set(n, true)
+			}
+		}
+	}
+	for _, sn := range s.Snakes {
+		for _, p := range sn.Body {
+			g.set(p, true)
+		}
+	}
+	return g
+}
+
+// clone 复制面板
+func (g *grid) clone() *grid {
+	c := *g
+	c.blocked = append([]bool(nil), g.blocked...)
+	return &c
+}
+
+func (g *grid) index(p snake.Point) int {
+	return p.Y*g.width + p.X
+}
+
+func (g *grid) set(p snake.Point, blocked bool) {
+	g.blocked[g.index(p)] = blocked
+}
+
+// free 格子是否可以通行
+func (g *grid) free(p snake.Point) bool {
+	return !g.blocked[g.index(p)]
+}
+
+// step 从 p 朝 d 走一格，越界时返回 false（穿墙模式从另一侧出现）
+func (g *grid) step(p snake.Point, d snake.Direction) (snake.Point, bool) {
+	switch d {
+	case snake.Up:
+		p.Y--
+	case snake.Down:
+		p.Y++
+	case snake.Left:
+		p.X--
+	case snake.Right:
+		p.X++
+	}
+	if g.wrap {
+		p.X = (p.X + g.width) % g.width
+		p.Y = (p.Y + g.height) % g.height
+		return p, true
+	}
+	return p, p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// path 广度优先搜索从 from 到第一个满足 goal 的可通行格子的最短路径
+// 返回的路径不含 from，找不到时返回 nil
+func (g *grid) path(from snake.Point, goal func(snake.Point) bool) []snake.Point {
+	prev := make([]int, len(g.blocked))
+	for i := range prev {
+		prev[i] = -1
+	}
+	start := g.index(from)
+	prev[start] = start
+	queue := make([]snake.Point, 1, len(g.blocked))
+	queue[0] = from
+	for k := 0; k < len(queue); k++ {
+		p := queue[k]
+		if p != from && goal(p) {
+			var path []snake.Point
+			for i := g.index(p); i != start; i = prev[i] {
+				path = append(path, snake.Point{X: i % g.width, Y: i / g.width})
+			}
+			for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+				path[l], path[r] = path[r], path[l]
+			}
+			return path
+		}
+		for _, d := range directions {
+			n, ok := g.step(p, d)
+			if !ok || prev[g.index(n)] >= 0 || (!g.free(n) && !goal(n)) {
+				continue
+			}
+			prev[g.index(n)] = g.index(p)
+			queue = append(queue, n)
+		}
+	}
+	return nil
+}
+
+// space 从 from 出发能到达的可通行格子数（洪水填充）
+func (g *grid) space(from snake.Point) int {
+	seen := make([]bool, len(g.blocked))
+	seen[g.index(from)] = true
+	queue := make([]snake.Point, 1, len(g.blocked))
+	queue[0] = from
+	for k := 0; k < len(queue); k++ {
+		p := queue[k]
+		for _, d := range directions {
+			n, ok := g.step(p, d)
+			if ok && !seen[g.index(n)] && g.free(n) {
+				seen[g.index(n)] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return len(queue)
+}
+
+// direction 返回从 p 走到相邻格子 q 的方向
+func (g *grid) direction(p, q snake.Point) snake.Direction {
+	for _, d := range directions {
+		if n, ok := g.step(p, d); ok && n == q {
+			return d
+		}
+	}
+	return snake.Up
+}
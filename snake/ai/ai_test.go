@@ -0,0 +1,276 @@
+package ai
+
+import (
+	"slices"
+	"testing"
+
+	"go-game/snake"
+)
+
+// checkState 快照中的蛇、障碍物和食物互不重叠，蛇身连续且都在面板内
+func checkState(t *testing.T, s snake.State) {
+	t.Helper()
+	inside := func(p snake.Point) bool {
+		return p.X >= 0 && p.X < s.Width && p.Y >= 0 && p.Y < s.Height
+	}
+	adjacent := func(a, b snake.Point) bool {
+		dx, dy := abs(a.X-b.X), abs(a.Y-b.Y)
+		if s.Wrap {
+			dx, dy = min(dx, s.Width-dx), min(dy, s.Height-dy)
+		}
+		return dx+dy == 1
+	}
+
+	taken := map[snake.Point]string{}
+	for _, p := range s.Walls {
+		taken[p] = "wall"
+	}
+	for n, sn := range s.Snakes {
+		if len(sn.Body) == 0 {
+			// 竞技场中撞死的蛇会从面板上移除
+			if sn.Alive {
+				t.Fatalf("snake %d has no body", n)
+			}
+			continue
+		}
+		for k, p := range sn.Body {
+			if !inside(p) {
+				t.Fatalf("snake %d: %v outside the board", n, p)
+			}
+			if what, ok := taken[p]; ok {
+				t.Fatalf("snake %d: %v overlaps %s", n, p, what)
+			}
+			taken[p] = "snake"
+			if k > 0 && !adjacent(sn.Body[k-1], p) {
+				t.Fatalf("snake %d: body broken between %v and %v", n, sn.Body[k-1], p)
+			}
+		}
+	}
+	for _, f := range s.Foods {
+		if !inside(f.Pos) {
+			t.Fatalf("food %v outside the board", f)
+		}
+		if what, ok := taken[f.Pos]; ok {
+			t.Fatalf("food %v overlaps %s", f, what)
+		}
+		taken[f.Pos] = "food"
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// play 让 bots 控制对应的蛇（nil 表示保持方向）走最多 steps 步，每一步都检查快照
+func play(t *testing.T, g *snake.Game, steps int, bots ...snake.Controller) snake.State {
+	t.Helper()
+	s := g.State()
+	for i := 0; i < steps && !g.Over(); i++ {
+		for n, bot := range bots {
+			if bot != nil && s.Snakes[n].Alive {
+				g.TurnPlayer(n, bot.Next(s, n))
+			}
+		}
+		g.Step()
+		s = g.State()
+		checkState(t, s)
+	}
+	return s
+}
+
+// TestRegistered 导入本包后控制器可以按名称使用
+func TestRegistered(t *testing.T) {
+	names := snake.ControllerNames()
+	for _, want := range []string{"greedy", "hamiltonian"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("ControllerNames() = %v, missing %q", names, want)
+		}
+	}
+}
+
+// TestZigzagCycle 构造出的回路经过每个格子恰好一次，相邻两格（包括首尾）都相邻
+func TestZigzagCycle(t *testing.T) {
+	for _, size := range [][2]int{{2, 2}, {4, 4}, {5, 4}, {20, 14}, {20, 15}, {3, 6}} {
+		h := NewHamiltonian()
+		h.build(size[0], size[1])
+		if h.err != nil {
+			t.Fatalf("%v: %v", size, h.err)
+		}
+		seen := map[snake.Point]bool{}
+		p := snake.Point{}
+		for range size[0] * size[1] {
+			if seen[p] {
+				t.Fatalf("%v: %v visited twice", size, p)
+			}
+			seen[p] = true
+			n := h.next[p.Y*size[0]+p.X]
+			if abs(n.X-p.X)+abs(n.Y-p.Y) != 1 {
+				t.Fatalf("%v: %v -> %v is not a step", size, p, n)
+			}
+			if h.prev[n.Y*size[0]+n.X] != p {
+				t.Fatalf("%v: prev(%v) != %v", size, n, p)
+			}
+			p = n
+		}
+		if p != (snake.Point{}) {
+			t.Fatalf("%v: cycle does not return to the start", size)
+		}
+	}
+
+	h := NewHamiltonian()
+	if h.build(5, 3); h.err == nil {
+		t.Error("5x3 board should have no cycle")
+	}
+}
+
+// TestHamiltonianFillsBoard 沿回路前进一定能填满面板
+func TestHamiltonianFillsBoard(t *testing.T) {
+	for _, wrap := range []bool{false, true} {
+		g := snake.NewGame(snake.WithSeed(3), snake.WithWrap(wrap))
+		bot := NewHamiltonian()
+		for i := 0; i < 500000 && !g.Over(); i++ {
+			g.Step(bot.Next(g.State(), 0))
+		}
+		s := g.State()
+		checkState(t, s)
+		if !s.Won {
+			t.Fatalf("wrap=%v: game ended without filling the board, length %d", wrap, len(s.Snake))
+		}
+	}
+}
+
+// TestGreedyPlays 贪心寻路在空白面板、穿墙模式和战役关卡上都能稳定得分
+func TestGreedyPlays(t *testing.T) {
+	campaign, err := snake.FindLevels(snake.LevelCampaign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	games := 1000
+	if testing.Short() {
+		games = 100
+	}
+	total := 0
+	for seed := int64(1); seed <= int64(games); seed++ {
+		var g *snake.Game
+		switch seed % 3 {
+		case 0:
+			g = snake.NewGame(snake.WithSeed(seed))
+		case 1:
+			g = snake.NewGame(snake.WithSeed(seed), snake.WithWrap(true))
+		default:
+			g = snake.NewGame(snake.WithSeed(seed), snake.WithLevels(campaign...))
+		}
+		s := play(t, g, 200, NewGreedy())
+		total += s.Score
+	}
+	if avg := total / games; avg < 200 {
+		t.Errorf("average score after 200 steps = %d, want at least 200", avg)
+	}
+}
+
+// TestVersusStress 多条电脑控制的蛇同时移动、相撞、吃食物，面板始终保持一致
+func TestVersusStress(t *testing.T) {
+	games := 200
+	if testing.Short() {
+		games = 20
+	}
+	rounds := 0
+	for seed := int64(1); seed <= int64(games); seed++ {
+		players := 2 + int(seed%3)
+		g := snake.NewGame(snake.WithSeed(seed), snake.WithPlayers(players), snake.WithWrap(seed%2 == 0))
+		bots := make([]snake.Controller, players)
+		for n := range bots {
+			bots[n] = NewGreedy()
+		}
+		// 一号玩家偶尔不受控制，制造更多碰撞
+		if seed%5 == 0 {
+			bots[0] = nil
+		}
+		for round := 0; round < 2; round++ {
+			s := play(t, g, 300, bots...)
+			if s.GameOver {
+				rounds++
+				alive := 0
+				for _, sn := range s.Snakes {
+					if sn.Alive {
+						alive++
+					}
+				}
+				if alive > 1 && len(s.Rounds) == 0 {
+					t.Fatalf("seed %d: round over with %d snakes alive and no result", seed, alive)
+				}
+			}
+			g.Reset(seed + 1000)
+		}
+	}
+	if rounds == 0 {
+		t.Error("no round finished")
+	}
+}
+
+// TestTailCheck 沿路径钻进死胡同吃食物之后够不到蛇尾，不能算安全路径
+func TestTailCheck(t *testing.T) {
+	//	. . . . . .
+	//	. . H B B .
+	//	. # # # # #
+	//	. . . . F #
+	s := snake.State{
+		Width: 6, Height: 4,
+		Walls: []snake.Point{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}, {X: 5, Y: 2}, {X: 5, Y: 3}},
+		Snakes: []snake.SnakeState{{
+			Body:      []snake.Point{{X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}},
+			Direction: snake.Left,
+			Length:    3,
+			Alive:     true,
+		}},
+		Foods: []snake.Food{{Pos: snake.Point{X: 4, Y: 3}}},
+	}
+	g := newGrid(s)
+	path := g.path(s.Snakes[0].Body[0], func(p snake.Point) bool { return p == s.Foods[0].Pos })
+	if len(path) != 8 {
+		t.Fatalf("path to food = %v, want 8 steps", path)
+	}
+	if g.canReachTail(s, 0, path, true) {
+		t.Error("path into the dead end reported safe")
+	}
+	if !g.canReachTail(s, 0, []snake.Point{{X: 2, Y: 0}}, false) {
+		t.Error("step into the open row reported unsafe")
+	}
+}
+
+// TestArenaStress 竞技场中的蛇不断撞死、复活和加入，面板始终保持一致
+func TestArenaStress(t *testing.T) {
+	games := 30
+	if testing.Short() {
+		games = 5
+	}
+	bot := NewGreedy()
+	for seed := int64(1); seed <= int64(games); seed++ {
+		g := snake.NewGame(snake.WithSeed(seed), snake.WithWrap(seed%2 == 0), snake.WithArena(&snake.Level{Name: "Arena", Width: 30, Height: 20}))
+		for range 4 {
+			g.Join()
+		}
+		for step := 0; step < 300; step++ {
+			s := g.State()
+			for n, sn := range s.Snakes {
+				if sn.Alive {
+					g.TurnPlayer(n, bot.Next(s, n))
+				} else {
+					g.Respawn(n)
+				}
+			}
+			// 比赛中途有新的蛇加入
+			if step%50 == 25 {
+				g.Join()
+			}
+			g.Step()
+			checkState(t, g.State())
+			if g.Over() {
+				t.Fatalf("seed %d: arena ended at step %d", seed, step)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package ai
+
+import "go-game/snake"
+
+// ============================================
+// Greedy - 贪心寻路
+// ============================================
+// 每一步按以下顺序选择方向：
+// 1. 沿最短路径去吃最近的食物，前提是吃到之后还能到达自己的蛇尾（不会把自己困死）
+// 2. 否则朝一个仍能回到蛇尾的方向走，优先选离蛇尾最远的（拖延时间，等待出路）
+// 3. 否则朝可活动空间最大的方向走
+// 多条蛇时尽量避开其他蛇头下一步可能到达的格子，防止迎面相撞
+
+// Greedy 贪心寻路控制器，没有内部状态，可以同时控制多条蛇
+type Greedy struct{}
+
+// NewGreedy 创建贪心寻路控制器
+func NewGreedy() *Greedy {
+	return &Greedy{}
+}
+
+// Next 为第 i 条蛇选择下一步的方向
+func (Greedy) Next(s snake.State, i int) snake.Direction {
+	me := s.Snakes[i]
+	if !me.Alive || len(me.Body) == 0 {
+		return me.Direction
+	}
+
+	// 不在生长的蛇这一步会移走蛇尾，蛇尾所在的格子可以通行
+	g := newGrid(s)
+	if len(me.Body) > 1 && len(me.Body) >= me.Length {
+		g.set(me.Body[len(me.Body)-1], false)
+	}
+
+	// 其他蛇头下一步可能到达的格子，尽量不走
+	careful := g.clone()
+	for j, other := range s.Snakes {
+		if j == i || !other.Alive || len(other.Body) == 0 {
+			continue
+		}
+		for _, d := range directions {
+			if n, ok := g.step(other.Body[0], d); ok {
+				careful.set(n, true)
+			}
+		}
+	}
+
+	for _, b := range []*grid{careful, g} {
+		if d, ok := b.choose(s, i); ok {
+			return d
+		}
+	}
+	return me.Direction
+}
+
+// choose 在面板 g 上为第 i 条蛇选择方向，找不到可走的格子时返回 false
+func (g *grid) choose(s snake.State, i int) (snake.Direction, bool) {
+	me := s.Snakes[i]
+	head := me.Body[0]
+
+	// 1. 吃最近的食物
+	isFood := func(p snake.Point) bool {
+		for _, f := range s.Foods {
+			if f.Pos == p {
+				return true
+			}
+		}
+		return false
+	}
+	if path := g.path(head, func(p snake.Point) bool { return isFood(p) && g.free(p) }); path != nil {
+		if g.canReachTail(s, i, path, true) {
+			return g.direction(head, path[0]), true
+		}
+	}
+
+	// 2. 跟着蛇尾走：选走完之后离蛇尾最远的方向
+	best, bestTail := snake.Up, -1
+	for _, d := range directions {
+		n, ok := g.step(head, d)
+		if !ok || !g.free(n) {
+			continue
+		}
+		if tail := g.tailDistance(s, i, []snake.Point{n}, isFood(n)); tail > bestTail {
+			best, bestTail = d, tail
+		}
+	}
+	if bestTail >= 0 {
+		return best, true
+	}
+
+	// 3. 选可活动空间最大的方向
+	bestSpace := 0
+	for _, d := range directions {
+		n, ok := g.step(head, d)
+		if !ok || !g.free(n) {
+			continue
+		}
+		after := g.clone()
+		after.set(n, true)
+		if space := after.space(n); space > bestSpace {
+			best, bestSpace = d, space
+		}
+	}
+	return best, bestSpace > 0
+}
+
+// canReachTail 第 i 条蛇沿 path 走完之后能否到达自己的蛇尾
+func (g *grid) canReachTail(s snake.State, i int, path []snake.Point, grow bool) bool {
+	return g.tailDistance(s, i, path, grow) >= 0
+}
+
+// tailDistance 第 i 条蛇沿 path 走完之后从蛇头到蛇尾的最短距离，到达不了时返回 -1
+// grow 表示走到终点时吃到了食物（蛇尾多保留一格）
+func (g *grid) tailDistance(s snake.State, i int, path []snake.Point, grow bool) int {
+	me := s.Snakes[i]
+
+	// 走完之后的身体：路径倒序接在原来的身体前面，再截到走完后的长度
+	body := make([]snake.Point, 0, len(path)+len(me.Body))
+	for k := len(path) - 1; k >= 0; k-- {
+		body = append(body, path[k])
+	}
+	body = append(body, me.Body...)
+	length := max(len(me.Body), 1)
+	if grow {
+		length++
+	}
+	body = body[:min(length, len(body))]
+
+	// 其他蛇和障碍物保持不变，自己的身体换成走完之后的身体
+	after := g.clone()
+	for _, p := range me.Body {
+		after.set(p, false)
+	}
+	for _, p := range body {
+		after.set(p, true)
+	}
+	tail := body[len(body)-1]
+	if len(body) == 1 {
+		return 0
+	}
+	to := after.path(body[0], func(p snake.Point) bool { return p == tail })
+	if to == nil {
+		return -1
+	}
+	return len(to)
+}
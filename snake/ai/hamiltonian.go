@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"errors"
+
+	"go-game/snake"
+)
+
+// ============================================
+// Hamiltonian - 哈密顿回路
+// ============================================
+// 沿一条经过面板上每个格子恰好一次的回路前进。只要蛇身一直排在回路上，
+// 蛇头前方的格子就总是空的（或是正要移走的蛇尾），因此一定能填满面板。
+//
+// 回路的构造（高度为偶数时，宽度为偶数则转置构造）：
+//
+//	→ → → → ↓      第 0 行从左走到右，
+//	↑ ↓ ← ← ←      之后在第 1 列到最后一列之间来回折返，
+//	↑ → → → ↓      最后沿第 0 列回到起点
+//	↑ ← ← ← ←
+//
+// 宽高都是奇数或面板上有障碍物时不存在这样的回路，此时退回贪心寻路
+
+// errNoCycle 面板上无法构造哈密顿回路
+var errNoCycle = errors.New("ai: board has no hamiltonian cycle")
+
+// Hamiltonian 哈密顿回路控制器
+type Hamiltonian struct {
+	width, height int
+	next, prev    []snake.Point // 回路上每个格子的后继和前驱，下标为 y*width+x
+	err           error         // 当前面板无法构造回路的原因
+	fallback      Greedy        // 无法沿回路前进时使用
+}
+
+// NewHamiltonian 创建哈密顿回路控制器，回路在第一次调用 Next 时按面板尺寸构造
+func NewHamiltonian() *Hamiltonian {
+	return &Hamiltonian{}
+}
+
+// Next 为第 i 条蛇选择下一步的方向
+// 回路有两个方向，选择与蛇身一致的那个（蛇头的后继不是蛇颈）
+func (h *Hamiltonian) Next(s snake.State, i int) snake.Direction {
+	me := s.Snakes[i]
+	if !me.Alive || len(me.Body) == 0 {
+		return me.Direction
+	}
+	if h.width != s.Width || h.height != s.Height {
+		h.build(s.Width, s.Height)
+	}
+	if h.err != nil || len(s.Walls) > 0 || len(s.Portals) > 0 || len(s.Hazards) > 0 {
+		return h.fallback.Next(s, i)
+	}
+
+	head := me.Body[0]
+	target := h.next[head.Y*h.width+head.X]
+	if len(me.Body) > 1 && target == me.Body[1] {
+		target = h.prev[head.Y*h.width+head.X]
+	}
+
+	// 蛇身不在回路上（例如开局位置与回路不一致）时前方可能被挡住，改用贪心寻路
+	g := newGrid(s)
+	if len(me.Body) >= me.Length {
+		g.set(me.Body[len(me.Body)-1], false)
+	}
+	if !g.free(target) {
+		return h.fallback.Next(s, i)
+	}
+	return g.direction(head, target)
+}
+
+// build 为 width x height 的面板构造回路
+func (h *Hamiltonian) build(width, height int) {
+	h.width, h.height = width, height
+	h.next, h.prev, h.err = nil, nil, nil
+
+	var cycle []snake.Point
+	switch {
+	case width < 2 || height < 2 || (width%2 == 1 && height%2 == 1):
+		h.err = errNoCycle
+		return
+	case height%2 == 0:
+		cycle = zigzag(width, height)
+	default:
+		// 转置：按列构造后交换坐标
+		for _, p := range zigzag(height, width) {
+			cycle = append(cycle, snake.Point{X: p.Y, Y: p.X})
+		}
+	}
+
+	h.next = make([]snake.Point, width*height)
+	h.prev = make([]snake.Point, width*height)
+	for k, p := range cycle {
+		n := cycle[(k+1)%len(cycle)]
+		h.next[p.Y*width+p.X] = n
+		h.prev[n.Y*width+n.X] = p
+	}
+}
+
+// zigzag 按顺序返回 width x height（height 为偶数）面板上的回路
+func zigzag(width, height int) []snake.Point {
+	cycle := make([]snake.Point, 0, width*height)
+	for x := 0; x < width; x++ {
+		cycle = append(cycle, snake.Point{X: x, Y: 0})
+	}
+	for y := 1; y < height; y++ {
+		if y%2 == 1 {
+			for x := width - 1; x >= 1; x-- {
+				cycle = append(cycle, snake.Point{X: x, Y: y})
+			}
+		} else {
+			for x := 1; x < width; x++ {
+				cycle = append(cycle, snake.Point{X: x, Y: y})
+			}
+		}
+	}
+	for y := height - 1; y >= 1; y-- {
+		cycle = append(cycle, snake.Point{X: 0, Y: y})
+	}
+	return cycle
+}
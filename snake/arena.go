@@ -0,0 +1,146 @@
+package snake
+
+// ============================================
+// 竞技场 - 任意数量的蛇随时加入、离开和复活
+// ============================================
+// 竞技场没有局的概念，也不会结束：撞死的蛇立即从面板上移除，等待复活；
+// 新加入或复活的蛇出现在随机的空位上。由 arena 包的服务器驱动
+
+// arenaMargin 新蛇前方至少留出的空格数，与其他蛇头的距离至少为它的两倍
+const arenaMargin = 3
+
+// WithArena 竞技场模式：开始时面板上没有蛇
+// lvl 指定面板尺寸和障碍物（为 nil 时是 WithSize 的空白面板），关卡的起点和目标长度不使用
+func WithArena(lvl *Level) Option {
+	return func(g *Game) {
+		g.arena, g.players = true, 0
+		if lvl != nil {
+			g.levels = []*Level{lvl}
+		}
+	}
+}
+
+// Join 在竞技场中加入一条新蛇，返回它的下标（在它离开之前一直不变）
+// 优先使用已经离开的蛇留下的下标，因此下标的数量不会超过同时在场的蛇的最大数量
+// 不是竞技场或没有足够的空位时返回 -1
+func (g *Game) Join() int {
+	if !g.arena {
+		return -1
+	}
+	p := g.spawnPlayer()
+	if p == nil {
+		return -1
+	}
+	for i, q := range g.snakes {
+		if q.left {
+			g.snakes[i] = p
+			g.fillApples()
+			return i
+		}
+	}
+	g.snakes = append(g.snakes, p)
+	g.players = len(g.snakes)
+	g.fillApples()
+	return len(g.snakes) - 1
+}
+
+// Respawn 让撞死的第 i 条蛇在新的空位上复活，得分清零
+// 蛇还活着、已经离开或没有空位时返回 false
+func (g *Game) Respawn(i int) bool {
+	if !g.arena || i < 0 || i >= len(g.snakes) || g.snakes[i].alive || g.snakes[i].left {
+		return false
+	}
+	p := g.spawnPlayer()
+	if p == nil {
+		return false
+	}
+	g.snakes[i] = p
+	g.fillApples()
+	return true
+}
+
+// Leave 让第 i 条蛇离开竞技场：从面板上移除，下标留给之后加入的蛇
+func (g *Game) Leave(i int) {
+	if !g.arena || i < 0 || i >= len(g.snakes) {
+		return
+	}
+	g.snakes[i].alive, g.snakes[i].left = false, true
+	g.clearBody(g.snakes[i])
+}
+
+// clearBody 把蛇从面板上移除（竞技场中撞死的蛇不留下障碍物）
+func (g *Game) clearBody(p *player) {
+	for _, c := range p.body {
+		g.board[c.Y][c.X] = cellEmpty
+	}
+	p.body, p.turns = nil, nil
+}
+
+// spawnPlayer 在随机的空位上创建一条新蛇并标记到面板上，没有空位时返回 nil
+func (g *Game) spawnPlayer() *player {
+	type spot struct {
+		head Point
+		dir  Direction
+	}
+	var spots []spot
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			for d := Up; d <= Right; d++ {
+				if g.spawnFits(Point{x, y}, d) {
+					spots = append(spots, spot{Point{x, y}, d})
+				}
+			}
+		}
+	}
+	if len(spots) == 0 {
+		return nil
+	}
+	s := spots[g.rng.Intn(len(spots))]
+	p := newPlayer(s.head, s.dir)
+	for _, c := range p.body {
+		g.board[c.Y][c.X] = cellSnake
+	}
+	return p
+}
+
+// spawnFits 蛇头在 head、朝 dir 移动的新蛇能否放下：
+// 身体和前方 arenaMargin 格都是没有食物的空白格子，且离其他蛇头足够远
+func (g *Game) spawnFits(head Point, dir Direction) bool {
+	cells := startBody(head, dir)
+	for c, i := head, 0; i < arenaMargin; i++ {
+		c = g.nextHead(c, dir)
+		cells = append(cells, c)
+	}
+	for _, c := range cells {
+		if c.X < 0 || c.X >= g.width || c.Y < 0 || c.Y >= g.height ||
+			g.board[c.Y][c.X] != cellEmpty || g.foodAt(c) >= 0 || g.hazardAt(c) {
+			return false
+		}
+	}
+	for _, q := range g.snakes {
+		if q.alive && abs(q.head().X-head.X)+abs(q.head().Y-head.Y) < 2*arenaMargin {
+			return false
+		}
+	}
+	return true
+}
+
+// fillApples 竞技场中每两条活着的蛇放一个苹果（至少一个）
+func (g *Game) fillApples() {
+	apples := 0
+	for _, f := range g.foods {
+		if f.Kind == Apple {
+			apples++
+		}
+	}
+	for ; apples < max(1, (g.alive()+1)/2) && len(g.freeCells()) > 0; apples++ {
+		g.spawnFood()
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
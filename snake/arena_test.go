@@ -0,0 +1,111 @@
+package snake
+
+import "testing"
+
+// TestArenaJoin 新加入的蛇出现在空位上，彼此不重叠，蛇头之间留有距离
+func TestArenaJoin(t *testing.T) {
+	g := NewGame(WithSeed(1), WithArena(&Level{Name: "Arena", Width: 30, Height: 20}))
+	if s := g.State(); len(s.Snakes) != 0 || len(s.Snake) != 0 {
+		t.Fatalf("new arena has snakes: %+v", s.Snakes)
+	}
+	for i := 0; i < 6; i++ {
+		if id := g.Join(); id != i {
+			t.Fatalf("Join() = %d, want %d", id, i)
+		}
+		checkBoard(t, g)
+	}
+	for i, p := range g.snakes {
+		for j, q := range g.snakes[:i] {
+			if d := abs(p.head().X-q.head().X) + abs(p.head().Y-q.head().Y); d < 2*arenaMargin {
+				t.Errorf("snakes %d and %d spawned %d cells apart", j, i, d)
+			}
+		}
+		for _, c := range p.body {
+			if g.foodAt(c) >= 0 {
+				t.Errorf("snake %d spawned on food at %v", i, c)
+			}
+		}
+	}
+	apples := 0
+	for _, f := range g.foods {
+		if f.Kind == Apple {
+			apples++
+		}
+	}
+	if apples != 3 {
+		t.Errorf("%d apples for 6 snakes, want 3", apples)
+	}
+	if NewGame(WithSeed(1)).Join() != -1 {
+		t.Error("Join() outside an arena should fail")
+	}
+}
+
+// TestArenaCrashAndRespawn 撞死的蛇从面板上移除，游戏继续，复活后得分清零
+func TestArenaCrashAndRespawn(t *testing.T) {
+	g := NewGame(WithSeed(2), WithArena(nil))
+	id := g.Join()
+	g.snakes[id].score = 50
+	for i := 0; i < BoardWidth+BoardHeight && g.snakes[id].alive; i++ {
+		g.Step()
+	}
+	p := g.snakes[id]
+	if p.alive || len(p.body) != 0 {
+		t.Fatalf("snake should have crashed and been cleared: alive %v body %v", p.alive, p.body)
+	}
+	if g.Over() {
+		t.Fatal("arena should never be over")
+	}
+	checkBoard(t, g)
+
+	if !g.Respawn(id) {
+		t.Fatal("Respawn() failed on an empty board")
+	}
+	if p := g.snakes[id]; !p.alive || p.score != 0 || len(p.body) != startLength {
+		t.Errorf("respawned snake: alive %v score %d length %d", p.alive, p.score, len(p.body))
+	}
+	if g.Respawn(id) {
+		t.Error("Respawn() of a living snake should fail")
+	}
+	checkBoard(t, g)
+}
+
+// TestArenaLeave 离开的蛇从面板上移除，不能复活，下标分配给下一条加入的蛇
+func TestArenaLeave(t *testing.T) {
+	g := NewGame(WithSeed(3), WithWrap(true), WithArena(nil))
+	g.Join()
+	g.Join()
+	g.Leave(0)
+	checkBoard(t, g)
+	if g.snakes[0].alive {
+		t.Error("snake 0 still alive after leaving")
+	}
+	if g.Respawn(0) {
+		t.Error("Respawn() of a departed snake should fail")
+	}
+	if id := g.Join(); id != 0 {
+		t.Errorf("Join() after Leave = %d, want the free slot 0", id)
+	}
+	if id := g.Join(); id != 2 {
+		t.Errorf("Join() with no free slot = %d, want 2", id)
+	}
+	if len(g.snakes) != 3 || !g.snakes[0].alive {
+		t.Errorf("snakes = %d, snake 0 alive %v; want 3 snakes with slot 0 reused", len(g.snakes), g.snakes[0].alive)
+	}
+	checkBoard(t, g)
+}
+
+// TestArenaFull 没有空位时不能再加入
+func TestArenaFull(t *testing.T) {
+	g := NewGame(WithSeed(4), WithArena(&Level{Name: "Tiny", Width: 7, Height: 7}))
+	n := 0
+	for g.Join() >= 0 {
+		n++
+		if n > 10 {
+			t.Fatal("tiny arena never fills up")
+		}
+	}
+	if n == 0 {
+		t.Fatal("no snake fits in a 7x7 arena")
+	}
+	checkBoard(t, g)
+}
@@ -0,0 +1,81 @@
+package snake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// Controller - 电脑控制的蛇
+// ============================================
+// 寻路算法的实现在 snake/ai 包中，该包在 init 中通过 RegisterController 注册，
+// 游戏本身只依赖这里的接口（避免 snake 与 snake/ai 相互引用）
+
+// Controller 每移动一格之前，根据当前状态为第 i 条蛇选择方向
+type Controller interface {
+	Next(s State, i int) Direction
+}
+
+// controllers 已注册的控制器：名称 -> 构造函数
+var controllers = map[string]func() Controller{}
+
+// RegisterController 注册一种控制器，供自动演示和电脑对手使用
+func RegisterController(name string, create func() Controller) {
+	controllers[name] = create
+}
+
+// ControllerNames 返回已注册的控制器名称（按字母顺序）
+func ControllerNames() []string {
+	names := make([]string, 0, len(controllers))
+	for name := range controllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewController 按名称创建已注册的控制器
+func NewController(name string) (Controller, error) {
+	newFn, ok := controllers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown autopilot %q", name)
+	}
+	return newFn(), nil
+}
+
+// OpponentController 电脑对手使用的控制器名称
+const OpponentController = "greedy"
+
+// ============================================
+// 转向动作名
+// ============================================
+// 录像中一号玩家的转向记为方向名（"MoveLeft"），其余玩家加上 "P<n>" 前缀（"P2MoveLeft"），
+// 与 input 包中二号玩家的动作名一致；电脑控制的蛇的转向也按同样的格式记录
+
+// turnAction 返回第 i 条蛇转向 d 的动作名
+func turnAction(i int, d Direction) string {
+	if i == 0 {
+		return d.String()
+	}
+	return fmt.Sprintf("P%d%s", i+1, d)
+}
+
+// parseTurn 解析转向动作名，返回蛇的下标和方向
+func parseTurn(action string) (int, Direction, bool) {
+	i := 0
+	if rest, ok := strings.CutPrefix(action, "P"); ok {
+		end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+		if end <= 0 {
+			return 0, Up, false
+		}
+		n, err := strconv.Atoi(rest[:end])
+		if err != nil || n < 1 {
+			return 0, Up, false
+		}
+		i, action = n-1, rest[end:]
+	}
+	d, ok := ParseDirection(action)
+	return i, d, ok
+}
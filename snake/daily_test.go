@@ -0,0 +1,113 @@
+package snake
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/daily"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+)
+
+// TestFixedSeedRestart 每日挑战重新开始时回到同一个种子，食物序列与第一局相同
+func TestFixedSeedRestart(t *testing.T) {
+	g := NewGame(WithSeed(5), WithFixedSeed())
+	first := g.State().Food
+	chaseFood(g, 30)
+	g.reset()
+	if got := g.State().Food; got != first {
+		t.Errorf("food after restart = %v, want %v", got, first)
+	}
+}
+
+// TestRunDaily 当天第一局计入每日历史（中途离开也算），之后的对局都是练习
+func TestRunDaily(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	const date = "2026-10-18"
+
+	play := func() tcell.SimulationScreen {
+		screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+		go func() {
+			time.Sleep(400 * time.Millisecond)
+			screentest.Type(screen, 5*time.Millisecond, screentest.Key(tcell.KeyEscape))
+		}()
+		if err := Run(screen, input.DefaultSnake(), Config{Daily: date, Wrap: true, Players: 2}); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return screen
+	}
+
+	screen := play()
+	if !screentest.Contains(screen, "SNAKE DAILY") || !screentest.Contains(screen, date) {
+		t.Errorf("expected the daily challenge (other options ignored):\n%s", screentest.Dump(screen))
+	}
+	if screentest.Contains(screen, "PRACTICE") {
+		t.Errorf("the first game of the day should be scored:\n%s", screentest.Dump(screen))
+	}
+
+	screen = play()
+	if !screentest.Contains(screen, "PRACTICE") || !screentest.Contains(screen, "TODAY: 0") {
+		t.Errorf("the second game should be practice:\n%s", screentest.Dump(screen))
+	}
+	history, err := daily.History(DailyGame)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 1 || history[0].Date != date || history[0].Label != "0" {
+		t.Errorf("history = %+v, want one result for %s", history, date)
+	}
+}
+
+// TestRunDailySaveError 每日成绩写入失败时 Run 返回错误，而不是悄悄丢掉当天唯一计分的一局
+func TestRunDailySaveError(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(store.EnvHome, home)
+	// 占住临时文件的位置，读取每日历史正常，写入失败
+	if err := os.Mkdir(filepath.Join(home, "daily.json.tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	go func() {
+		// 中途重新开始也会用掉当天计分的一局
+		time.Sleep(400 * time.Millisecond)
+		screentest.Type(screen, 5*time.Millisecond, screentest.Rune('r'), screentest.Key(tcell.KeyEscape))
+	}()
+	err := Run(screen, input.DefaultSnake(), Config{Daily: "2026-10-18"})
+	if err == nil || !strings.Contains(err.Error(), "daily.json") {
+		t.Errorf("Run = %v, want the failed daily write", err)
+	}
+}
+
+// TestDailySeed 同一天的种子对所有人都相同，不同日期和不同游戏的种子不同
+func TestDailySeed(t *testing.T) {
+	seed := daily.Seed(DailyGame, "2026-10-18")
+	if seed != daily.Seed(DailyGame, "2026-10-18") || seed < 0 {
+		t.Errorf("seed %d should be stable and non-negative", seed)
+	}
+	if seed == daily.Seed(DailyGame, "2026-10-19") || seed == daily.Seed("tetris", "2026-10-18") {
+		t.Error("seeds of other days and games should differ")
+	}
+	if got := daily.Date(time.Date(2026, 10, 18, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))); got != "2026-10-19" {
+		t.Errorf("Date = %s, want the UTC date 2026-10-19", got)
+	}
+}
+
+// TestRenderDailyGolden 每日挑战计分的一局结束后，结果面板提示成绩已记录
+func TestRenderDailyGolden(t *testing.T) {
+	g := NewGame(WithSeed(daily.Seed(DailyGame, "2026-10-18")), WithFixedSeed())
+	chaseFood(g, 1000)
+	if !g.Over() {
+		t.Fatal("game should be over")
+	}
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	r := NewRenderer(screen, g, input.DefaultSnake())
+	r.daily, r.practice, r.record, r.best = "2026-10-18", true, true, g.snakes[0].score
+	r.Render()
+	screentest.AssertScreen(t, "render_daily", screen)
+}
@@ -0,0 +1,78 @@
+package snake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================
+// 难度 - 速度曲线预设
+// ============================================
+// 每种难度有自己的初始速度、提速快慢和最快速度；选择恒定速度时一直保持初始速度。
+// 不同难度的成绩不可比较，普通难度之外的每种难度有自己的高分榜
+
+// Difficulty 速度曲线：移动间隔从 Start 开始，每得 Ramp 分减少 1 毫秒，直到 Floor
+type Difficulty struct {
+	Name  string
+	Start int // 初始移动间隔（毫秒）
+	Ramp  int // 每得多少分移动间隔减少 1 毫秒，0 表示恒定速度
+	Floor int // 最短移动间隔（毫秒）
+}
+
+// Difficulties 难度预设，Normal 与原先固定的速度曲线一致
+var Difficulties = []Difficulty{
+	{"easy", 200, 8, 110},
+	{"normal", SpeedNormal, 5, SpeedFast},
+	{"hard", 110, 4, 60},
+	{"insane", 75, 3, 40},
+}
+
+// DifficultyNormal 默认难度
+const DifficultyNormal = "normal"
+
+// ParseDifficulty 按名称查找难度预设，空字符串表示普通难度
+// constant 为 true 时返回保持初始速度不变的版本
+func ParseDifficulty(name string, constant bool) (Difficulty, error) {
+	if name == "" {
+		name = DifficultyNormal
+	}
+	for _, d := range Difficulties {
+		if strings.EqualFold(d.Name, name) {
+			if constant {
+				d.Ramp = 0
+			}
+			return d, nil
+		}
+	}
+	return Difficulty{}, fmt.Errorf("unknown difficulty %q (valid: %s)", name, strings.Join(DifficultyNames(), ", "))
+}
+
+// DifficultyNames 返回难度预设的名称列表
+func DifficultyNames() []string {
+	names := make([]string, len(Difficulties))
+	for i, d := range Difficulties {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// interval 返回得分为 score 时的移动间隔（毫秒）
+func (d Difficulty) interval(score int) int {
+	if d.Ramp <= 0 {
+		return d.Start
+	}
+	return max(d.Start-score/d.Ramp, d.Floor)
+}
+
+// tableSuffix 返回高分榜名称中的难度部分，普通难度的提速曲线为空
+// 例如 "-hard"、"-constant"、"-insane-constant"
+func (d Difficulty) tableSuffix() string {
+	suffix := ""
+	if d.Name != DifficultyNormal {
+		suffix += "-" + d.Name
+	}
+	if d.Ramp == 0 {
+		suffix += "-constant"
+	}
+	return suffix
+}
@@ -0,0 +1,150 @@
+package snake
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+	"go-game/replay"
+)
+
+// TestDifficultyInterval 各难度的速度曲线；普通难度与原先固定的公式一致
+func TestDifficultyInterval(t *testing.T) {
+	normal, err := ParseDifficulty("", false)
+	if err != nil {
+		t.Fatalf("ParseDifficulty: %v", err)
+	}
+	for _, score := range []int{0, 10, 100, 349, 350, 1000} {
+		if got, want := normal.interval(score), max(SpeedNormal-score/5, SpeedFast); got != want {
+			t.Errorf("normal interval(%d) = %d, want %d", score, got, want)
+		}
+	}
+
+	for _, d := range Difficulties {
+		if d.interval(0) != d.Start || d.interval(100000) != d.Floor {
+			t.Errorf("%s: interval runs from %d to %d, want %d to %d", d.Name, d.interval(0), d.interval(100000), d.Start, d.Floor)
+		}
+		constant, _ := ParseDifficulty(d.Name, true)
+		if constant.interval(100000) != d.Start {
+			t.Errorf("%s constant: interval(100000) = %d, want %d", d.Name, constant.interval(100000), d.Start)
+		}
+	}
+	for i := 1; i < len(Difficulties); i++ {
+		if Difficulties[i].Start >= Difficulties[i-1].Start || Difficulties[i].Floor >= Difficulties[i-1].Floor {
+			t.Errorf("%s should be faster than %s", Difficulties[i].Name, Difficulties[i-1].Name)
+		}
+	}
+
+	if _, err := ParseDifficulty("nightmare", false); err == nil {
+		t.Error("ParseDifficulty(nightmare) should fail")
+	}
+	if d, err := ParseDifficulty("HARD", false); err != nil || d.Name != "hard" {
+		t.Errorf("ParseDifficulty(HARD) = %+v, %v", d, err)
+	}
+}
+
+// TestDifficultySpeed 难度决定 State().Speed
+func TestDifficultySpeed(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	hard, _ := ParseDifficulty("hard", false)
+	g.SetDifficulty(hard)
+	g.snakes[0].score = 200
+	if got := g.State().Speed; got != 60 {
+		t.Errorf("hard speed at 200 points = %d, want 60", got)
+	}
+	easy, _ := ParseDifficulty("easy", true)
+	g.SetDifficulty(easy)
+	if got := g.State().Speed; got != 200 {
+		t.Errorf("constant easy speed = %d, want 200", got)
+	}
+}
+
+// TestBoost 加速键使移动间隔减半、得分翻倍，若干步后自动结束
+func TestBoost(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	head := g.snakes[0].head()
+	setFood(g, Point{head.X, head.Y - 1})
+	g.Boost()
+	if s := g.State(); s.Boost != BoostTicks || s.Speed != SpeedNormal/2 {
+		t.Errorf("after Boost: boost %d speed %d, want %d %d", s.Boost, s.Speed, BoostTicks, SpeedNormal/2)
+	}
+
+	g.Step()
+	if s := g.State(); s.Score != 20 || s.Boost != BoostTicks-1 {
+		t.Errorf("boosted apple: score %d boost %d, want 20 %d", s.Score, s.Boost, BoostTicks-1)
+	}
+	for i := 1; i < BoostTicks; i++ {
+		g.Step()
+	}
+	if s := g.State(); s.Boost != 0 || s.Speed != normalSpeed(s.Score) {
+		t.Errorf("boost should have expired: boost %d speed %d", s.Boost, s.Speed)
+	}
+
+	// 多人对战共用一个节拍，加速键无效
+	v := NewGame(WithSeed(1), WithPlayers(2))
+	v.Boost()
+	if v.State().Boost != 0 {
+		t.Error("Boost should do nothing in versus games")
+	}
+}
+
+// normalSpeed 普通难度下没有任何效果时的移动间隔
+func normalSpeed(score int) int {
+	return max(SpeedNormal-score/5, SpeedFast)
+}
+
+// TestTableNameByDifficulty 普通难度之外的难度和恒定速度有自己的高分榜
+func TestTableNameByDifficulty(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		constant   bool
+		want       string
+	}{
+		{"", false, "snake"},
+		{"normal", true, "snake-constant"},
+		{"hard", false, "snake-hard"},
+		{"insane", true, "snake-insane-constant"},
+	}
+	for _, tt := range tests {
+		g, err := Config{Difficulty: tt.difficulty, Constant: tt.constant}.newGame(1)
+		if err != nil {
+			t.Fatalf("newGame: %v", err)
+		}
+		if got := g.tableName(); got != tt.want {
+			t.Errorf("tableName(%s, %v) = %q, want %q", tt.difficulty, tt.constant, got, tt.want)
+		}
+	}
+	if _, err := (Config{Difficulty: "nightmare"}).newGame(1); err == nil {
+		t.Error("newGame should reject unknown difficulties")
+	}
+}
+
+// TestRunBoost 加速键记入录像，回放时得到同样的得分
+func TestRunBoost(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	path := filepath.Join(t.TempDir(), "boost.json")
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	screentest.Type(screen, 5*time.Millisecond,
+		screentest.Rune(' '),
+		screentest.Key(tcell.KeyEscape),
+	)
+	if err := Run(screen, input.DefaultSnake(), Config{Seed: 1, Record: path}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	rp, err := replay.Load(path)
+	if err != nil {
+		t.Fatalf("load replay: %v", err)
+	}
+	var actions []string
+	for _, ev := range rp.Events {
+		actions = append(actions, ev.Action)
+	}
+	if !slices.Contains(actions, input.Boost.String()) {
+		t.Errorf("replay actions %v should include Boost", actions)
+	}
+}
@@ -0,0 +1,112 @@
+package snake
+
+// ============================================
+// 关卡实体 - 传送门与巡逻障碍
+// ============================================
+// 传送门成对出现：蛇头进入其中一个，从另一个的下一格出来，方向保持不变。
+// 传送门格子本身不会被蛇身或食物占用。
+//
+// 巡逻障碍沿直线来回移动，每 hazardPeriod 步前进一格，遇到墙、边界、传送门或其他巡逻障碍时掉头。
+// 蛇头撞上巡逻障碍、或巡逻障碍撞上蛇的任何一节，这条蛇都会撞死。
+// 巡逻障碍不占用面板（位置随时变化），食物不会生成在它所在的格子上，但它可以从食物上经过
+
+// DefaultHazardPeriod 关卡没有指定时巡逻障碍前进一格所需的步数
+const DefaultHazardPeriod = 3
+
+// Portal 一对传送门
+type Portal struct {
+	A, B Point
+}
+
+// Hazard 一个巡逻障碍：当前位置和前进方向
+type Hazard struct {
+	Pos Point
+	Dir Direction
+}
+
+// portalExit 如果 p 是传送门，返回与它成对的另一个传送门
+func (g *Game) portalExit(p Point) (Point, bool) {
+	for _, pt := range g.portals {
+		switch p {
+		case pt.A:
+			return pt.B, true
+		case pt.B:
+			return pt.A, true
+		}
+	}
+	return Point{}, false
+}
+
+// hazardAt 是否有巡逻障碍位于 p
+func (g *Game) hazardAt(p Point) bool {
+	for _, h := range g.hazards {
+		if h.Pos == p {
+			return true
+		}
+	}
+	return false
+}
+
+// updateEntities 实体更新：每次 move 中蛇移动之后调用一次
+// 巡逻障碍每 hazardPeriod 步前进一格，撞上的蛇立即撞死
+func (g *Game) updateEntities() {
+	g.ticks++
+	if len(g.hazards) == 0 || g.ticks%g.hazardPeriod != 0 {
+		return
+	}
+	for i := range g.hazards {
+		h := &g.hazards[i]
+		if next, ok := g.hazardStep(h.Pos, h.Dir); ok {
+			h.Pos = next
+		} else if next, ok := g.hazardStep(h.Pos, opposite(h.Dir)); ok {
+			h.Dir = opposite(h.Dir)
+			h.Pos = next
+		}
+		g.hitSnakes(h.Pos)
+	}
+}
+
+// hazardStep 巡逻障碍从 p 沿 d 前进一格的位置，被挡住时返回 false
+// 巡逻障碍不穿墙：穿墙模式下也在面板边界掉头
+func (g *Game) hazardStep(p Point, d Direction) (Point, bool) {
+	next := p
+	switch d {
+	case Up:
+		next.Y--
+	case Down:
+		next.Y++
+	case Left:
+		next.X--
+	case Right:
+		next.X++
+	}
+	if next.X < 0 || next.X >= g.width || next.Y < 0 || next.Y >= g.height {
+		return p, false
+	}
+	if cell := g.board[next.Y][next.X]; cell == cellWall || cell == cellPortal || g.hazardAt(next) {
+		return p, false
+	}
+	return next, true
+}
+
+// hitSnakes 撞死身体占用 p 的蛇（竞技场中撞死的蛇从面板上移除）
+func (g *Game) hitSnakes(p Point) {
+	if g.board[p.Y][p.X] != cellSnake {
+		return
+	}
+	for _, s := range g.snakes {
+		if !s.alive {
+			continue
+		}
+		for _, c := range s.body {
+			if c == p {
+				s.alive = false
+				s.stats.Death, s.stats.Killer = DeathHazard, -1
+				if g.arena {
+					g.clearBody(s)
+				}
+				break
+			}
+		}
+	}
+}
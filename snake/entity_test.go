@@ -0,0 +1,124 @@
+package snake
+
+import "testing"
+
+func TestParseLevelEntities(t *testing.T) {
+	lvl := testLevel(t, "hazard-period: 2\n1.....\n..^..=\n.|....\n.....1\n")
+	if len(lvl.Portals) != 1 || lvl.Portals[0] != (Portal{Point{0, 0}, Point{5, 3}}) {
+		t.Errorf("portals = %v", lvl.Portals)
+	}
+	want := []Hazard{{Point{5, 1}, Right}, {Point{1, 2}, Down}}
+	if len(lvl.Hazards) != 2 || lvl.Hazards[0] != want[0] || lvl.Hazards[1] != want[1] {
+		t.Errorf("hazards = %v, want %v", lvl.Hazards, want)
+	}
+	if lvl.HazardPeriod != 2 {
+		t.Errorf("hazard period = %d, want 2", lvl.HazardPeriod)
+	}
+	if lvl := testLevel(t, "..^..\n.....\n.....\n"); lvl.HazardPeriod != DefaultHazardPeriod {
+		t.Errorf("default hazard period = %d, want %d", lvl.HazardPeriod, DefaultHazardPeriod)
+	}
+}
+
+// TestPortalTeleports 蛇头进入传送门后从另一个传送门的下一格出来，方向不变，身体依次跟随
+func TestPortalTeleports(t *testing.T) {
+	lvl := testLevel(t, "..1...\n......\n..^...\n......\n......\n....1.\n")
+	g := NewGame(WithSeed(1), WithLevels(lvl))
+	setFood(g, Point{0, 4})
+
+	g.Step() // (2,1)
+	g.Step() // 进入 (2,0) 的传送门，从 (4,5) 向上出来到 (4,4)
+	s := g.State()
+	if s.GameOver || s.Snake[0] != (Point{4, 4}) || s.Direction != Up {
+		t.Fatalf("after the portal: snake %v dir %v over %v", s.Snake, s.Direction, s.GameOver)
+	}
+	if s.Snake[1] != (Point{2, 1}) {
+		t.Errorf("neck = %v, want (2,1)", s.Snake[1])
+	}
+	checkBoard(t, g)
+	for _, p := range []Point{{2, 0}, {4, 5}} {
+		if g.board[p.Y][p.X] != cellPortal {
+			t.Errorf("portal cell %v = %d", p, g.board[p.Y][p.X])
+		}
+	}
+}
+
+// TestPortalExitBlocked 出口越界（非穿墙模式）时蛇撞死；穿墙模式下从对侧进入
+func TestPortalExitBlocked(t *testing.T) {
+	lvl := testLevel(t, ".....1\n..1...\n......\n..^...\n......\n......\n")
+	g := NewGame(WithSeed(1), WithLevels(lvl))
+	setFood(g, Point{0, 5})
+	g.Step()
+	g.Step()
+	if !g.Over() {
+		t.Errorf("leaving the board through a portal should end the game: %v", g.State().Snake)
+	}
+
+	g = NewGame(WithSeed(1), WithWrap(true), WithLevels(lvl))
+	setFood(g, Point{0, 5})
+	g.Step()
+	g.Step()
+	if s := g.State(); s.GameOver || s.Snake[0] != (Point{5, 5}) {
+		t.Errorf("wrapped portal exit: snake %v over %v", s.Snake, s.GameOver)
+	}
+}
+
+// TestHazardPatrols 巡逻障碍每 hazardPeriod 步前进一格，遇到墙掉头
+func TestHazardPatrols(t *testing.T) {
+	lvl := testLevel(t, "hazard-period: 2\n#=..#.\n......\n......\n......\n...^..\n......\n......\n")
+	g := NewGame(WithSeed(1), WithLevels(lvl))
+
+	// 只执行实体更新，蛇不动
+	var got []Point
+	for i := 0; i < 8; i++ {
+		g.updateEntities()
+		got = append(got, g.State().Hazards[0].Pos)
+	}
+	want := []Point{{1, 0}, {2, 0}, {2, 0}, {3, 0}, {3, 0}, {2, 0}, {2, 0}, {1, 0}}
+	if lvl.Hazards[0].Pos != (Point{1, 0}) {
+		t.Errorf("the level itself should not change: %v", lvl.Hazards)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("hazard path = %v, want %v", got, want)
+		}
+	}
+}
+
+// TestHazardKillsSnake 蛇头撞上巡逻障碍、或巡逻障碍撞上蛇身都会结束游戏
+func TestHazardKillsSnake(t *testing.T) {
+	// 蛇头撞上
+	lvl := testLevel(t, "hazard-period: 100\n..=...\n......\n..^...\n......\n......\n")
+	g := NewGame(WithSeed(1), WithLevels(lvl))
+	setFood(g, Point{5, 4})
+	g.Step()
+	g.Step()
+	if !g.Over() {
+		t.Error("running into a hazard should end the game")
+	}
+
+	// 巡逻障碍撞上蛇身
+	lvl = testLevel(t, "hazard-period: 1\n......\n=.....\n.....>\n......\n")
+	g = NewGame(WithSeed(1), WithLevels(lvl))
+	setFood(g, Point{0, 0})
+	g.snakes[0].body = []Point{{5, 2}, {4, 2}, {4, 1}}
+	g.syncBoard()
+	g.Turn(Down)
+	for i := 0; i < 4 && !g.Over(); i++ {
+		g.Step()
+	}
+	if !g.Over() {
+		t.Errorf("a hazard moving into the body should end the game: hazards %v snake %v", g.State().Hazards, g.State().Snake)
+	}
+}
+
+// TestFoodAvoidsEntities 食物不会生成在传送门和巡逻障碍上
+func TestFoodAvoidsEntities(t *testing.T) {
+	lvl := testLevel(t, "1=2\n...\n.^.\n...\n...\n2|1\n")
+	for seed := int64(1); seed <= 100; seed++ {
+		g := NewGame(WithSeed(seed), WithLevels(lvl))
+		p := g.apple()
+		if g.board[p.Y][p.X] != cellEmpty || g.hazardAt(p) {
+			t.Fatalf("seed %d: food %v spawned on an entity", seed, p)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package snake
+
+// ============================================
+// 食物 - 种类、效果与倒计时
+// ============================================
+// 面板上始终有一个苹果，吃掉后立即在别处生成新的苹果
+// 此外每移动一步都有机会随机出现一个限时的特殊食物，最多同时存在 MaxSpecialFoods 个
+
+type FoodKind int
+
+const (
+	Apple    FoodKind = iota // 苹果：普通食物，一直存在
+	Bonus                    // 奖励水果：分数更高，倒计时结束后消失
+	Golden                   // 金色食物：分数最高，出现时间最短
+	Shrink                   // 缩小药丸：蛇身变短（不会短于初始长度）
+	SpeedUp                  // 加速：一段时间内移动加快，额外得分
+	SlowDown                 // 减速：一段时间内移动变慢
+)
+
+const (
+	MaxSpecialFoods = 2  // 同时存在的特殊食物上限
+	EffectTicks     = 40 // 加速/减速效果持续的步数
+	specialChance   = 25 // 每移动一步有 1/specialChance 的概率生成特殊食物
+	blinkTicks      = 10 // 剩余步数不多于此值时食物开始闪烁
+)
+
+// foodInfo 一种食物的属性
+type foodInfo struct {
+	name   string
+	points int // 得分
+	grow   int // 长度变化（负数表示缩短）
+	ttl    int // 出现后存在的步数，0 表示一直存在
+	weight int // 作为特殊食物随机生成的权重，0 表示不会随机生成
+}
+
+var foodInfos = [...]foodInfo{
+	Apple:    {name: "Apple", points: 10, grow: 1},
+	Bonus:    {name: "Bonus", points: 30, grow: 1, ttl: 30, weight: 4},
+	Golden:   {name: "Golden", points: 50, grow: 1, ttl: 20, weight: 1},
+	Shrink:   {name: "Shrink", points: 5, grow: -2, ttl: 40, weight: 3},
+	SpeedUp:  {name: "SpeedUp", points: 20, ttl: 40, weight: 2},
+	SlowDown: {name: "SlowDown", ttl: 40, weight: 2},
+}
+
+// String 返回食物种类名称
+func (k FoodKind) String() string {
+	if int(k) < len(foodInfos) {
+		return foodInfos[k].name
+	}
+	return "Unknown"
+}
+
+// Food 面板上的一个食物
+type Food struct {
+	Pos  Point
+	Kind FoodKind
+	TTL  int // 剩余步数，0 表示不会消失
+}
+
+// foodAt 返回位于 p 的食物下标，没有食物时返回 -1
+// 面板上最多只有 1+MaxSpecialFoods 个食物，直接遍历即可
+func (g *Game) foodAt(p Point) int {
+	for i, f := range g.foods {
+		if f.Pos == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// apple 返回苹果的位置（获胜后面板上没有苹果时返回零值）
+func (g *Game) apple() Point {
+	for _, f := range g.foods {
+		if f.Kind == Apple {
+			return f.Pos
+		}
+	}
+	return Point{}
+}
+
+// freeCells 返回既没有蛇、障碍物、传送门和巡逻障碍，也没有食物的格子
+func (g *Game) freeCells() []Point {
+	var cells []Point
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			if p := (Point{x, y}); g.board[y][x] == cellEmpty && g.foodAt(p) < 0 && !g.hazardAt(p) {
+				cells = append(cells, p)
+			}
+		}
+	}
+	return cells
+}
+
+// spawnFood 在空白位置生成一个新的苹果
+// 算法：收集所有空白位置（不含蛇身、障碍物和其他食物），随机选择一个
+// 剩下的空格都被特殊食物占着时，清掉特殊食物给苹果让位
+// 没有空白位置说明蛇已经填满面板，玩家获胜
+func (g *Game) spawnFood() {
+	cells := g.freeCells()
+	if len(cells) == 0 && len(g.foods) > 0 {
+		g.foods = g.foods[:0]
+		cells = g.freeCells()
+	}
+
+	// 如果有空白位置，随机选择一个作为食物
+	if len(cells) > 0 {
+		g.foods = append(g.foods, Food{Pos: cells[g.rng.Intn(len(cells))], Kind: Apple})
+		return
+	}
+
+	// 面板已满：获胜（竞技场没有胜负，等有空位时再补充）
+	if g.arena {
+		return
+	}
+	g.won = true
+	g.gameOver = true
+}
+
+// tickFoods 每移动一步调用一次：推进倒计时，移除过期的食物，按概率生成特殊食物
+func (g *Game) tickFoods() {
+	kept := g.foods[:0]
+	specials := 0
+	for _, f := range g.foods {
+		if f.TTL > 0 {
+			f.TTL--
+			if f.TTL == 0 {
+				continue
+			}
+		}
+		if f.Kind != Apple {
+			specials++
+		}
+		kept = append(kept, f)
+	}
+	g.foods = kept
+
+	if g.effectTicks > 0 {
+		g.effectTicks--
+	}
+
+	if specials >= MaxSpecialFoods || g.rng.Intn(specialChance) != 0 {
+		return
+	}
+	kind := g.randomSpecial()
+	if cells := g.freeCells(); len(cells) > 0 {
+		pos := cells[g.rng.Intn(len(cells))]
+		g.foods = append(g.foods, Food{Pos: pos, Kind: kind, TTL: foodInfos[kind].ttl})
+	}
+}
+
+// randomSpecial 按权重随机选择一种特殊食物
+func (g *Game) randomSpecial() FoodKind {
+	total := 0
+	for _, info := range foodInfos {
+		total += info.weight
+	}
+	n := g.rng.Intn(total)
+	for kind, info := range foodInfos {
+		if n < info.weight {
+			return FoodKind(kind)
+		}
+		n -= info.weight
+	}
+	return Bonus
+}
+
+// eat 蛇 p 吃掉下标为 i 的食物：移除它并结算得分、长度和速度效果
+// 速度效果作用于整个面板（所有蛇共用同一个移动节拍）
+func (g *Game) eat(p *player, i int) FoodKind {
+	food := g.foods[i]
+	g.foods = append(g.foods[:i], g.foods[i+1:]...)
+
+	info := foodInfos[food.Kind]
+	points := info.points
+	if g.boostTicks > 0 {
+		points *= 2 // 加速时得分翻倍
+	}
+	p.score += points
+	p.length = max(p.length+info.grow, startLength)
+	if food.Kind == SpeedUp || food.Kind == SlowDown {
+		g.effect, g.effectTicks = food.Kind, EffectTicks
+	}
+	return food.Kind
+}
@@ -0,0 +1,112 @@
+package snake
+
+import "testing"
+
+// TestEatFoodKinds 每种食物的得分、长度和速度效果
+func TestEatFoodKinds(t *testing.T) {
+	tests := []struct {
+		kind       FoodKind
+		score, len int
+		speed      int
+	}{
+		{Apple, 10, 7, SpeedNormal - 2},
+		{Bonus, 30, 7, SpeedNormal - 6},
+		{Golden, 50, 7, SpeedNormal - 10},
+		{Shrink, 5, 4, SpeedNormal - 1},
+		{SpeedUp, 20, 6, (SpeedNormal - 4) * 2 / 3},
+		{SlowDown, 0, 6, SpeedNormal * 3 / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			g := NewGame(WithSeed(1))
+			// 先长到 6 节，方便观察缩小药丸的效果
+			g.snakes[0].length = 6
+			for i := 0; i < 3; i++ {
+				g.Step(Left)
+			}
+			g.snakes[0].score = 0
+
+			head := g.snakes[0].body[0]
+			g.foods = []Food{{Pos: Point{head.X - 1, head.Y}, Kind: tt.kind, TTL: 5}}
+			if tt.kind != Apple {
+				g.foods = append(g.foods, Food{Pos: Point{0, 0}, Kind: Apple})
+			}
+			g.Step()
+
+			if s := g.State(); s.Score != tt.score || len(s.Snake) != tt.len || s.Speed != tt.speed {
+				t.Errorf("score %d length %d speed %d, want %d %d %d", s.Score, len(s.Snake), s.Speed, tt.score, tt.len, tt.speed)
+			}
+			checkBoard(t, g)
+			if apples := countKind(g, Apple); apples != 1 {
+				t.Errorf("%d apples on the board, want 1", apples)
+			}
+		})
+	}
+}
+
+// countKind 面板上某种食物的数量
+func countKind(g *Game, kind FoodKind) int {
+	n := 0
+	for _, f := range g.foods {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// TestSpecialFoodExpires 特殊食物倒计时结束后消失，苹果一直存在
+func TestSpecialFoodExpires(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	g.foods = []Food{{Pos: Point{0, 0}, Kind: Apple}, {Pos: Point{19, 0}, Kind: Bonus, TTL: 2}}
+	g.Step()
+	if i := g.foodAt(Point{19, 0}); i < 0 || g.foods[i].TTL != 1 {
+		t.Fatalf("bonus after one step: %v", g.foods)
+	}
+	g.Step()
+	if g.foodAt(Point{19, 0}) >= 0 {
+		t.Errorf("bonus should have expired: %v", g.foods)
+	}
+	if g.foodAt(Point{0, 0}) < 0 {
+		t.Errorf("apple should never expire: %v", g.foods)
+	}
+}
+
+// TestSpecialFoodLimit 长时间游戏中特殊食物会出现，但不超过上限，也不会重叠
+func TestSpecialFoodLimit(t *testing.T) {
+	seen := map[FoodKind]bool{}
+	for seed := int64(1); seed <= 20; seed++ {
+		g := NewGame(WithSeed(seed), WithWrap(true))
+		for i := 0; i < 300 && !g.Over(); i++ {
+			chaseFood(g, 1)
+			if n := len(g.foods) - countKind(g, Apple); n > MaxSpecialFoods {
+				t.Fatalf("seed %d: %d special foods on the board", seed, n)
+			}
+			occupied := map[Point]bool{}
+			for _, f := range g.foods {
+				if occupied[f.Pos] || g.board[f.Pos.Y][f.Pos.X] != cellEmpty {
+					t.Fatalf("seed %d: food %v overlaps something", seed, f)
+				}
+				occupied[f.Pos] = true
+				seen[f.Kind] = true
+			}
+		}
+	}
+	for kind := range foodInfos {
+		if !seen[FoodKind(kind)] {
+			t.Errorf("%v never appeared", FoodKind(kind))
+		}
+	}
+}
+
+// TestShrinkKeepsStartLength 缩小药丸不会让蛇短于初始长度
+func TestShrinkKeepsStartLength(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	head := g.snakes[0].body[0]
+	g.foods = []Food{{Pos: Point{head.X, head.Y - 1}, Kind: Shrink, TTL: 5}, {Pos: Point{0, 0}, Kind: Apple}}
+	g.Step()
+	if len(g.snakes[0].body) != startLength || g.snakes[0].length != startLength {
+		t.Errorf("length %d (target %d), want %d", len(g.snakes[0].body), g.snakes[0].length, startLength)
+	}
+	checkBoard(t, g)
+}
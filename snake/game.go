@@ -1,16 +1,35 @@
 package snake
 
-import "math/rand"
+import (
+	"math/rand"
+	"time"
+)
 
 // ============================================
 // 常量定义 - 游戏参数配置
 // ============================================
 
 const (
-	BoardWidth  = 20 // 游戏面板宽度（格子数）
-	BoardHeight = 15 // 游戏面板高度（格子数）
+	BoardWidth  = 20 // 默认游戏面板宽度（格子数），关卡可以指定其他尺寸
+	BoardHeight = 15 // 默认游戏面板高度（格子数）
 	SpeedNormal = 150
 	SpeedFast   = 80
+
+	// MaxQueuedTurns 最多缓存的转向输入数
+	// 每次移动只消耗一个，使一个节拍内连按的两个方向键（例如 ↑ 再 ←）都能生效
+	MaxQueuedTurns = 3
+
+	// BoostTicks 按一次加速键持续的步数
+	// 终端没有松开按键的事件，按住时按键自动重复会不断续上，松开后很快恢复原速
+	BoostTicks = 4
+)
+
+// 面板单元格状态
+const (
+	cellEmpty  = 0 // 空白
+	cellSnake  = 1 // 被蛇身体占用
+	cellWall   = 2 // 关卡中的障碍物
+	cellPortal = 3 // 关卡中的传送门（见 entity.go）
 )
 
 // ============================================
@@ -34,7 +53,7 @@ const (
 // Point 表示游戏面板上的一个坐标点
 // 用于表示蛇的身体 segments 和食物的位置
 type Point struct {
-	x, y int
+	X, Y int
 }
 
 // ============================================
@@ -43,201 +62,520 @@ type Point struct {
 // 包含游戏的所有状态信息
 
 type Game struct {
-	// 游戏面板：0表示空，1表示被蛇身体占用
-	board [][]int
+	// 游戏面板：cellEmpty 表示空，cellSnake 表示被蛇身体占用，cellWall 表示障碍物
+	// 在 move 中随蛇头前进、蛇尾离开增量更新，与 snakes 始终保持一致
+	board         [][]int
+	width, height int     // 面板尺寸（没有关卡时为 openWidth x openHeight）
+	walls         []Point // 当前关卡的障碍物
+
+	// 当前关卡的传送门和巡逻障碍（见 entity.go），ticks 为本关已经移动的步数
+	portals      []Portal
+	hazards      []Hazard
+	hazardPeriod int
+	ticks        int
+
+	// 本关蛇头经过每个格子的次数（见 heatmap.go）
+	heat Heatmap
+
+	// 墙壁遮罩：游戏进行中变成墙的格子，在面板上与障碍物一样标记为 cellWall
+	// 大逃杀（见 royale.go）每隔 shrinkEvery 关闭一圈，ring 为已关闭的圈数
+	mask        [][]bool
+	shrinkEvery time.Duration
+	ring        int
 
-	// 蛇身体：从头部(snake[0])到尾部排列的坐标列表
-	snake []Point
+	// 本局的游戏时间：每步按移动间隔累计，不含暂停（用于缩圈和挑战模式的计时）
+	elapsed time.Duration
 
-	// 食物在面板上的位置
-	food Point
+	// 单人游戏的模式和目标长度模式的目标（见 mode.go）
+	mode   Mode
+	target int
 
-	// direction: 蛇当前的实际移动方向
-	// nextDir: 用户输入的下一个方向（用于防止快速反向导致自杀）
-	direction Direction
-	nextDir   Direction
+	// 没有关卡时的面板尺寸（默认 BoardWidth x BoardHeight，见 size.go）
+	openWidth, openHeight int
 
-	// 游戏状态
-	score    int  // 当前得分（每吃一个食物+10分）
-	length   int  // 蛇的目标长度（随得分增加）
+	// 关卡：依次挑战的关卡列表（为空表示空白面板）和当前关卡下标
+	levels []*Level
+	level  int
+
+	// 面板上的蛇：snakes[0] 是一号玩家，双人模式下 snakes[1] 是二号玩家
+	// 每局开始时按 players 重新创建
+	snakes  []*player
+	players int
+
+	// 多人对战每局的结果（重新开始不会清除，用于记分板）
+	rounds []Round
+
+	// 面板上的食物：始终有一个苹果，另有若干限时的特殊食物
+	foods []Food
+
+	// 加速/减速效果及其剩余步数（effectTicks 为 0 表示没有效果）
+	effect      FoodKind
+	effectTicks int
+
+	// 速度曲线（见 difficulty.go）和加速键剩余的步数（加速时得分翻倍）
+	difficulty Difficulty
+	boostTicks int
+
+	// 游戏状态（得分和长度属于每条蛇，见 player）
 	paused   bool // 游戏是否暂停
 	gameOver bool // 游戏是否结束
+	won      bool // 蛇填满了整个面板（同时 gameOver 也为 true）
+	wrap     bool // 穿墙模式：从一侧边界离开时从另一侧进入
+	arena    bool // 竞技场：蛇随时加入、离开和复活，没有局的概念（见 arena.go）
 
 	// 依赖组件
-	rng *rand.Rand // 随机数生成器（用于生成食物位置）
+	rng   RNG   // 随机数生成器（用于生成食物位置和竞技场的出生点）
+	seed  int64 // 创建游戏时的种子（WithSeed）
+	fixed bool  // 每次重新开始都从 seed 重新生成随机数（每日挑战：每局的食物序列都相同）
+}
+
+// ============================================
+// RNG 随机数接口
+// ============================================
+// 与 tetris.RNG 相同，用于支持测试时的依赖注入（见 WithRNG）
+
+type RNG interface {
+	Intn(n int) int
+}
+
+// randRNG 标准库随机数的实现（没有指定种子或 RNG 时使用）
+type randRNG struct{}
+
+// Intn 返回 [0, n) 范围内的随机整数
+func (r randRNG) Intn(n int) int {
+	return rand.Intn(n)
 }
 
 // ============================================
 // 工厂方法
 // ============================================
 
-// NewGame 创建并初始化一个新的贪吃蛇游戏
-// screen: 用于渲染的 tcell 屏幕对象
-func NewGame(screen interface{}) *Game {
-	// 初始化游戏面板
-	board := make([][]int, BoardHeight)
-	for i := range board {
-		board[i] = make([]int, BoardWidth)
-	}
+// Option 创建游戏时的选项（见 NewGame）
+type Option func(*Game)
 
-	// 初始化蛇的起始位置（面板中间，向上移动）
-	snake := []Point{
-		{BoardWidth / 2, BoardHeight / 2},
-		{BoardWidth / 2, BoardHeight / 2 + 1},
-		{BoardWidth / 2, BoardHeight / 2 + 2},
-	}
+// WithSeed 使用固定种子的随机数，相同的种子和操作序列总是得到相同的对局
+func WithSeed(seed int64) Option {
+	return func(g *Game) { g.seed, g.rng = seed, rand.New(rand.NewSource(seed)) }
+}
 
-	return &Game{
-		board:     board,
-		snake:     snake,
-		food:      Point{},
-		direction: Up,
-		nextDir:   Up,
-		score:     0,
-		length:    3,
-		paused:    false,
-		gameOver:  false,
-		rng:       rand.New(rand.NewSource(rand.Int63())),
+// WithFixedSeed 重新开始时回到 WithSeed 的种子，而不是沿用上一局的随机数
+// 用于每日挑战：当天的每一局（包括练习）都是同样的食物序列
+func WithFixedSeed() Option {
+	return func(g *Game) { g.fixed = true }
+}
+
+// WithRNG 使用指定的随机数生成器（例如测试中按顺序返回预设值的实现）
+func WithRNG(rng RNG) Option {
+	return func(g *Game) { g.rng = rng }
+}
+
+// WithSize 空白面板的尺寸（默认 BoardWidth x BoardHeight，见 ParseBoardSize），关卡使用自己的尺寸
+func WithSize(width, height int) Option {
+	return func(g *Game) { g.openWidth, g.openHeight = width, height }
+}
+
+// WithPlayers 面板上蛇的数量（1 到 MaxSnakes），大于 1 时是多人对战
+func WithPlayers(n int) Option {
+	return func(g *Game) { g.players = min(max(n, 1), MaxSnakes) }
+}
+
+// WithWrap 穿墙模式
+func WithWrap(wrap bool) Option {
+	return func(g *Game) { g.wrap = wrap }
+}
+
+// WithLevels 依次挑战的关卡（只用于单人游戏）
+func WithLevels(levels ...*Level) Option {
+	return func(g *Game) { g.levels = levels }
+}
+
+// WithDifficulty 速度曲线（见 Difficulties，默认普通难度）
+func WithDifficulty(d Difficulty) Option {
+	return func(g *Game) { g.SetDifficulty(d) }
+}
+
+// WithMode 单人游戏的挑战模式，target 为目标长度模式的目标（见 SetMode）
+func WithMode(m Mode, target int) Option {
+	return func(g *Game) { g.mode, g.target = m, target }
+}
+
+// WithShrink 大逃杀的缩圈间隔（见 royale.go），0 表示不缩圈
+func WithShrink(every time.Duration) Option {
+	return func(g *Game) { g.SetShrink(every) }
+}
+
+// NewGame 按选项创建并初始化一个新的贪吃蛇游戏：布置面板、放置蛇并生成第一个食物
+// 默认是 BoardWidth x BoardHeight 的单人空白面板、普通难度、无尽模式，食物位置随机
+//
+//	g := snake.NewGame(snake.WithSeed(42), snake.WithSize(30, 20), snake.WithMode(snake.ModeTimeAttack, 0))
+func NewGame(opts ...Option) *Game {
+	g := &Game{
+		openWidth:  BoardWidth,
+		openHeight: BoardHeight,
+		difficulty: Difficulties[1],
+		players:    1,
+		rng:        randRNG{},
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	g.SetMode(g.mode, g.target) // 多人对战和关卡只能是无尽模式
+
+	// 初始化面板和蛇的起始位置
+	g.startLevel()
+	g.spawnFood()
+	return g
 }
 
 // ============================================
 // 核心游戏逻辑
 // ============================================
 
-// spawnFood 在空白位置生成一个新的食物
-// 算法：收集所有空白位置，随机选择一个作为食物位置
-func (g *Game) spawnFood() {
-	var emptyPoints []Point
+// currentLevel 返回当前关卡，没有关卡时返回 nil
+func (g *Game) currentLevel() *Level {
+	if g.level < len(g.levels) {
+		return g.levels[g.level]
+	}
+	return nil
+}
 
-	// 遍历整个面板，找出所有空白位置
-	for y := 0; y < BoardHeight; y++ {
-		for x := 0; x < BoardWidth; x++ {
-			if g.board[y][x] == 0 {
-				emptyPoints = append(emptyPoints, Point{x, y})
-			}
+// startLevel 按当前关卡布置面板：尺寸、障碍物、蛇的起始位置和方向
+// 没有关卡时是 openWidth x openHeight 的空白面板，蛇沿水平方向均匀分布在中间一行，向上移动
+// 蛇恢复初始长度，得分和统计保持不变（关卡只用于单人游戏），热力图从头记录
+func (g *Game) startLevel() {
+	g.width, g.height, g.walls = g.openWidth, g.openHeight, nil
+	g.portals, g.hazards, g.ticks = nil, nil, 0
+	lvl := g.currentLevel()
+	if lvl != nil {
+		g.width, g.height, g.walls = lvl.Width, lvl.Height, lvl.Walls
+		g.portals = lvl.Portals
+		g.hazards = append([]Hazard(nil), lvl.Hazards...) // 巡逻障碍会移动，不能修改关卡本身
+		g.hazardPeriod = max(lvl.HazardPeriod, 1)
+	}
+
+	g.board = make([][]int, g.height)
+	for i := range g.board {
+		g.board[i] = make([]int, g.width)
+	}
+	g.mask, g.ring, g.elapsed = newMask(g.width, g.height), 0, 0
+
+	prev := g.snakes
+	g.snakes = make([]*player, g.players)
+	for i := range g.snakes {
+		head, dir := Point{(i + 1) * g.width / (g.players + 1), g.height / 2}, Up
+		if lvl != nil {
+			head, dir = lvl.Start, lvl.Dir
+		}
+		g.snakes[i] = newPlayer(head, dir)
+		if i < len(prev) {
+			g.snakes[i].score, g.snakes[i].stats = prev[i].score, prev[i].stats
 		}
 	}
+	g.heat = newHeatmap(g.width, g.height)
+	for _, p := range g.snakes {
+		g.heat.visit(p.head())
+	}
+	g.foods = nil
+	g.effectTicks = 0
+	g.boostTicks = 0
+	g.syncBoard()
+}
 
-	// 如果有空白位置，随机选择一个作为食物
-	if len(emptyPoints) > 0 {
-		g.food = emptyPoints[g.rng.Intn(len(emptyPoints))]
+// syncBoard 根据障碍物和所有蛇的身体重建整个面板
+// 只在新建、重置和换关时使用，移动过程中由 move 增量更新
+func (g *Game) syncBoard() {
+	for y := range g.board {
+		for x := range g.board[y] {
+			g.board[y][x] = cellEmpty
+		}
+	}
+	for _, p := range g.walls {
+		g.board[p.Y][p.X] = cellWall
+	}
+	for _, p := range g.closedCells() {
+		g.board[p.Y][p.X] = cellWall
+	}
+	for _, pt := range g.portals {
+		g.board[pt.A.Y][pt.A.X] = cellPortal
+		g.board[pt.B.Y][pt.B.X] = cellPortal
+	}
+	for _, p := range g.snakes {
+		for _, c := range p.body {
+			g.board[c.Y][c.X] = cellSnake
+		}
 	}
 }
 
-// collides 检测给定坐标是否会发生碰撞
-// head: 要检测的坐标点
+// levelComplete 蛇是否已达到当前关卡的目标长度（竞技场不换关）
+func (g *Game) levelComplete() bool {
+	lvl := g.currentLevel()
+	return !g.arena && lvl != nil && lvl.Target > 0 && g.snakes[0].length >= lvl.Target
+}
+
+// nextLevel 进入下一关，最后一关完成时玩家获胜
+func (g *Game) nextLevel() {
+	if g.level+1 >= len(g.levels) {
+		g.won = true
+		g.gameOver = true
+		return
+	}
+	g.level++
+	g.startLevel()
+	g.spawnFood()
+}
+
+// collides 检测第 i 条蛇的蛇头移动到 heads[i] 是否会发生碰撞
+// heads: 这一步所有蛇的新头部位置；crashed: 已判定撞死（不会移动）的蛇
 // 返回值：如果发生碰撞返回 true，否则返回 false
 //
 // 碰撞检测包括：
-// 1. 撞墙检测：坐标超出面板边界
-// 2. 撞自身检测：坐标与蛇身体（除尾部外）重合
-func (g *Game) collides(head Point) bool {
+// 1. 撞墙检测：坐标超出面板边界（穿墙模式下 move 已将坐标折回面板内）
+// 2. 撞蛇身和障碍物检测：直接查询面板占用情况，O(1)（墙壁遮罩关闭的格子也在面板上标记为墙）
+//    例外：某条蛇的蛇尾在这一步会移开（没有吃到会变长的食物且已达到目标长度），撞上它不算碰撞
+// 3. 迎头相撞：两条蛇的新头部在同一格，双方都撞死
+// 4. 撞上巡逻障碍（传送门由 nextHead 处理，新头部落在传送门上说明出口被另一个传送门挡住）
+func (g *Game) collides(i int, heads []Point, crashed []bool) bool {
+	head := heads[i]
+
 	// 撞墙检测
-	if head.x < 0 || head.x >= BoardWidth || head.y < 0 || head.y >= BoardHeight {
+	if head.X < 0 || head.X >= g.width || head.Y < 0 || head.Y >= g.height {
 		return true
 	}
 
-	// 撞自身检测（跳过蛇尾，因为蛇会移动）
-	for i := 0; i < len(g.snake)-1; i++ {
-		if g.snake[i] == head {
+	// 巡逻障碍
+	if g.hazardAt(head) {
+		return true
+	}
+
+	// 迎头相撞
+	for j, q := range g.snakes {
+		if j != i && q.alive && heads[j] == head {
 			return true
 		}
 	}
 
-	return false
+	// 撞蛇身/障碍物检测（障碍物不可能是蛇尾，总是碰撞）
+	if g.board[head.Y][head.X] == cellEmpty {
+		return false
+	}
+	for j, q := range g.snakes {
+		if q.alive && !crashed[j] && head == q.tail() && g.tailMoves(q, heads[j]) {
+			return false
+		}
+	}
+	return true
 }
 
-// move 让蛇移动一格
-// 返回值：移动是否成功（失败时游戏结束）
-//
-// 移动逻辑：
-// 1. 更新实际方向为用户输入的方向
-// 2. 计算新的头部位置
-// 3. 检测碰撞（撞墙或撞自身则游戏结束）
-// 4. 检测是否吃到食物（头部与食物重合）
-// 5. 添加新头部，根据是否吃到食物决定是否移除尾部
-func (g *Game) move() bool {
-	// 更新实际移动方向
-	g.direction = g.nextDir
+// tailMoves 蛇 p 的头部移动到 head 时，蛇尾是否会随之离开原位置
+// 只有吃到会让蛇变长的食物时蛇尾才留在原地
+func (g *Game) tailMoves(p *player, head Point) bool {
+	if i := g.foodAt(head); i >= 0 && foodInfos[g.foods[i].Kind].grow > 0 {
+		return false
+	}
+	return len(p.body) >= p.length
+}
 
-	// 计算新头部位置
-	head := g.snake[0]
-	switch g.direction {
+// nextHead 返回从 head 沿 d 方向移动一格后的位置
+// 穿墙模式下越界的坐标从对侧进入；进入传送门时从成对的传送门沿原方向再走一格
+func (g *Game) nextHead(head Point, d Direction) Point {
+	next := g.step(head, d)
+	if exit, ok := g.portalExit(next); ok {
+		return g.step(exit, d)
+	}
+	return next
+}
+
+// step 返回从 head 沿 d 方向移动一格后的位置（不处理传送门）
+func (g *Game) step(head Point, d Direction) Point {
+	switch d {
 	case Up:
-		head.y--
+		head.Y--
 	case Down:
-		head.y++
+		head.Y++
 	case Left:
-		head.x--
+		head.X--
 	case Right:
-		head.x++
+		head.X++
 	}
+	if g.wrap {
+		head.X = (head.X + g.width) % g.width
+		head.Y = (head.Y + g.height) % g.height
+	}
+	return head
+}
 
-	// 碰撞检测
-	if g.collides(head) {
-		g.gameOver = true
-		return false
+// move 让所有活着的蛇同时移动一格
+// 返回值：一号蛇是否仍然活着（单人模式下失败即游戏结束）
+//
+// 移动逻辑：
+// 1. 每条蛇从转向队列取出一个方向作为实际方向（队列为空则保持原方向），计算新的头部位置
+// 2. 检测碰撞：先判定所有蛇，再一起移动；撞死的蛇不移动，它的蛇尾也不会让出位置
+// 3. 检测是否吃到食物（头部与食物重合），结算得分、长度和效果
+// 4. 添加新头部，移除超出目标长度的尾部（缩小药丸一次会移除多节）
+// 5. 同步更新面板：清除离开的尾部，再标记新头部（蛇头可能正好进入刚离开的蛇尾格子），并记入热力图
+// 6. 达到关卡目标长度时进入下一关；吃掉的苹果在别处重新生成
+// 7. 实体更新：巡逻障碍前进，撞上的蛇撞死；大逃杀到时间时缩小一圈，压在圈上的蛇撞死；
+//    挑战模式时间到或达到目标长度时结束
+// 8. 单人模式下蛇撞死即结束；多人模式下只剩不到两条蛇时本局结束；竞技场中撞死的蛇从面板上移除
+// 9. 推进食物倒计时，随机生成特殊食物
+func (g *Game) move() bool {
+	// 这一步的移动间隔，计入存活时间
+	interval := time.Duration(g.getSpeed()) * time.Millisecond
+
+	// 计算新头部位置
+	heads := make([]Point, len(g.snakes))
+	for i, p := range g.snakes {
+		if p.alive {
+			p.nextTurn()
+			heads[i] = g.nextHead(p.head(), p.direction)
+		}
+	}
+
+	// 碰撞检测：撞死的蛇不会移动，可能让原本要进入它蛇尾的蛇也撞上，反复判定直到稳定
+	crashed := make([]bool, len(g.snakes))
+	for changed := true; changed; {
+		changed = false
+		for i, p := range g.snakes {
+			if p.alive && !crashed[i] && g.collides(i, heads, crashed) {
+				crashed[i], changed = true, true
+			}
+		}
+	}
+	for i, p := range g.snakes {
+		if crashed[i] {
+			p.stats.Death, p.stats.Killer = g.deathCause(i, heads)
+		}
+	}
+	for i, p := range g.snakes {
+		if crashed[i] {
+			p.alive = false
+			if g.arena {
+				g.clearBody(p)
+			}
+		}
 	}
 
-	// 检测是否吃到食物
-	ateFood := (head == g.food)
+	// 移动并处理食物逻辑
+	ate, apples := false, 0
+	for i, p := range g.snakes {
+		if !p.alive {
+			continue
+		}
+		head := heads[i]
+		p.body = append([]Point{head}, p.body...)
+		if k := g.foodAt(head); k >= 0 {
+			ate = true
+			p.stats.Foods++
+			if g.eat(p, k) == Apple {
+				apples++
+			}
+		}
 
-	// 添加新头部到蛇身
-	g.snake = append([]Point{head}, g.snake...)
+		// 移除尾部以保持目标长度
+		for len(p.body) > p.length {
+			tail := p.tail()
+			p.body = p.body[:len(p.body)-1]
+			g.board[tail.Y][tail.X] = cellEmpty
+		}
 
-	// 处理食物逻辑
-	if ateFood {
-		g.score += 10  // 增加得分
-		g.length++     // 增加目标长度
-		g.spawnFood()  // 生成新食物
-	} else {
-		// 未吃到食物，移除尾部以保持长度
-		if len(g.snake) > g.length {
-			g.snake = g.snake[:len(g.snake)-1]
+		p.stats.Ticks++
+		p.stats.Time += interval
+		p.stats.MaxLength = max(p.stats.MaxLength, len(p.body))
+	}
+	for i, p := range g.snakes {
+		if p.alive {
+			g.board[heads[i].Y][heads[i].X] = cellSnake
+			g.heat.visit(heads[i])
 		}
 	}
 
-	return true
+	if ate && g.levelComplete() {
+		g.nextLevel() // 过关
+		return true
+	}
+	g.updateEntities()
+	for ; apples > 0 && !g.gameOver; apples-- {
+		g.spawnFood() // 生成新苹果
+	}
+	g.elapsed += interval
+	if !g.gameOver {
+		g.advanceZone()
+		g.checkChallenge()
+	}
+
+	// 判定本局是否结束（竞技场没有局，只按活着的蛇数补充苹果）
+	switch {
+	case g.arena:
+		g.fillApples()
+	case g.players == 1:
+		if !g.snakes[0].alive {
+			g.gameOver = true
+		}
+	case g.alive() <= 1 || g.gameOver:
+		g.endRound()
+	}
+	if !g.gameOver {
+		g.tickFoods()
+	}
+	if g.boostTicks > 0 {
+		g.boostTicks--
+	}
+
+	return len(g.snakes) > 0 && g.snakes[0].alive
+}
+
+// alive 返回还活着的蛇的数量
+func (g *Game) alive() int {
+	n := 0
+	for _, p := range g.snakes {
+		if p.alive {
+			n++
+		}
+	}
+	return n
 }
 
 // getSpeed 根据当前得分计算移动速度
 // 返回值：移动间隔（毫秒），分数越高速度越快
 //
-// 速度计算公式：基础速度 - 得分/5
-// 最小速度限制为 SpeedFast
+// 速度按难度的速度曲线计算（多人模式取最高得分），普通难度为：基础速度 - 得分/5，
+// 最小速度限制为 SpeedFast；加速/减速效果在此基础上再乘以 2/3 或 3/2，按住加速键时再减半
 func (g *Game) getSpeed() int {
-	speed := SpeedNormal - g.score/5
-	if speed < SpeedFast {
-		return SpeedFast
+	score := 0
+	for _, p := range g.snakes {
+		score = max(score, p.score)
+	}
+	speed := g.difficulty.interval(score)
+	if g.boostTicks > 0 {
+		speed /= 2
+	}
+	if g.effectTicks > 0 {
+		switch g.effect {
+		case SpeedUp:
+			speed = speed * 2 / 3
+		case SlowDown:
+			speed = speed * 3 / 2
+		}
 	}
 	return speed
 }
 
 // reset 重置游戏到初始状态
-// 用于游戏结束后重新开始
+// 用于游戏结束后重新开始（多人模式下开始新的一局，记分板保留）
 func (g *Game) reset() {
-	// 清空游戏面板
-	for y := range g.board {
-		for x := range g.board[y] {
-			g.board[y][x] = 0
-		}
+	if g.fixed {
+		g.rng = rand.New(rand.NewSource(g.seed))
 	}
 
-	// 重置蛇的位置
-	g.snake = []Point{
-		{BoardWidth / 2, BoardHeight / 2},
-		{BoardWidth / 2, BoardHeight / 2 + 1},
-		{BoardWidth / 2, BoardHeight / 2 + 2},
-	}
+	// 回到第一关，重置蛇的位置并据此重建面板
+	g.level = 0
+	g.snakes = nil
+	g.startLevel()
 
 	// 重置游戏状态
-	g.direction = Up
-	g.nextDir = Up
-	g.score = 0
-	g.length = 3
 	g.paused = false
 	g.gameOver = false
+	g.won = false
 
 	// 生成新的食物
 	g.spawnFood()
@@ -0,0 +1,206 @@
+package snake
+
+import "testing"
+
+// checkBoard 面板占用情况应与所有蛇的身体完全一致
+func checkBoard(t *testing.T, g *Game) {
+	t.Helper()
+	want := map[Point]bool{}
+	for _, s := range g.snakes {
+		for _, p := range s.body {
+			want[p] = true
+		}
+	}
+	for y := range g.board {
+		for x, cell := range g.board[y] {
+			if occupied := cell == cellSnake; occupied != want[Point{x, y}] {
+				t.Fatalf("board[%d][%d] = %d, snake occupies it: %v", y, x, cell, want[Point{x, y}])
+			}
+		}
+	}
+}
+
+// setFood 让面板上只剩一个位于 p 的苹果
+func setFood(g *Game, p Point) {
+	g.foods = []Food{{Pos: p, Kind: Apple}}
+}
+
+// TestBoardTracksSnake 移动过程中面板始终与蛇同步，食物不会出现在蛇身上
+func TestBoardTracksSnake(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		g := NewGame(WithSeed(seed), WithWrap(seed%2 == 0))
+		chaseFood(g, 500)
+		checkBoard(t, g)
+		for _, f := range g.foods {
+			if g.board[f.Pos.Y][f.Pos.X] != cellEmpty {
+				t.Fatalf("seed %d: food %v spawned on the snake", seed, f)
+			}
+		}
+	}
+}
+
+// TestFoodNeverUnderSnake 每次生成食物都落在空白格子上
+func TestFoodNeverUnderSnake(t *testing.T) {
+	g := NewGame(WithSeed(7))
+	for i := 0; i < 200 && !g.Over(); i++ {
+		score := g.snakes[0].score
+		chaseFood(g, 1)
+		if g.snakes[0].score > score {
+			for _, p := range g.snakes[0].body {
+				if g.foodAt(p) >= 0 {
+					t.Fatalf("food %v spawned on the snake after eating", g.foods[g.foodAt(p)])
+				}
+			}
+		}
+	}
+}
+
+// TestChaseTail 蛇头进入蛇尾刚离开的格子不算碰撞
+func TestChaseTail(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	setFood(g, Point{0, 0})
+	// 2x2 的环形：头部 (5,5)，沿逆时针排列，蛇尾 (5,6) 紧挨着头部
+	g.snakes[0].body = []Point{{5, 5}, {6, 5}, {6, 6}, {5, 6}}
+	g.snakes[0].length = 4
+	g.snakes[0].direction = Down
+	g.syncBoard()
+
+	if !g.move() {
+		t.Fatal("moving into the vacating tail should not collide")
+	}
+	checkBoard(t, g)
+
+	// 蛇正在变长时蛇尾不会移开，撞上去就是碰撞
+	g.snakes[0].length = 5
+	g.Turn(Right)
+	if g.move() {
+		t.Fatal("moving into the tail while growing should collide")
+	}
+}
+
+// almostFullGame 返回只剩一个空格（放着食物）的对局，再走一步即可填满面板
+func almostFullGame() *Game {
+	// 蛇形路径覆盖整个面板：第 0 行从左到右，第 1 行从右到左……
+	var path []Point
+	for y := 0; y < BoardHeight; y++ {
+		for i := 0; i < BoardWidth; i++ {
+			x := i
+			if y%2 == 1 {
+				x = BoardWidth - 1 - i
+			}
+			path = append(path, Point{x, y})
+		}
+	}
+
+	// 除 path[0] 外全部被蛇占据，蛇头在 path[1]，食物在 path[0]
+	g := NewGame(WithSeed(1))
+	g.snakes[0].body = append([]Point(nil), path[1:]...)
+	g.snakes[0].length = len(g.snakes[0].body)
+	setFood(g, path[0])
+	g.snakes[0].direction = Left
+	g.syncBoard()
+	return g
+}
+
+// TestWinOnFullBoard 填满面板时获胜而不是卡住
+func TestWinOnFullBoard(t *testing.T) {
+	g := almostFullGame()
+	g.Step()
+	if !g.won || !g.gameOver {
+		t.Fatalf("won = %v, gameOver = %v; want both true", g.won, g.gameOver)
+	}
+	if len(g.snakes[0].body) != BoardWidth*BoardHeight {
+		t.Errorf("snake length = %d, want %d", len(g.snakes[0].body), BoardWidth*BoardHeight)
+	}
+}
+
+// TestTurnQueue 一个节拍内连按的两个方向都会生效，每次移动消耗一个
+func TestTurnQueue(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	head := g.snakes[0].body[0]
+
+	// 向上移动中连按 ← ↓：原先只保留最后一个方向，现在先左转再下转（U 形掉头）
+	g.Turn(Left)
+	g.Turn(Down)
+	g.Step()
+	if g.snakes[0].direction != Left || g.snakes[0].body[0] != (Point{head.X - 1, head.Y}) {
+		t.Fatalf("after first step: direction %v head %v", g.snakes[0].direction, g.snakes[0].body[0])
+	}
+	g.Step()
+	if g.snakes[0].direction != Down || g.snakes[0].body[0] != (Point{head.X - 1, head.Y + 1}) {
+		t.Fatalf("after second step: direction %v head %v", g.snakes[0].direction, g.snakes[0].body[0])
+	}
+}
+
+// TestTurnQueueRejects 与前一个排队方向相反、重复或超出容量的输入被忽略
+func TestTurnQueueRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		turns []Direction
+		want  []Direction
+	}{
+		{"reverse of current", []Direction{Down}, nil},
+		{"same as current", []Direction{Up}, nil},
+		{"reverse of queued", []Direction{Left, Right}, []Direction{Left}},
+		{"duplicate of queued", []Direction{Left, Left, Up}, []Direction{Left, Up}},
+		{"bounded", []Direction{Left, Up, Right, Down, Left}, []Direction{Left, Up, Right}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGame(WithSeed(1))
+			for _, d := range tt.turns {
+				g.Turn(d)
+			}
+			if len(g.snakes[0].turns) != len(tt.want) {
+				t.Fatalf("queue = %v, want %v", g.snakes[0].turns, tt.want)
+			}
+			for i := range tt.want {
+				if g.snakes[0].turns[i] != tt.want[i] {
+					t.Fatalf("queue = %v, want %v", g.snakes[0].turns, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// fixedRNG 总是返回同一个值的随机数（取模保证在范围内）
+type fixedRNG int
+
+func (r fixedRNG) Intn(n int) int {
+	return int(r) % n
+}
+
+// TestNewGameOptions 选项决定面板尺寸、蛇的数量和模式；注入的 RNG 决定食物位置
+func TestNewGameOptions(t *testing.T) {
+	g := NewGame(WithRNG(fixedRNG(0)), WithSize(12, 10), WithPlayers(2), WithMode(ModeTimeAttack, 0))
+	s := g.State()
+	if s.Width != 12 || s.Height != 10 || len(s.Snakes) != 2 {
+		t.Errorf("board %dx%d with %d snakes, want 12x10 with 2", s.Width, s.Height, len(s.Snakes))
+	}
+	if g.mode != ModeEndless {
+		t.Errorf("versus mode = %v, want endless", g.mode)
+	}
+	// 第一个空格是左上角
+	if s.Food != (Point{0, 0}) {
+		t.Errorf("food at %+v, want the first free cell", s.Food)
+	}
+
+	// 默认：经典尺寸的单人游戏
+	d := NewGame().State()
+	if d.Width != BoardWidth || d.Height != BoardHeight || len(d.Snakes) != 1 || len(d.Foods) != 1 {
+		t.Errorf("default game: %dx%d, %d snakes, %d foods", d.Width, d.Height, len(d.Snakes), len(d.Foods))
+	}
+}
+
+// TestNewGameSeed 相同的种子得到相同的对局，与简写的构造函数一致
+func TestNewGameSeed(t *testing.T) {
+	a := NewGame(WithSeed(7), WithWrap(true))
+	b := NewGame(WithSeed(7), WithWrap(true))
+	for i := 0; i < 50; i++ {
+		a.Step(Left)
+		b.Step(Left)
+	}
+	if sa, sb := a.State(), b.State(); sa.Food != sb.Food || sa.Score != sb.Score || !sa.Wrap {
+		t.Errorf("seeded games diverged: %+v vs %+v", sa.Food, sb.Food)
+	}
+}
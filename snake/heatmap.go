@@ -0,0 +1,131 @@
+package snake
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ============================================
+// 热力图 - 蛇头经过每个格子的次数
+// ============================================
+// 每关开始时清零并记下蛇头的起始位置，之后每移动一步，活着的蛇的蛇头所在格子加一。
+// 多条蛇同时在面板上时合计所有蛇的蛇头。
+// 游戏结束后可以在面板上叠加显示（见 Renderer），也可以导出为 CSV 供分析
+
+// Heatmap 各格子被蛇头经过的次数，下标为 [y][x]
+type Heatmap [][]int
+
+// newHeatmap 创建 width x height 的空热力图
+func newHeatmap(width, height int) Heatmap {
+	h := make(Heatmap, height)
+	for y := range h {
+		h[y] = make([]int, width)
+	}
+	return h
+}
+
+// visit 记录蛇头经过 p 一次
+func (h Heatmap) visit(p Point) {
+	if p.Y >= 0 && p.Y < len(h) && p.X >= 0 && p.X < len(h[p.Y]) {
+		h[p.Y][p.X]++
+	}
+}
+
+// clone 返回热力图的副本
+func (h Heatmap) clone() Heatmap {
+	c := make(Heatmap, len(h))
+	for y, row := range h {
+		c[y] = append([]int(nil), row...)
+	}
+	return c
+}
+
+// Max 返回经过次数最多的格子的次数
+func (h Heatmap) Max() int {
+	most := 0
+	for _, row := range h {
+		for _, n := range row {
+			most = max(most, n)
+		}
+	}
+	return most
+}
+
+// Total 返回所有格子的经过次数之和
+func (h Heatmap) Total() int {
+	total := 0
+	for _, row := range h {
+		for _, n := range row {
+			total += n
+		}
+	}
+	return total
+}
+
+// WriteCSV 把热力图写成 CSV：每行对应面板的一行，每列是该格子的经过次数
+func (h Heatmap) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	for _, row := range h {
+		record := make([]string, len(row))
+		for x, n := range row {
+			record[x] = strconv.Itoa(n)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// level 把经过次数换算为 0~1 之间的热度，0 表示从未经过
+// 使用对数刻度，避免少数经过很多次的格子让其他格子都显示成冷色
+func (h Heatmap) level(n, most int) float64 {
+	if n <= 0 || most <= 0 {
+		return 0
+	}
+	return math.Log1p(float64(n)) / math.Log1p(float64(most))
+}
+
+// ============================================
+// 热力图配色
+// ============================================
+
+// heatStops 真彩色渐变的关键色，从冷（深蓝）到热（红）
+var heatStops = [...][3]float64{
+	{0, 0, 128},
+	{0, 128, 255},
+	{0, 200, 100},
+	{255, 220, 0},
+	{255, 40, 0},
+}
+
+// heatPalette 终端不支持真彩色时使用的 256 色渐变（xterm 色号），与 heatStops 的走向一致
+var heatPalette = []int{
+	17, 18, 19, 20, 21, 27, 33, 39, 45, 44, 43, 42, 41, 40,
+	76, 112, 148, 184, 220, 214, 208, 202, 196,
+}
+
+// trueColors 支持真彩色的终端报告的颜色数
+const trueColors = 1 << 24
+
+// HeatColor 返回热度 t（0~1）对应的颜色
+// colors 为终端支持的颜色数（tcell.Screen.Colors），不足真彩色时从 256 色调色板中选取
+func HeatColor(t float64, colors int) tcell.Color {
+	t = min(max(t, 0), 1)
+	if colors < trueColors {
+		return tcell.PaletteColor(heatPalette[int(math.Round(t*float64(len(heatPalette)-1)))])
+	}
+	pos := t * float64(len(heatStops)-1)
+	i := min(int(pos), len(heatStops)-2)
+	f := pos - float64(i)
+	a, b := heatStops[i], heatStops[i+1]
+	mix := func(c int) int32 {
+		return int32(math.Round(a[c] + (b[c]-a[c])*f))
+	}
+	return tcell.NewRGBColor(mix(0), mix(1), mix(2))
+}
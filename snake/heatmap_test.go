@@ -0,0 +1,136 @@
+package snake
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+)
+
+// TestHeatmapCounts 每移动一步蛇头所在格子加一，起始位置也计入，重新开始时清零
+func TestHeatmapCounts(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	start := g.State().Snake[0]
+	g.Step(Left)
+	g.Step(Up)
+	g.Step(Right)
+	g.Step(Down) // 回到起始位置
+
+	heat := g.Heatmap()
+	if len(heat) != BoardHeight || len(heat[0]) != BoardWidth {
+		t.Fatalf("heatmap is %dx%d, want %dx%d", len(heat[0]), len(heat), BoardWidth, BoardHeight)
+	}
+	if heat.Total() != 5 || heat[start.Y][start.X] != 2 || heat.Max() != 2 {
+		t.Errorf("total %d, start %d, max %d; want 5 2 2", heat.Total(), heat[start.Y][start.X], heat.Max())
+	}
+
+	// 返回的是副本
+	heat[0][0] = 100
+	if g.Heatmap().Max() != 2 {
+		t.Error("Heatmap should return a copy")
+	}
+
+	g.Reset(1)
+	if g.Heatmap().Total() != 1 {
+		t.Errorf("after reset total = %d, want 1 (the start cell)", g.Heatmap().Total())
+	}
+}
+
+// TestHeatmapVersus 多条蛇时合计所有活着的蛇的蛇头
+func TestHeatmapVersus(t *testing.T) {
+	g := NewGame(WithSeed(1), WithPlayers(2))
+	g.Step()
+	g.Step()
+	if got := g.Heatmap().Total(); got != 6 {
+		t.Errorf("total = %d, want 6 (2 snakes x 3 cells)", got)
+	}
+}
+
+func TestHeatmapWriteCSV(t *testing.T) {
+	heat := Heatmap{{0, 1, 2}, {10, 0, 3}}
+	var buf bytes.Buffer
+	if err := heat.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	if want := "0,1,2\n10,0,3\n"; buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestHeatColor 真彩色时使用渐变的两端颜色，否则退回 256 色调色板
+func TestHeatColor(t *testing.T) {
+	tests := []struct {
+		t      float64
+		colors int
+		want   tcell.Color
+	}{
+		{0, trueColors, tcell.NewRGBColor(0, 0, 128)},
+		{0.5, trueColors, tcell.NewRGBColor(0, 200, 100)},
+		{1, trueColors, tcell.NewRGBColor(255, 40, 0)},
+		{0.125, trueColors, tcell.NewRGBColor(0, 64, 192)},
+		{0, 256, tcell.PaletteColor(17)},
+		{1, 256, tcell.PaletteColor(196)},
+		{2, 256, tcell.PaletteColor(196)},
+	}
+	for _, tt := range tests {
+		if got := HeatColor(tt.t, tt.colors); got != tt.want {
+			t.Errorf("HeatColor(%v, %d) = %#x, want %#x", tt.t, tt.colors, got.Hex(), tt.want.Hex())
+		}
+	}
+}
+
+// TestRenderHeatmapGolden 游戏结束后面板显示热力图，信息面板显示图例
+func TestRenderHeatmapGolden(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	chaseFood(g, 40)
+	if !g.Over() {
+		t.Fatal("game should be over")
+	}
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	r := NewRenderer(screen, g, input.DefaultSnake())
+	r.heatmap = true
+	r.Render()
+	screentest.AssertScreen(t, "render_heatmap", screen)
+}
+
+// TestRunHeatmap 游戏结束后按 m 显示热力图，离开时导出 CSV
+func TestRunHeatmap(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	path := filepath.Join(t.TempDir(), "heat.csv")
+
+	// 蛇从中间向上移动，撞墙后显示热力图再退出
+	wait := time.Duration(BoardHeight/2+3) * 75 * time.Millisecond
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	go func() {
+		time.Sleep(wait)
+		screentest.Type(screen, 5*time.Millisecond, screentest.Rune('m'), screentest.Key(tcell.KeyEscape))
+	}()
+	if err := Run(screen, input.DefaultSnake(), Config{Seed: 1, Difficulty: "insane", Heatmap: path}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !screentest.Contains(screen, "HEATMAP:") || screentest.Contains(screen, "GAME OVER") {
+		t.Errorf("expected the heatmap overlay:\n%s", screentest.Dump(screen))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read heatmap: %v", err)
+	}
+	rows := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(rows) != BoardHeight || len(strings.Split(rows[0], ",")) != BoardWidth {
+		t.Errorf("csv has %d rows, first %q; want %d rows of %d", len(rows), rows[0], BoardHeight, BoardWidth)
+	}
+	// 蛇头从中间一直向上走到顶端
+	for y := 0; y <= BoardHeight/2; y++ {
+		if cells := strings.Split(rows[y], ","); cells[BoardWidth/2] != "1" {
+			t.Errorf("row %d: center cell = %s, want 1", y, cells[BoardWidth/2])
+		}
+	}
+}
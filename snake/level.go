@@ -0,0 +1,220 @@
+package snake
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"go-game/internal/textfile"
+)
+
+// ============================================
+// Level - 关卡
+// ============================================
+// 关卡文件是纯文本格式，例如：
+//
+//   # 以 # 加空格开头的行是注释
+//   name: Pillars
+//   target: 12
+//   ....................
+//   ..##............##..
+//   .........^..........
+//
+// - "键: 值" 形式的行是属性：name（关卡名，默认取文件名）、target（目标长度）、
+//   hazard-period（巡逻障碍前进一格所需的步数，默认 DefaultHazardPeriod）
+// - 其余行是地图，所有行等宽，地图的宽高就是面板尺寸
+//   '.' 空白  '#' 墙
+//   '^' 'v' '<' '>' 蛇头的起始位置和方向，身体沿反方向向后延伸两格
+//   '1'-'9' 传送门，相同数字的两个格子成为一对
+//   '=' 左右巡逻的障碍（先向右）  '|' 上下巡逻的障碍（先向下）
+// - target 为 0 表示没有目标长度（一直玩到撞墙或填满面板）
+
+type Level struct {
+	Name          string    // 关卡名
+	Width, Height int       // 面板尺寸
+	Walls         []Point   // 障碍物
+	Portals       []Portal  // 传送门
+	Hazards       []Hazard  // 巡逻障碍的起始位置和方向
+	HazardPeriod  int       // 巡逻障碍前进一格所需的步数
+	Start         Point     // 蛇头起始位置
+	Dir           Direction // 起始方向
+	Target        int       // 目标长度，达到后过关
+}
+
+// LevelCampaign 配置中表示“依次挑战所有关卡”的关卡名
+const LevelCampaign = "campaign"
+
+// levelDir 存档目录下存放用户自定义关卡的子目录
+const levelDir = "snake-levels"
+
+// levelExt 关卡文件扩展名
+const levelExt = ".txt"
+
+// startLength 蛇的初始长度（蛇头加两节身体）
+const startLength = 3
+
+// builtinLevels 内置关卡，按文件名顺序组成战役
+//
+//go:embed levels/*.txt
+var builtinLevels embed.FS
+
+// startDirections 地图中表示起始位置的字符及其方向
+var startDirections = map[rune]Direction{'^': Up, 'v': Down, '<': Left, '>': Right}
+
+// hazardDirections 地图中表示巡逻障碍的字符及其起始方向
+var hazardDirections = map[rune]Direction{'=': Right, '|': Down}
+
+// ParseLevel 解析关卡文件，name 为未指定 name 属性时使用的默认关卡名
+func ParseLevel(name string, r io.Reader) (*Level, error) {
+	lvl := &Level{Name: name, HazardPeriod: DefaultHazardPeriod}
+	var rows []string
+	starts := 0
+	portals := map[rune][]Point{} // 传送门编号 -> 位置
+
+	// 属性行
+	prop := func(key, value string) error {
+		switch key {
+		case "name":
+			lvl.Name = value
+		case "target":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid target %q", value)
+			}
+			lvl.Target = n
+		case "hazard-period":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid hazard-period %q", value)
+			}
+			lvl.HazardPeriod = n
+		default:
+			return fmt.Errorf("unknown property %q", key)
+		}
+		return nil
+	}
+
+	// 地图行
+	mapRow := func(text string) error {
+		y := len(rows)
+		if y > 0 && len([]rune(text)) != len([]rune(rows[0])) {
+			return fmt.Errorf("row width %d, want %d", len([]rune(text)), len([]rune(rows[0])))
+		}
+		for x, ch := range []rune(text) {
+			switch ch {
+			case '.':
+			case '#':
+				lvl.Walls = append(lvl.Walls, Point{x, y})
+			case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+				portals[ch] = append(portals[ch], Point{x, y})
+			case '=', '|':
+				lvl.Hazards = append(lvl.Hazards, Hazard{Pos: Point{x, y}, Dir: hazardDirections[ch]})
+			default:
+				d, ok := startDirections[ch]
+				if !ok {
+					return fmt.Errorf("unexpected %q in map", ch)
+				}
+				lvl.Start, lvl.Dir = Point{x, y}, d
+				starts++
+			}
+		}
+		rows = append(rows, text)
+		return nil
+	}
+	if err := textfile.Scan(r, prop, mapRow); err != nil {
+		return nil, err
+	}
+
+	// 校验
+	if len(rows) == 0 {
+		return nil, errors.New("level has no map")
+	}
+	lvl.Width, lvl.Height = len([]rune(rows[0])), len(rows)
+	if lvl.Name == "" {
+		return nil, errors.New("level has no name")
+	}
+	if starts != 1 {
+		return nil, fmt.Errorf("map must contain exactly one start (^ v < >), found %d", starts)
+	}
+	for _, ch := range "123456789" {
+		switch cells := portals[ch]; len(cells) {
+		case 0:
+		case 2:
+			lvl.Portals = append(lvl.Portals, Portal{cells[0], cells[1]})
+		default:
+			return nil, fmt.Errorf("portal %c must appear exactly twice, found %d", ch, len(cells))
+		}
+	}
+	blocked := make(map[Point]bool, len(lvl.Walls))
+	for _, p := range lvl.Walls {
+		blocked[p] = true
+	}
+	for _, pt := range lvl.Portals {
+		blocked[pt.A], blocked[pt.B] = true, true
+	}
+	for _, h := range lvl.Hazards {
+		blocked[h.Pos] = true
+	}
+	for _, p := range startBody(lvl.Start, lvl.Dir) {
+		if p.X < 0 || p.X >= lvl.Width || p.Y < 0 || p.Y >= lvl.Height || blocked[p] {
+			return nil, fmt.Errorf("snake body at %v is blocked", p)
+		}
+	}
+	if free := lvl.Width*lvl.Height - len(lvl.Walls) - 2*len(lvl.Portals); lvl.Target > free {
+		return nil, fmt.Errorf("target %d exceeds the %d free cells", lvl.Target, free)
+	}
+	if lvl.Target > 0 && lvl.Target <= startLength {
+		return nil, fmt.Errorf("target %d must be longer than the starting snake", lvl.Target)
+	}
+	return lvl, nil
+}
+
+// startBody 返回蛇的初始身体：蛇头在 head，身体沿 dir 的反方向延伸
+func startBody(head Point, dir Direction) []Point {
+	dx, dy := 0, 0
+	switch dir {
+	case Up:
+		dy = 1
+	case Down:
+		dy = -1
+	case Left:
+		dx = 1
+	case Right:
+		dx = -1
+	}
+	body := make([]Point, startLength)
+	for i := range body {
+		body[i] = Point{head.X + dx*i, head.Y + dy*i}
+	}
+	return body
+}
+
+// LoadLevels 加载全部关卡：先是内置关卡，然后是存档目录 snake-levels 下的用户关卡
+// 两组各自按文件名排序；无法解析的用户关卡会被跳过，错误合并后一起返回
+func LoadLevels() ([]*Level, error) {
+	return textfile.Load(builtinLevels, "levels", levelDir, levelExt, ParseLevel)
+}
+
+// FindLevels 按配置中的关卡名选出要玩的关卡
+// "" 表示没有关卡（空白面板），LevelCampaign 表示全部关卡，其余按关卡名查找
+func FindLevels(name string) ([]*Level, error) {
+	if name == "" {
+		return nil, nil
+	}
+	levels, err := LoadLevels()
+	if name == LevelCampaign {
+		if len(levels) == 0 {
+			return nil, err
+		}
+		return levels, nil
+	}
+	if lvl, ok := textfile.Find(levels, name, func(lvl *Level) string { return lvl.Name }); ok {
+		return []*Level{lvl}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("unknown level %q", name)
+}
@@ -0,0 +1,158 @@
+package snake
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-game/internal/store"
+)
+
+func TestParseLevel(t *testing.T) {
+	lvl, err := ParseLevel("fallback", strings.NewReader(`# comment
+name: Tiny
+target: 5
+#.....
+#..>..
+#.....
+`))
+	if err != nil {
+		t.Fatalf("ParseLevel: %v", err)
+	}
+	if lvl.Name != "Tiny" || lvl.Width != 6 || lvl.Height != 3 || lvl.Target != 5 {
+		t.Errorf("level = %+v", lvl)
+	}
+	if lvl.Start != (Point{3, 1}) || lvl.Dir != Right {
+		t.Errorf("start = %v %v, want (3,1) right", lvl.Start, lvl.Dir)
+	}
+	if len(lvl.Walls) != 3 || lvl.Walls[1] != (Point{0, 1}) {
+		t.Errorf("walls = %v", lvl.Walls)
+	}
+}
+
+func TestParseLevelErrors(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"no map", "name: x\n", "no map"},
+		{"ragged", "....\n.^.\n....\n", "row width"},
+		{"bad char", "..x..\n..^..\n.....\n", "unexpected"},
+		{"no start", ".....\n.....\n", "exactly one start"},
+		{"two starts", "..^..\n.....\n.....\n..^..\n", "exactly one start"},
+		{"body off board", "..v..\n.....\n", "blocked"},
+		{"body in wall", ".....\n..^..\n..#..\n.....\n", "blocked"},
+		{"unknown property", "speed: 3\n..^..\n.....\n.....\n", "unknown property"},
+		{"bad target", "target: x\n..^..\n.....\n.....\n", "invalid target"},
+		{"short target", "target: 3\n..^..\n.....\n.....\n", "longer than"},
+		{"huge target", "target: 99\n..^..\n.....\n.....\n", "exceeds"},
+		{"lonely portal", "1....\n..^..\n.....\n.....\n", "exactly twice"},
+		{"three portals", "1...1\n..^..\n.....\n....1\n", "exactly twice"},
+		{"body on portal", "1...1\n..^..\n..2..\n..2..\n", "blocked"},
+		{"body on hazard", ".....\n..^..\n..=..\n.....\n", "blocked"},
+		{"bad hazard period", "hazard-period: 0\n..^..\n.....\n.....\n", "invalid hazard-period"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLevel("test", strings.NewReader(tt.text))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadLevels 内置关卡全部有效，用户关卡排在后面，坏文件被跳过并报告
+func TestLoadLevels(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(store.EnvHome, home)
+	dir := filepath.Join(home, levelDir)
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "mine.txt"), []byte("target: 5\n.....\n..^..\n.....\n.....\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "broken.txt"), []byte("nothing here\n"), 0o644)
+
+	levels, err := LoadLevels()
+	if err == nil || !strings.Contains(err.Error(), "broken.txt") {
+		t.Errorf("err = %v, want it to report broken.txt", err)
+	}
+	if len(levels) < 2 || levels[0].Name != "Box" || levels[len(levels)-1].Name != "mine" {
+		t.Fatalf("levels = %v", levelNames(levels))
+	}
+
+	if got, _ := FindLevels("MINE"); len(got) != 1 || got[0].Name != "mine" {
+		t.Errorf("FindLevels(MINE) = %v", levelNames(got))
+	}
+	if got, _ := FindLevels(LevelCampaign); len(got) != len(levels) {
+		t.Errorf("campaign has %d levels, want %d", len(got), len(levels))
+	}
+	if _, err := FindLevels("nope"); err == nil {
+		t.Error("FindLevels(nope) should fail")
+	}
+}
+
+// levelNames 关卡名列表，用于错误信息
+func levelNames(levels []*Level) []string {
+	var names []string
+	for _, lvl := range levels {
+		names = append(names, lvl.Name)
+	}
+	return names
+}
+
+// testLevel 解析测试用关卡，失败时终止测试
+func testLevel(t *testing.T, text string) *Level {
+	t.Helper()
+	lvl, err := ParseLevel("test", strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ParseLevel: %v", err)
+	}
+	return lvl
+}
+
+// TestWallsBlockSnakeAndFood 障碍物会撞死蛇，食物不会生成在障碍物上
+func TestWallsBlockSnakeAndFood(t *testing.T) {
+	lvl := testLevel(t, "..#..\n..^..\n.....\n.....\n")
+	for seed := int64(1); seed <= 50; seed++ {
+		g := NewGame(WithSeed(seed), WithLevels(lvl))
+		if p := g.apple(); g.board[p.Y][p.X] != cellEmpty {
+			t.Fatalf("seed %d: food %v spawned on an occupied cell", seed, p)
+		}
+	}
+
+	g := NewGame(WithSeed(1), WithLevels(lvl))
+	setFood(g, Point{0, 3})
+	g.Step()
+	if !g.Over() {
+		t.Fatal("moving into a wall should end the game")
+	}
+	checkBoard(t, g)
+}
+
+// TestCampaignAdvances 达到目标长度后进入下一关并保留得分，完成最后一关获胜
+func TestCampaignAdvances(t *testing.T) {
+	first := testLevel(t, "name: One\ntarget: 4\n......\n..^...\n......\n......\n")
+	second := testLevel(t, "name: Two\ntarget: 4\n#.......\n#....<..\n#.......\n")
+	g := NewGame(WithSeed(1), WithLevels(first, second))
+
+	setFood(g, Point{2, 0})
+	g.Step()
+	s := g.State()
+	if s.Level != "Two" || s.Width != 8 || s.Height != 3 || s.Score != 10 {
+		t.Fatalf("after the first target: %+v", s)
+	}
+	if s.Snake[0] != (Point{5, 1}) || s.Direction != Left || len(s.Snake) != startLength {
+		t.Errorf("second level start: snake %v dir %v", s.Snake, s.Direction)
+	}
+	checkBoard(t, g)
+
+	setFood(g, Point{4, 1})
+	g.Step()
+	if s := g.State(); !s.Won || !s.GameOver || s.Score != 20 {
+		t.Errorf("after the last target: %+v", s)
+	}
+
+	g.reset()
+	if s := g.State(); s.Level != "One" || s.Score != 0 || s.GameOver {
+		t.Errorf("after reset: %+v", s)
+	}
+}
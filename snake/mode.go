@@ -0,0 +1,121 @@
+package snake
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================
+// 挑战模式 - 限时和目标长度
+// ============================================
+// 除了撞死才结束的无尽模式，单人游戏还有两种挑战：
+// - 限时（time-attack）：TimeAttackLimit 内尽量多得分，时间到即结束，按得分排名
+// - 目标长度（length）：尽快长到目标长度，达到即结束，按用时排名（撞死不计成绩）
+//
+// 计时使用游戏时间：每步按当时的移动间隔累计（与 Stats.Time 相同），不含暂停，
+// 因此同样的种子和操作总是得到同样的用时，录像回放时也完全一致
+
+// Mode 单人游戏的模式
+type Mode int
+
+const (
+	ModeEndless    Mode = iota // 无尽：撞死才结束
+	ModeTimeAttack             // 限时：TimeAttackLimit 内尽量多得分
+	ModeLength                 // 目标长度：尽快长到目标长度
+)
+
+// TimeAttackLimit 限时模式的时长
+const TimeAttackLimit = 60 * time.Second
+
+// DefaultLengthTarget 目标长度模式默认的目标长度
+const DefaultLengthTarget = 30
+
+// modeNames 模式名称（命令行参数、选项和录像文件中使用）
+var modeNames = []string{"endless", "time-attack", "length"}
+
+// String 返回模式名称
+func (m Mode) String() string {
+	if int(m) < len(modeNames) {
+		return modeNames[m]
+	}
+	return "unknown"
+}
+
+// ParseMode 根据名称查找模式，空字符串表示无尽模式
+func ParseMode(name string) (Mode, error) {
+	if name == "" {
+		return ModeEndless, nil
+	}
+	for i, n := range modeNames {
+		if strings.EqualFold(n, name) {
+			return Mode(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown mode %q (valid: %s)", name, strings.Join(modeNames, ", "))
+}
+
+// CheckTarget 检查目标长度模式的目标：必须比初始的蛇更长
+func CheckTarget(target int) error {
+	if target <= startLength {
+		return fmt.Errorf("target %d must be longer than the starting length %d", target, startLength)
+	}
+	return nil
+}
+
+// SetMode 设置单人游戏的模式，target 为目标长度模式的目标，0 表示 DefaultLengthTarget
+// 其他目标必须通过 CheckTarget 的检查，否则 panic（命令行、选项菜单和录像都会事先检查）
+// 只在开始游戏前调用；多人对战和关卡总是无尽模式
+func (g *Game) SetMode(m Mode, target int) {
+	if g.players != 1 || len(g.levels) > 0 {
+		m = ModeEndless
+	}
+	if target == 0 {
+		target = DefaultLengthTarget
+	}
+	if err := CheckTarget(target); err != nil {
+		panic(err)
+	}
+	g.mode, g.target = m, target
+}
+
+// timeLeft 限时模式剩余的游戏时间
+func (g *Game) timeLeft() time.Duration {
+	return max(TimeAttackLimit-g.elapsed, 0)
+}
+
+// checkChallenge 每步结束时检查挑战是否完成：限时模式时间到，或目标长度模式达到目标
+// 完成时游戏结束并记为获胜；蛇在这一步撞死时不算完成
+func (g *Game) checkChallenge() {
+	if g.mode == ModeEndless || g.gameOver || !g.snakes[0].alive {
+		return
+	}
+	switch g.mode {
+	case ModeTimeAttack:
+		if g.elapsed >= TimeAttackLimit {
+			g.won, g.gameOver = true, true
+		}
+	case ModeLength:
+		if len(g.snakes[0].body) >= g.target {
+			g.won, g.gameOver = true, true
+		}
+	}
+}
+
+// modeSuffix 返回高分榜名称中的模式部分，无尽模式为空
+// 例如 "-time-attack"、"-length-30"
+func (g *Game) modeSuffix() string {
+	switch g.mode {
+	case ModeTimeAttack:
+		return "-" + g.mode.String()
+	case ModeLength:
+		return fmt.Sprintf("-%s-%d", g.mode, g.target)
+	}
+	return ""
+}
+
+// formatTenths 把时长格式化为 "分:秒.十分之一秒"，例如 "0:31.2"（用于按用时排名的成绩）
+func formatTenths(d time.Duration) string {
+	tenths := int(d / (100 * time.Millisecond))
+	return fmt.Sprintf("%d:%02d.%d", tenths/600, tenths/10%60, tenths%10)
+}
@@ -0,0 +1,194 @@
+package snake
+
+import (
+	"testing"
+	"time"
+
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+	"go-game/scores"
+)
+
+func TestParseMode(t *testing.T) {
+	for name, want := range map[string]Mode{"": ModeEndless, "endless": ModeEndless, "Time-Attack": ModeTimeAttack, "length": ModeLength} {
+		if got, err := ParseMode(name); err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseMode("zen"); err == nil {
+		t.Error("ParseMode should reject unknown modes")
+	}
+}
+
+// newModeGame 恒定普通速度的单人游戏，便于计算用时
+func newModeGame(t *testing.T, wrap bool, m Mode, target int) *Game {
+	t.Helper()
+	g := NewGame(WithSeed(1), WithWrap(wrap))
+	d, err := ParseDifficulty("normal", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetDifficulty(d)
+	g.SetMode(m, target)
+	return g
+}
+
+// TestTimeAttack 限时模式按游戏时间计时，时间到时游戏结束并记为完成
+func TestTimeAttack(t *testing.T) {
+	g := newModeGame(t, true, ModeTimeAttack, 0) // 穿墙模式下一直向上走不会撞死
+	steps := int(TimeAttackLimit / (SpeedNormal * time.Millisecond))
+	for i := 1; i < steps; i++ {
+		g.Step()
+	}
+	if s := g.State(); s.GameOver || s.Elapsed != (steps-1)*SpeedNormal {
+		t.Fatalf("after %d steps: over %v elapsed %d", steps-1, s.GameOver, s.Elapsed)
+	}
+	g.Step()
+	if s := g.State(); !s.GameOver || !s.Won || s.Snakes[0].Stats.Death != DeathNone {
+		t.Errorf("time should be up: over %v won %v death %v", s.GameOver, s.Won, s.Snakes[0].Stats.Death)
+	}
+}
+
+// TestLengthTarget 目标长度模式达到目标时结束，撞死则不算完成
+func TestLengthTarget(t *testing.T) {
+	g := newModeGame(t, false, ModeLength, 5)
+	chaseFood(g, 200)
+	if s := g.State(); !s.GameOver || !s.Won || len(s.Snake) != 5 {
+		t.Errorf("over %v won %v length %d, want a win at length 5", s.GameOver, s.Won, len(s.Snake))
+	}
+
+	g = newModeGame(t, false, ModeLength, 5)
+	for !g.Over() {
+		g.Step() // 一直向上撞墙
+	}
+	if g.State().Won {
+		t.Error("crashing should not complete the challenge")
+	}
+}
+
+// TestSetMode 目标为 0 时使用默认目标，过短的目标会 panic；多人对战和关卡总是无尽模式
+func TestSetMode(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	g.SetMode(ModeLength, 0)
+	if g.mode != ModeLength || g.target != DefaultLengthTarget {
+		t.Errorf("mode %v target %d, want length %d", g.mode, g.target, DefaultLengthTarget)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("SetMode with target 2 should panic")
+			}
+		}()
+		g.SetMode(ModeLength, 2)
+	}()
+	v := NewGame(WithSeed(1), WithPlayers(2))
+	v.SetMode(ModeTimeAttack, 0)
+	if v.mode != ModeEndless {
+		t.Errorf("versus mode = %v, want endless", v.mode)
+	}
+}
+
+// TestCheckTarget 目标必须比初始的蛇更长，录像或配置中过短的目标在创建游戏时报错
+func TestCheckTarget(t *testing.T) {
+	for _, target := range []int{-1, 0, startLength} {
+		if CheckTarget(target) == nil {
+			t.Errorf("CheckTarget(%d) = nil, want an error", target)
+		}
+	}
+	if err := CheckTarget(startLength + 1); err != nil {
+		t.Errorf("CheckTarget(%d) = %v", startLength+1, err)
+	}
+	if _, err := (Config{Mode: "length", Target: 2}).newGame(1); err == nil {
+		t.Error("newGame with target 2 should fail")
+	}
+}
+
+func TestTableNameByMode(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{Mode: "time-attack"}, "snake-time-attack"},
+		{Config{Mode: "length"}, "snake-length-30"},
+		{Config{Mode: "length", Target: 20, Wrap: true, Difficulty: "hard"}, "snake-wrap-length-20-hard"},
+		{Config{Mode: "time-attack", Level: "Pillars"}, "snake-time-attack"}, // 挑战模式总是空白面板
+	}
+	for _, tt := range tests {
+		g, err := tt.cfg.newGame(1)
+		if err != nil {
+			t.Fatalf("newGame(%+v): %v", tt.cfg, err)
+		}
+		if got := g.tableName(); got != tt.want {
+			t.Errorf("tableName(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+	if _, err := (Config{Mode: "zen"}).newGame(1); err == nil {
+		t.Error("newGame should reject unknown modes")
+	}
+}
+
+// TestSubmitLengthRecord 目标长度模式按用时排名，只记录完成的对局
+func TestSubmitLengthRecord(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+
+	crashed := newModeGame(t, false, ModeLength, 5)
+	for !crashed.Over() {
+		crashed.Step()
+	}
+	if crashed.submitScore(1) {
+		t.Error("a crashed length race should not be recorded")
+	}
+
+	g := newModeGame(t, false, ModeLength, 5)
+	chaseFood(g, 200)
+	if !g.submitScore(1) {
+		t.Error("the first finished race should be a record")
+	}
+	table, err := scores.Get(g.tableName())
+	if err != nil || table == nil {
+		t.Fatalf("table %q: %v", g.tableName(), err)
+	}
+	best, _ := table.Best()
+	if table.Order != scores.LowerIsBetter || best.Value != g.elapsed.Milliseconds() || g.bestScore() != int(best.Value) {
+		t.Errorf("table order %v best %+v, want the race time %v", table.Order, best, g.elapsed)
+	}
+}
+
+func TestRenderModeGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T) *Game
+		best   int
+		record bool
+	}{
+		{
+			name: "timeattack",
+			best: 120,
+			setup: func(t *testing.T) *Game {
+				g := newModeGame(t, false, ModeTimeAttack, 0)
+				chaseFood(g, 20)
+				return g
+			},
+		},
+		{
+			name:   "lengthwon",
+			best:   5400,
+			record: true,
+			setup: func(t *testing.T) *Game {
+				g := newModeGame(t, false, ModeLength, 5)
+				chaseFood(g, 200)
+				return g
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+			r := NewRenderer(screen, tt.setup(t), input.DefaultSnake())
+			r.best, r.record = tt.best, tt.record
+			r.Render()
+			screentest.AssertScreen(t, "render_"+tt.name, screen)
+		})
+	}
+}
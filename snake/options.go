@@ -0,0 +1,363 @@
+package snake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/store"
+)
+
+// ============================================
+// 选项持久化
+// ============================================
+
+// optionsFile 贪吃蛇选项在存档目录中的文件名
+const optionsFile = "snake-options.json"
+
+// LoadConfig 读取上次在选项菜单中保存的配置，不存在时返回默认配置
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := store.Load(optionsFile, &cfg); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// save 保存选项（种子、录像路径等一次性参数不保存）
+func (c Config) save() error {
+	return store.Save(optionsFile, c)
+}
+
+// ============================================
+// OptionsMenu - 贪吃蛇选项菜单
+// ============================================
+// 操作方式：
+// - ↑↓: 选择
+// - ←→: 修改选项
+// - Enter: 开始游戏 / 返回
+// - Esc: 返回主菜单
+// （方向和返回也可以使用映射表中的移动和返回按键）
+
+type OptionsMenu struct {
+	screen   tcell.Screen
+	keys     *input.Keymap // 菜单导航使用的按键
+	config   *Config
+	items    []optionItem
+	selected int
+	message  string // 无法开始游戏的原因（见 Config.Validate），显示在操作说明下方
+}
+
+// optionItem 菜单中的一项
+// values 为空表示这是一个动作项（开始游戏、返回）
+type optionItem struct {
+	label  string
+	values []string
+	get    func() int
+	set    func(i int)
+}
+
+// 动作项的标签
+const (
+	itemStart = "START GAME"
+	itemBack  = "BACK"
+)
+
+// NewOptionsMenu 创建选项菜单，修改直接写入 config
+func NewOptionsMenu(screen tcell.Screen, keys *input.Keymap, config *Config) *OptionsMenu {
+	m := &OptionsMenu{screen: screen, keys: keys, config: config}
+
+	// 关卡选项：空白面板、战役，然后是每个关卡（无法解析的用户关卡不出现）
+	levels, _ := LoadLevels()
+
+	// 面板尺寸选项：各个预设，然后是按终端大小自动计算
+	sizeNames := []string{""}
+	sizeLabels := []string{fmt.Sprintf("Classic %dx%d", BoardWidth, BoardHeight)}
+	for _, s := range BoardSizes[1:] {
+		sizeNames = append(sizeNames, s.Name)
+		sizeLabels = append(sizeLabels, fmt.Sprintf("%s %dx%d", strings.ToUpper(s.Name[:1])+s.Name[1:], s.Width, s.Height))
+	}
+	sizeNames = append(sizeNames, SizeAuto)
+	sizeLabels = append(sizeLabels, "Fit terminal")
+
+	// 难度选项：各个难度预设
+	difficultyLabels := make([]string, len(Difficulties))
+	for i, d := range Difficulties {
+		difficultyLabels[i] = strings.ToUpper(d.Name[:1]) + d.Name[1:]
+	}
+
+	// 自动演示选项：关闭，然后是每个已注册的控制器
+	autopilotNames := append([]string{""}, ControllerNames()...)
+	autopilotLabels := []string{"Off"}
+	for _, name := range ControllerNames() {
+		autopilotLabels = append(autopilotLabels, strings.ToUpper(name[:1])+name[1:])
+	}
+	// 大逃杀选项：关闭，然后是几档缩圈间隔（秒）
+	shrinkSeconds := []int{0, 5, 10, 20, 30}
+	shrinkLabels := []string{"Off"}
+	for _, n := range shrinkSeconds[1:] {
+		shrinkLabels = append(shrinkLabels, fmt.Sprintf("Shrink %ds", n))
+	}
+	// 挑战选项：无尽模式、限时模式，然后是几档目标长度
+	challenges := []struct {
+		mode   Mode
+		target int
+	}{{ModeEndless, 0}, {ModeTimeAttack, 0}, {ModeLength, 20}, {ModeLength, DefaultLengthTarget}, {ModeLength, 50}}
+	challengeLabels := []string{"Endless", fmt.Sprintf("Time attack %ds", int(TimeAttackLimit/time.Second))}
+	for _, c := range challenges[2:] {
+		challengeLabels = append(challengeLabels, fmt.Sprintf("Length %d", c.target))
+	}
+	levelNames := []string{"", LevelCampaign}
+	levelLabels := []string{"Open", "Campaign"}
+	for _, lvl := range levels {
+		levelNames = append(levelNames, lvl.Name)
+		levelLabels = append(levelLabels, lvl.Name)
+	}
+
+	m.items = []optionItem{
+		{label: itemStart},
+		{
+			label:  "MODE",
+			values: []string{"Classic", "Wrap"},
+			get: func() int {
+				if config.Wrap {
+					return 1
+				}
+				return 0
+			},
+			set: func(i int) { config.Wrap = i == 1 },
+		},
+		{
+			label:  "SIZE",
+			values: sizeLabels,
+			get: func() int {
+				for i, name := range sizeNames {
+					if strings.EqualFold(name, config.Size) {
+						return i
+					}
+				}
+				return 0
+			},
+			set: func(i int) { config.Size = sizeNames[i] },
+		},
+		{
+			label:  "DIFFICULTY",
+			values: difficultyLabels,
+			get: func() int {
+				for i, d := range Difficulties {
+					if strings.EqualFold(d.Name, config.Difficulty) {
+						return i
+					}
+				}
+				return 1 // 未设置时为普通难度
+			},
+			set: func(i int) { config.Difficulty = Difficulties[i].Name },
+		},
+		{
+			label:  "SPEED",
+			values: []string{"Ramp up", "Constant"},
+			get: func() int {
+				if config.Constant {
+					return 1
+				}
+				return 0
+			},
+			set: func(i int) { config.Constant = i == 1 },
+		},
+		{
+			label:  "CHALLENGE",
+			values: challengeLabels,
+			get: func() int {
+				mode, _ := ParseMode(config.Mode)
+				target := config.Target
+				if target == 0 {
+					target = DefaultLengthTarget
+				}
+				for i, c := range challenges {
+					if c.mode == mode && (mode != ModeLength || c.target == target) {
+						return i
+					}
+				}
+				return 0
+			},
+			set: func(i int) {
+				config.Mode, config.Target = "", 0
+				if c := challenges[i]; c.mode != ModeEndless {
+					config.Mode = c.mode.String()
+					if c.mode == ModeLength {
+						config.Target = c.target
+					}
+				}
+			},
+		},
+		{
+			label:  "PLAYERS",
+			values: []string{"1", "2 (VS)"},
+			get: func() int {
+				if config.Players > 1 {
+					return 1
+				}
+				return 0
+			},
+			set: func(i int) { config.Players = i + 1 },
+		},
+		{
+			label:  "OPPONENTS",
+			values: []string{"0", "1", "2", "3"},
+			get:    func() int { return min(max(config.Opponents, 0), MaxSnakes-1) },
+			set:    func(i int) { config.Opponents = i },
+		},
+		{
+			label:  "ROYALE",
+			values: shrinkLabels,
+			get: func() int {
+				for i, n := range shrinkSeconds {
+					if n == config.Shrink {
+						return i
+					}
+				}
+				return 0
+			},
+			set: func(i int) { config.Shrink = shrinkSeconds[i] },
+		},
+		{
+			label:  "AUTOPILOT",
+			values: autopilotLabels,
+			get: func() int {
+				for i, name := range autopilotNames {
+					if name == config.Autopilot {
+						return i
+					}
+				}
+				return 0
+			},
+			set: func(i int) { config.Autopilot = autopilotNames[i] },
+		},
+		{
+			label:  "LEVEL",
+			values: levelLabels,
+			get: func() int {
+				for i, name := range levelNames {
+					if strings.EqualFold(name, config.Level) {
+						return i
+					}
+				}
+				return 0
+			},
+			set: func(i int) { config.Level = levelNames[i] },
+		},
+		{label: itemBack},
+	}
+	return m
+}
+
+// Render 绘制选项菜单
+func (m *OptionsMenu) Render() {
+	m.screen.Clear()
+	m.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true)
+	normalStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorLime).Bold(true)
+	hintStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+
+	drawText(m.screen, 10, 3, "SNAKE OPTIONS", titleStyle)
+
+	for i, item := range m.items {
+		style, prefix := normalStyle, "  "
+		if i == m.selected {
+			style, prefix = selectedStyle, "► "
+		}
+		text := prefix + item.label
+		if len(item.values) > 0 {
+			text = fmt.Sprintf("%s%-10s < %s >", prefix, item.label, item.values[item.get()])
+		}
+		drawText(m.screen, 8, 6+i, text, style)
+	}
+
+	hints := []string{
+		"↑↓ : Select",
+		"←→ : Change",
+		"Enter : Confirm",
+		"Esc : Back",
+	}
+	for i, hint := range hints {
+		drawText(m.screen, 8, 7+len(m.items)+i, hint, hintStyle)
+	}
+	if m.message != "" {
+		drawText(m.screen, 8, 7+len(m.items)+len(hints), m.message, tcell.StyleDefault.Foreground(tcell.ColorRed))
+	}
+
+	m.screen.Show()
+}
+
+// Run 运行选项菜单
+// 返回 true 表示开始游戏，false 表示返回主菜单；两种情况都会保存选项，保存失败时返回错误
+// 选项的组合无效时（见 Config.Validate）不会开始游戏，而是在菜单中显示原因
+func (m *OptionsMenu) Run() (bool, error) {
+	m.Render()
+
+	for {
+		switch ev := m.screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			item := m.items[m.selected]
+			m.message = ""
+			switch m.keys.Navigate(ev) {
+			case input.NavBack:
+				return false, m.config.save()
+			case input.NavUp:
+				if m.selected > 0 {
+					m.selected--
+				}
+			case input.NavDown:
+				if m.selected < len(m.items)-1 {
+					m.selected++
+				}
+			case input.NavLeft:
+				m.cycle(item, -1)
+			case input.NavRight:
+				m.cycle(item, 1)
+			case input.NavSelect:
+				switch item.label {
+				case itemStart:
+					if err := m.config.Validate(); err != nil {
+						m.message = err.Error()
+						break
+					}
+					return true, m.config.save()
+				case itemBack:
+					return false, m.config.save()
+				default:
+					m.cycle(item, 1)
+				}
+			}
+			m.Render()
+
+		case *tcell.EventResize:
+			m.Render()
+
+		case nil:
+			return false, nil
+		}
+	}
+}
+
+// cycle 循环切换选项的值
+func (m *OptionsMenu) cycle(item optionItem, delta int) {
+	if len(item.values) == 0 {
+		return
+	}
+	n := len(item.values)
+	item.set((item.get() + delta + n) % n)
+}
+
+// drawText 在指定位置绘制一行文字
+func drawText(screen tcell.Screen, x, y int, text string, style tcell.Style) {
+	for i, ch := range []rune(text) {
+		screen.SetContent(x+i, y, ch, nil, style)
+	}
+}
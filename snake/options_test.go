@@ -0,0 +1,204 @@
+package snake
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+)
+
+func TestOptionsMenuGolden(t *testing.T) {
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	cfg := Config{Wrap: true}
+	NewOptionsMenu(screen, input.DefaultSnake(), &cfg).Render()
+	screentest.AssertScreen(t, "options", screen)
+}
+
+// keyPress 测试脚本中的一步：先选中标签为 item 的菜单项，再按下 key
+type keyPress struct {
+	item string
+	key  tcell.Key
+}
+
+// script 把按菜单项描述的操作转换为按键序列（从第一项开始，用 ↑↓ 移动）
+func script(m *OptionsMenu, presses ...keyPress) []*tcell.EventKey {
+	var keys []*tcell.EventKey
+	at := 0
+	for _, p := range presses {
+		if p.item != "" {
+			target := -1
+			for i, item := range m.items {
+				if item.label == p.item {
+					target = i
+				}
+			}
+			for ; at < target; at++ {
+				keys = append(keys, screentest.Key(tcell.KeyDown))
+			}
+			for ; at > target; at-- {
+				keys = append(keys, screentest.Key(tcell.KeyUp))
+			}
+		}
+		keys = append(keys, screentest.Key(p.key))
+	}
+	return keys
+}
+
+// TestOptionsMenuRun 修改选项后开始游戏或返回，选项应写入配置并保存到磁盘
+func TestOptionsMenuRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		presses   []keyPress
+		wantStart bool
+		want      Config
+	}{
+		{"start classic", []keyPress{{itemStart, tcell.KeyEnter}}, true, Config{}},
+		{"toggle and start", []keyPress{
+			{"MODE", tcell.KeyRight},
+			{itemStart, tcell.KeyEnter},
+		}, true, Config{Wrap: true}},
+		{"toggle twice", []keyPress{
+			{"MODE", tcell.KeyLeft},
+			{"", tcell.KeyEnter},
+			{"", tcell.KeyEscape},
+		}, false, Config{}},
+		{"toggle and back", []keyPress{
+			{"MODE", tcell.KeyEnter},
+			{itemBack, tcell.KeyEnter},
+		}, false, Config{Wrap: true}},
+		{"pick campaign", []keyPress{
+			{"LEVEL", tcell.KeyRight},
+			{"", tcell.KeyEscape},
+		}, false, Config{Level: LevelCampaign}},
+		{"pick last level", []keyPress{
+			{"LEVEL", tcell.KeyLeft},
+			{"", tcell.KeyEscape},
+		}, false, Config{Level: "Warp"}},
+		{"two players", []keyPress{
+			{"PLAYERS", tcell.KeyRight},
+			{itemStart, tcell.KeyEnter},
+		}, true, Config{Players: 2}},
+		{"hard constant", []keyPress{
+			{"DIFFICULTY", tcell.KeyRight},
+			{"SPEED", tcell.KeyEnter},
+			{itemStart, tcell.KeyEnter},
+		}, true, Config{Difficulty: "hard", Constant: true}},
+		{"easy", []keyPress{
+			{"DIFFICULTY", tcell.KeyLeft},
+			{"", tcell.KeyEscape},
+		}, false, Config{Difficulty: "easy"}},
+		{"length race", []keyPress{
+			{"CHALLENGE", tcell.KeyRight},
+			{"", tcell.KeyRight},
+			{"", tcell.KeyEscape},
+		}, false, Config{Mode: "length", Target: 20}},
+		{"time attack", []keyPress{
+			{"CHALLENGE", tcell.KeyEnter},
+			{itemStart, tcell.KeyEnter},
+		}, true, Config{Mode: "time-attack"}},
+		{"royale", []keyPress{
+			{"OPPONENTS", tcell.KeyRight},
+			{"ROYALE", tcell.KeyRight},
+			{"", tcell.KeyRight},
+			{itemStart, tcell.KeyEnter},
+		}, true, Config{Opponents: 1, Shrink: 10}},
+		// 命令行拒绝的组合不能开始游戏，只能修改选项或返回
+		{"challenge with opponents", []keyPress{
+			{"CHALLENGE", tcell.KeyRight},
+			{"OPPONENTS", tcell.KeyRight},
+			{itemStart, tcell.KeyEnter},
+			{"", tcell.KeyEscape},
+		}, false, Config{Mode: "time-attack", Opponents: 1}},
+		{"level with versus", []keyPress{
+			{"PLAYERS", tcell.KeyRight},
+			{"LEVEL", tcell.KeyRight},
+			{itemStart, tcell.KeyEnter},
+			{itemBack, tcell.KeyEnter},
+		}, false, Config{Players: 2, Level: LevelCampaign}},
+		{"royale alone", []keyPress{
+			{"ROYALE", tcell.KeyRight},
+			{itemStart, tcell.KeyEnter},
+			{"ROYALE", tcell.KeyLeft},
+			{itemStart, tcell.KeyEnter},
+		}, true, Config{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(store.EnvHome, t.TempDir())
+			screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+
+			var cfg Config
+			m := NewOptionsMenu(screen, input.DefaultSnake(), &cfg)
+			screentest.Type(screen, time.Millisecond, script(m, tt.presses...)...)
+			got, err := m.Run()
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if got != tt.wantStart {
+				t.Errorf("Run() = %v, want %v", got, tt.wantStart)
+			}
+			if cfg != tt.want {
+				t.Errorf("config = %+v, want %+v", cfg, tt.want)
+			}
+
+			saved, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if saved != cfg {
+				t.Errorf("saved config = %+v, want %+v", saved, cfg)
+			}
+		})
+	}
+}
+
+// TestOptionsMenuInvalid 无效的组合在菜单中显示原因，修改任意选项后提示消失
+func TestOptionsMenuInvalid(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	cfg := Config{Shrink: 10}
+	m := NewOptionsMenu(screen, input.DefaultSnake(), &cfg)
+
+	result := make(chan bool)
+	go func() {
+		start, _ := m.Run()
+		result <- start
+	}()
+	<-screentest.Type(screen, time.Millisecond, screentest.Key(tcell.KeyEnter))
+	time.Sleep(50 * time.Millisecond)
+	if !screentest.Contains(screen, "battle royale needs at least 2 snakes") {
+		t.Error("the reason should be shown after a refused start")
+	}
+	<-screentest.Type(screen, time.Millisecond, screentest.Key(tcell.KeyDown))
+	time.Sleep(50 * time.Millisecond)
+	if screentest.Contains(screen, "battle royale") {
+		t.Error("the reason should be cleared by the next key")
+	}
+	screentest.Type(screen, time.Millisecond, screentest.Key(tcell.KeyEscape))
+	if <-result {
+		t.Error("Run() = true, want false")
+	}
+}
+
+// TestOptionsMenuSaveError 保存选项失败时 Run 返回错误
+func TestOptionsMenuSaveError(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(store.EnvHome, dir)
+	// 临时文件的位置被目录占用，写入失败
+	if err := os.Mkdir(filepath.Join(dir, optionsFile+".tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	var cfg Config
+	m := NewOptionsMenu(screen, input.DefaultSnake(), &cfg)
+	screentest.Type(screen, time.Millisecond, screentest.Key(tcell.KeyEnter))
+	if start, err := m.Run(); err == nil {
+		t.Errorf("Run() = %v, nil, want a save error", start)
+	}
+}
@@ -0,0 +1,113 @@
+package snake
+
+// ============================================
+// player - 面板上的一条蛇
+// ============================================
+// 单人模式只有一条蛇；双人模式下每条蛇有自己的方向、转向队列、长度和得分
+
+type player struct {
+	// 蛇身体：从头部(body[0])到尾部排列的坐标列表
+	body []Point
+
+	// direction: 蛇当前的实际移动方向
+	// turns: 尚未执行的转向输入队列，每次移动取出一个
+	//        （入队时与前一个方向比较，防止快速反向导致自杀）
+	direction Direction
+	turns     []Direction
+
+	length int  // 目标长度（随吃到的食物变化）
+	score  int  // 当前得分
+	alive  bool // 是否还活着（撞死的蛇留在原地成为障碍物，直到本局结束）
+	left   bool // 已经离开竞技场，下标可以分配给新加入的蛇
+
+	stats Stats // 本局的统计（见 stats.go）
+}
+
+// newPlayer 创建一条初始长度的蛇，蛇头在 head，朝 dir 方向移动
+func newPlayer(head Point, dir Direction) *player {
+	return &player{
+		body:      startBody(head, dir),
+		direction: dir,
+		length:    startLength,
+		alive:     true,
+		stats:     Stats{MaxLength: startLength, Killer: -1},
+	}
+}
+
+// head 返回蛇头位置
+func (p *player) head() Point {
+	return p.body[0]
+}
+
+// tail 返回蛇尾位置
+func (p *player) tail() Point {
+	return p.body[len(p.body)-1]
+}
+
+// queueTurn 将转向输入加入队列
+//
+// 规则：
+// 1. 与队列中最后一个方向（队列为空时为当前方向）相同或相反的输入被忽略
+// 2. 队列已满时丢弃新的输入
+func (p *player) queueTurn(d Direction) {
+	last := p.direction
+	if n := len(p.turns); n > 0 {
+		last = p.turns[n-1]
+	}
+	if d == last || d == opposite(last) || len(p.turns) >= MaxQueuedTurns {
+		return
+	}
+	p.turns = append(p.turns, d)
+}
+
+// nextTurn 从转向队列取出一个方向作为实际方向（队列为空则保持原方向）
+// 队列中的方向总是与前一个不同，每取出一个记一次转向
+func (p *player) nextTurn() {
+	if len(p.turns) > 0 {
+		p.direction = p.turns[0]
+		p.turns = p.turns[1:]
+		p.stats.Turns++
+	}
+}
+
+// ============================================
+// 对战结果
+// ============================================
+
+// Round 一局多人对战的结果
+type Round struct {
+	Winner int   // 获胜玩家下标，-1 表示平局
+	Scores []int // 各玩家本局得分
+}
+
+// endRound 结束多人对战的一局并记录结果
+// 只剩一条蛇时它获胜；全部撞死为平局；面板被填满时存活者中得分高者获胜
+func (g *Game) endRound() {
+	g.gameOver = true
+	r := Round{Winner: -1}
+	best := -1
+	for i, p := range g.snakes {
+		r.Scores = append(r.Scores, p.score)
+		if !p.alive {
+			continue
+		}
+		switch {
+		case p.score > best:
+			r.Winner, best = i, p.score
+		case p.score == best:
+			r.Winner = -1
+		}
+	}
+	g.rounds = append(g.rounds, r)
+}
+
+// wins 返回每个玩家累计获胜的局数
+func (g *Game) wins() []int {
+	wins := make([]int, len(g.snakes))
+	for _, r := range g.rounds {
+		if r.Winner >= 0 {
+			wins[r.Winner]++
+		}
+	}
+	return wins
+}
@@ -0,0 +1,176 @@
+package snake
+
+import (
+	"testing"
+
+	"go-game/input"
+)
+
+// versusGame 返回两条蛇的对局，食物放在角落里不会被吃到
+func versusGame() *Game {
+	g := NewGame(WithSeed(1), WithPlayers(2))
+	setFood(g, Point{0, 0})
+	return g
+}
+
+// place 把第 i 条蛇摆到指定位置并重建面板
+func place(g *Game, i int, dir Direction, body ...Point) {
+	p := g.snakes[i]
+	p.body, p.direction, p.length, p.turns = body, dir, len(body), nil
+	g.syncBoard()
+}
+
+func TestVersusStart(t *testing.T) {
+	s := NewGame(WithSeed(1), WithPlayers(2)).State()
+	if len(s.Snakes) != 2 || s.Snakes[0].Body[0] == s.Snakes[1].Body[0] {
+		t.Fatalf("snakes = %+v", s.Snakes)
+	}
+	for i, sn := range s.Snakes {
+		if !sn.Alive || len(sn.Body) != startLength {
+			t.Errorf("snake %d = %+v", i, sn)
+		}
+	}
+}
+
+// TestVersusHeadOn 迎头相撞双方都死，本局平局
+func TestVersusHeadOn(t *testing.T) {
+	g := versusGame()
+	place(g, 0, Right, Point{5, 5}, Point{4, 5}, Point{3, 5})
+	place(g, 1, Left, Point{7, 5}, Point{8, 5}, Point{9, 5})
+	g.Step()
+
+	s := g.State()
+	if !s.GameOver || s.Snakes[0].Alive || s.Snakes[1].Alive {
+		t.Fatalf("after head-on: %+v", s.Snakes)
+	}
+	if len(s.Rounds) != 1 || s.Rounds[0].Winner != -1 {
+		t.Errorf("rounds = %+v, want one draw", s.Rounds)
+	}
+}
+
+// TestVersusBodyCollision 撞上对方身体的蛇输掉本局，撞死的蛇留在原地
+func TestVersusBodyCollision(t *testing.T) {
+	g := versusGame()
+	place(g, 0, Up, Point{5, 6}, Point{5, 7}, Point{5, 8})
+	place(g, 1, Right, Point{6, 5}, Point{5, 5}, Point{4, 5})
+	g.snakes[1].score = 30
+	g.Step()
+
+	s := g.State()
+	if !s.GameOver || s.Snakes[0].Alive || !s.Snakes[1].Alive {
+		t.Fatalf("after collision: %+v", s.Snakes)
+	}
+	if s.Snakes[0].Body[0] != (Point{5, 6}) {
+		t.Errorf("crashed snake moved to %v", s.Snakes[0].Body[0])
+	}
+	if len(s.Rounds) != 1 || s.Rounds[0].Winner != 1 || s.Rounds[0].Scores[1] != 30 {
+		t.Errorf("rounds = %+v, want P2 to win", s.Rounds)
+	}
+	checkBoard(t, g)
+}
+
+// TestVersusFollowTail 蛇头进入另一条蛇刚离开的蛇尾格子不算碰撞
+func TestVersusFollowTail(t *testing.T) {
+	g := versusGame()
+	place(g, 0, Right, Point{7, 5}, Point{6, 5}, Point{5, 5})
+	place(g, 1, Up, Point{4, 4}, Point{4, 5}, Point{4, 6})
+	g.TurnPlayer(1, Right)
+	g.Step(Up)
+
+	s := g.State()
+	if s.GameOver || !s.Snakes[0].Alive || !s.Snakes[1].Alive {
+		t.Fatalf("no one should crash: %+v", s.Snakes)
+	}
+
+	// 跟着一条刚撞死的蛇的蛇尾走：它的蛇尾不会移开，所以会撞上
+	g = versusGame()
+	place(g, 0, Up, Point{5, 0}, Point{5, 1}, Point{5, 2})
+	place(g, 1, Up, Point{5, 3}, Point{5, 4}, Point{5, 5})
+	g.Step()
+	if s := g.State(); s.Snakes[1].Alive || s.Rounds[0].Winner != -1 {
+		t.Errorf("following a crashing snake's tail: %+v %+v", s.Snakes, s.Rounds)
+	}
+	checkBoard(t, g)
+}
+
+// TestVersusRounds 重新开始后进入下一局，记分板保留
+func TestVersusRounds(t *testing.T) {
+	g := versusGame()
+	for round := 1; round <= 3; round++ {
+		for !g.Over() {
+			// 二号玩家一直向右撞墙，一号玩家原地兜圈
+			g.TurnPlayer(1, Right)
+			g.Step(Left, Down, Right)
+		}
+		if len(g.rounds) != round {
+			t.Fatalf("round %d: rounds = %+v", round, g.rounds)
+		}
+		g.reset()
+	}
+	if wins := g.wins(); wins[0] != 3 || wins[1] != 0 {
+		t.Errorf("wins = %v, want [3 0]", wins)
+	}
+}
+
+// TestApplyP2Actions 录像中的二号玩家动作只转动二号玩家的蛇
+func TestApplyP2Actions(t *testing.T) {
+	g := versusGame()
+	apply(g, input.P2MoveLeft.String())
+	apply(g, input.MoveRight.String())
+	if p1, p2 := g.snakes[0].turns, g.snakes[1].turns; len(p1) != 1 || p1[0] != Right || len(p2) != 1 || p2[0] != Left {
+		t.Errorf("turns: p1 %v p2 %v", p1, p2)
+	}
+}
+
+// TestTurnActions 转向动作名可以解析回蛇的下标和方向，二号玩家的动作名与 input 包一致
+func TestTurnActions(t *testing.T) {
+	for i := 0; i < MaxSnakes; i++ {
+		for d := Up; d <= Right; d++ {
+			n, got, ok := parseTurn(turnAction(i, d))
+			if !ok || n != i || got != d {
+				t.Errorf("parseTurn(%q) = %d, %v, %v", turnAction(i, d), n, got, ok)
+			}
+		}
+	}
+	if turnAction(1, Left) != input.P2MoveLeft.String() {
+		t.Errorf("turnAction(1, Left) = %q, want %q", turnAction(1, Left), input.P2MoveLeft)
+	}
+	for _, bad := range []string{"Tick", "P0MoveUp", "PMoveUp", "P2Jump", "Restart"} {
+		if _, _, ok := parseTurn(bad); ok {
+			t.Errorf("parseTurn(%q) accepted", bad)
+		}
+	}
+}
+
+// stubController 总是返回同一个方向
+type stubController Direction
+
+func (c stubController) Next(State, int) Direction { return Direction(c) }
+
+// TestPilots 自动演示控制一号玩家，玩家之后的蛇都是电脑对手
+func TestPilots(t *testing.T) {
+	for _, name := range []string{"stub", OpponentController} {
+		if _, ok := controllers[name]; ok {
+			continue
+		}
+		RegisterController(name, func() Controller { return stubController(Left) })
+		t.Cleanup(func() { delete(controllers, name) })
+	}
+
+	pilots, err := Config{Players: 2, Opponents: 3, Autopilot: "stub"}.pilots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pilots) != MaxSnakes {
+		t.Fatalf("len(pilots) = %d, want %d", len(pilots), MaxSnakes)
+	}
+	for i, want := range []bool{true, false, true, true} {
+		if got := pilots[i] != nil; got != want {
+			t.Errorf("pilots[%d] set = %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err := (Config{Autopilot: "nope"}).pilots(); err == nil {
+		t.Error("unknown autopilot accepted")
+	}
+}
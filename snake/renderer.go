@@ -2,8 +2,10 @@ package snake
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"go-game/input"
 )
 
 // ============================================
@@ -12,15 +14,81 @@ import (
 // 负责将游戏状态绘制到终端屏幕
 
 type Renderer struct {
-	screen tcell.Screen // tcell 屏幕对象
-	game   *Game        // 要渲染的游戏实例
+	screen   tcell.Screen  // tcell 屏幕对象
+	game     *Game         // 要渲染的游戏实例
+	keys     *input.Keymap // 当前按键映射（用于生成操作说明）
+	replay   bool          // 是否为录像回放
+	finished bool          // 录像是否已播放完毕
+	best     int           // 当前模式的最好成绩：最高分，目标长度模式为最短用时（毫秒），0 表示没有记录
+	record   bool          // 刚结束的一局刷新了最好成绩
+	cpu      []bool        // 各条蛇是否由电脑控制，nil 表示都由玩家控制
+	heatmap  bool          // 游戏结束后是否在面板上显示热力图（代替蛇和食物）
+	daily    string        // 每日挑战的日期，空表示普通对局（此时 best 为当天计分的一局的得分）
+	practice bool          // 每日挑战当天已经有成绩，之后的对局都是练习
+
+	// 画面左上角的偏移，使面板和信息面板在终端中居中（每次绘制时按终端大小计算）
+	offsetX, offsetY int
+}
+
+// 画面布局
+const (
+	layoutMarginX = 8  // 面板左侧的空白和边框，以及面板与信息面板之间的间隔（列）
+	layoutMarginY = 3  // 面板上方的空白和上下边框（行）
+	panelWidth    = 24 // 右侧信息面板的宽度（列）
+	panelHeight   = 22 // 信息面板连同操作说明最多占用的行数
+)
+
+// foodLook 食物的图案和颜色
+type foodLook struct {
+	glyph rune
+	color tcell.Color
+}
+
+// foodLooks 各种食物的外观，下标为 FoodKind
+var foodLooks = [...]foodLook{
+	Apple:    {'★', tcell.ColorRed},
+	Bonus:    {'♦', tcell.ColorFuchsia},
+	Golden:   {'★', tcell.ColorGold},
+	Shrink:   {'○', tcell.ColorAqua},
+	SpeedUp:  {'»', tcell.ColorOrange},
+	SlowDown: {'«', tcell.ColorBlue},
+}
+
+// portalColors 各对传送门的颜色（超过颜色数时循环使用）
+var portalColors = []tcell.Color{tcell.ColorDodgerBlue, tcell.ColorDarkOrange, tcell.ColorSpringGreen, tcell.ColorHotPink}
+
+// playerColors 各玩家蛇头和蛇身的颜色
+var playerColors = []struct {
+	head, body tcell.Color
+}{
+	{tcell.ColorLime, tcell.ColorGreen},
+	{tcell.ColorAqua, tcell.ColorTeal},
+	{tcell.ColorFuchsia, tcell.ColorPurple},
+	{tcell.ColorYellow, tcell.ColorOlive},
+}
+
+// FoodLook 返回食物的图案和样式
+// 每种食物有自己的图案和颜色，快要消失的食物在原色和暗灰色之间闪烁
+func FoodLook(f Food) (rune, tcell.Style) {
+	look := foodLooks[f.Kind]
+	if f.TTL > 0 && f.TTL <= blinkTicks && f.TTL%2 == 1 {
+		return look.glyph, tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	}
+	return look.glyph, tcell.StyleDefault.Foreground(look.color)
+}
+
+// PlayerColor 返回第 n 条蛇蛇头和蛇身的颜色（超过颜色数时循环使用）
+func PlayerColor(n int) (head, body tcell.Color) {
+	c := playerColors[n%len(playerColors)]
+	return c.head, c.body
 }
 
 // NewRenderer 创建渲染器实例
-func NewRenderer(screen tcell.Screen, game *Game) *Renderer {
+func NewRenderer(screen tcell.Screen, game *Game, keys *input.Keymap) *Renderer {
 	return &Renderer{
 		screen: screen,
 		game:   game,
+		keys:   keys,
 	}
 }
 
@@ -29,81 +97,237 @@ func NewRenderer(screen tcell.Screen, game *Game) *Renderer {
 // 绘制顺序（从后到前）：
 // 1. 清屏并设置背景色
 // 2. 绘制游戏区域边框
-// 3. 绘制蛇
-// 4. 绘制食物
+// 3. 绘制障碍物、传送门和蛇
+// 4. 绘制食物和巡逻障碍（巡逻障碍可以从食物上经过）
 // 5. 绘制右侧信息面板
 // 6. 绘制状态提示（暂停/游戏结束）
+//
+// 显示热力图时，热力图覆盖面板上除障碍物以外的所有格子，信息面板中的操作说明换成图例
 func (r *Renderer) Render() {
+	// 面板尺寸随关卡和设置变化
+	width, height := r.game.width, r.game.height
+
 	// ---------- 1. 清屏 ----------
 	r.screen.Clear()
 	r.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
 
+	// 终端放不下整个画面时只显示提示（Run 会同时暂停游戏）
+	if r.TooSmall() {
+		r.drawTooSmall()
+		r.screen.Show()
+		return
+	}
+	cols, rows := r.screen.Size()
+	layoutW, layoutH := layoutSize(width, height)
+	r.offsetX, r.offsetY = (cols-layoutW)/2, (rows-layoutH)/2
+
 	// ---------- 2. 绘制边框 ----------
+	// 穿墙模式使用灰色虚线边框，表示边界可以穿过
 	borderStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	vertical, horizontal := '|', '-'
+	if r.game.wrap {
+		borderStyle = tcell.StyleDefault.Foreground(tcell.ColorGray)
+		vertical, horizontal = ':', '.'
+	}
 
 	// 绘制左右边框
-	for y := 0; y < BoardHeight+2; y++ {
-		r.screen.SetContent(2, y+1, '|', nil, borderStyle)
-		r.screen.SetContent(BoardWidth*2+3, y+1, '|', nil, borderStyle)
+	for y := 0; y < height+2; y++ {
+		r.set(2, y+1, vertical, borderStyle)
+		r.set(width*2+4, y+1, vertical, borderStyle)
 	}
 	// 绘制上下边框
-	for x := 0; x < BoardWidth*2+2; x++ {
-		r.screen.SetContent(3+x, 1, '-', nil, borderStyle)
-		r.screen.SetContent(3+x, BoardHeight+2, '-', nil, borderStyle)
-	}
-
-	// ---------- 3. 绘制蛇 ----------
-	// 蛇头使用亮绿色，其他部分使用普通绿色
-	for i, p := range r.game.snake {
-		var snakeStyle tcell.Style
-		if i == 0 {
-			// 蛇头
-			snakeStyle = tcell.StyleDefault.Foreground(tcell.ColorLime)
-		} else {
-			// 蛇身
-			snakeStyle = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	for x := 0; x < width*2+1; x++ {
+		r.set(3+x, 1, horizontal, borderStyle)
+		r.set(3+x, height+2, horizontal, borderStyle)
+	}
+
+	// ---------- 3. 绘制障碍物、传送门和蛇 ----------
+	wallStyle := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	for _, p := range r.game.walls {
+		r.set(4+p.X*2, p.Y+2, '█', wallStyle)
+		r.set(5+p.X*2, p.Y+2, '█', wallStyle)
+	}
+	// 大逃杀中已关闭的区域
+	zoneStyle := tcell.StyleDefault.Foreground(tcell.ColorMaroon)
+	for _, p := range r.game.closedCells() {
+		r.set(4+p.X*2, p.Y+2, '░', zoneStyle)
+		r.set(5+p.X*2, p.Y+2, '░', zoneStyle)
+	}
+	// 成对的传送门使用相同的颜色
+	for n, pt := range r.game.portals {
+		portalStyle := tcell.StyleDefault.Foreground(portalColors[n%len(portalColors)])
+		for _, p := range []Point{pt.A, pt.B} {
+			r.set(4+p.X*2, p.Y+2, '◎', portalStyle)
+			r.set(5+p.X*2, p.Y+2, ' ', portalStyle)
 		}
+	}
 
-		drawX := 4 + p.x*2
-		drawY := p.y + 2
-		r.screen.SetContent(drawX, drawY, '●', nil, snakeStyle)
-		r.screen.SetContent(drawX+1, drawY, ' ', nil, snakeStyle)
+	// 每个玩家有自己的颜色，蛇头使用亮色，其他部分使用普通颜色
+	// 多人模式下撞死的蛇显示为灰色
+	versus := len(r.game.snakes) > 1
+	for n, s := range r.game.snakes {
+		head, body := PlayerColor(n)
+		for i, p := range s.body {
+			var snakeStyle tcell.Style
+			switch {
+			case versus && !s.alive:
+				snakeStyle = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+			case i == 0:
+				// 蛇头
+				snakeStyle = tcell.StyleDefault.Foreground(head)
+			default:
+				// 蛇身
+				snakeStyle = tcell.StyleDefault.Foreground(body)
+			}
+
+			drawX := 4 + p.X*2
+			drawY := p.Y + 2
+			r.set(drawX, drawY, '●', snakeStyle)
+			r.set(drawX+1, drawY, ' ', snakeStyle)
+		}
 	}
 
-	// ---------- 4. 绘制食物 ----------
-	foodStyle := tcell.StyleDefault.Foreground(tcell.ColorRed)
-	drawX := 4 + r.game.food.x*2
-	drawY := r.game.food.y + 2
-	r.screen.SetContent(drawX, drawY, '★', nil, foodStyle)
-	r.screen.SetContent(drawX+1, drawY, ' ', nil, foodStyle)
+	// ---------- 4. 绘制食物和巡逻障碍 ----------
+	for _, f := range r.game.foods {
+		glyph, foodStyle := FoodLook(f)
+		drawX := 4 + f.Pos.X*2
+		drawY := f.Pos.Y + 2
+		r.set(drawX, drawY, glyph, foodStyle)
+		r.set(drawX+1, drawY, ' ', foodStyle)
+	}
+	hazardStyle := tcell.StyleDefault.Foreground(tcell.ColorRed)
+	for _, h := range r.game.hazards {
+		r.set(4+h.Pos.X*2, h.Pos.Y+2, '▒', hazardStyle)
+		r.set(5+h.Pos.X*2, h.Pos.Y+2, '▒', hazardStyle)
+	}
+	showHeat := r.heatmap && r.game.gameOver
+	if showHeat {
+		r.drawHeatmap()
+	}
 
 	// ---------- 5. 绘制右侧信息面板 ----------
 	infoStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
-	nextX := BoardWidth*2 + 8
+	nextX := width*2 + 8
 
 	// 游戏标题
 	title := "SNAKE"
+	switch {
+	case r.game.shrinkEvery > 0:
+		title += " ROYALE"
+	case versus:
+		title += " VS"
+	case r.daily != "":
+		title += " DAILY"
+	}
+	if r.game.wrap {
+		title += " (WRAP)"
+	}
+	if r.isCPU(0) {
+		title += " DEMO"
+	}
+	if r.replay {
+		title += " REPLAY"
+	}
 	for i, ch := range title {
-		r.screen.SetContent(nextX+i, 2, ch, nil, infoStyle)
+		r.set(nextX+i, 2, ch, infoStyle)
 	}
 
-	// 分数
-	scoreText := fmt.Sprintf("SCORE: %d", r.game.score)
-	for i, ch := range scoreText {
-		r.screen.SetContent(nextX+i, 5, ch, nil, infoStyle)
+	// 分数：单人模式显示得分和最高分，多人模式显示每个玩家的得分和累计胜局
+	if versus {
+		wins := r.game.wins()
+		for n, s := range r.game.snakes {
+			head, _ := PlayerColor(n)
+			style := tcell.StyleDefault.Foreground(head)
+			r.text(nextX, 4+n, fmt.Sprintf("P%d: %-5d WINS: %d", n+1, s.score, wins[n]), style)
+		}
+	} else {
+		r.text(nextX, 5, fmt.Sprintf("SCORE: %d", r.game.snakes[0].score), infoStyle)
+		if r.daily != "" {
+			r.text(nextX, 7, "TODAY: "+r.bestText(), infoStyle)
+		} else {
+			r.text(nextX, 7, "BEST:  "+r.bestText(), infoStyle)
+		}
+	}
+
+	// 每日挑战的日期，当天的计分机会用掉之后标记为练习
+	if r.daily != "" {
+		r.text(nextX, 3, r.daily, infoStyle)
+		if r.practice {
+			r.text(nextX, 4, "PRACTICE", infoStyle)
+		}
 	}
 
-	// 操作说明
-	controls := []string{
-		"CONTROLS:",
-		"↑↓←→ : Move",
-		"P   : Pause",
-		"R   : Restart",
-		"Esc : Back to Menu",
+	// 挑战模式的名称和计时：限时模式显示剩余时间（向上取整到秒），目标长度模式显示已用时间
+	switch r.game.mode {
+	case ModeTimeAttack:
+		r.text(nextX, 3, fmt.Sprintf("TIME ATTACK %ds", int(TimeAttackLimit/time.Second)), infoStyle)
+		r.text(nextX, 4, "TIME:  "+formatTime(r.game.timeLeft()+time.Second-1), infoStyle)
+	case ModeLength:
+		r.text(nextX, 3, fmt.Sprintf("LENGTH RACE TO %d", r.game.target), infoStyle)
+		r.text(nextX, 4, "TIME:  "+formatTenths(r.game.elapsed), infoStyle)
+		r.text(nextX, 6, fmt.Sprintf("LENGTH: %d/%d", len(r.game.snakes[0].body), r.game.target), infoStyle)
+	}
+
+	// 加速/减速效果剩余步数
+	if r.game.effectTicks > 0 {
+		effectText := fmt.Sprintf("SLOW:  %d", r.game.effectTicks)
+		if r.game.effect == SpeedUp {
+			effectText = fmt.Sprintf("FAST:  %d", r.game.effectTicks)
+		}
+		for i, ch := range effectText {
+			r.set(nextX+i, 8, ch, infoStyle)
+		}
+	}
+
+	// 关卡名和目标长度
+	if lvl := r.game.currentLevel(); lvl != nil {
+		levelText := fmt.Sprintf("LEVEL %d/%d %s", r.game.level+1, len(r.game.levels), lvl.Name)
+		for i, ch := range levelText {
+			r.set(nextX+i, 3, ch, infoStyle)
+		}
+		if lvl.Target > 0 {
+			targetText := fmt.Sprintf("LENGTH: %d/%d", len(r.game.snakes[0].body), lvl.Target)
+			for i, ch := range targetText {
+				r.set(nextX+i, 6, ch, infoStyle)
+			}
+		}
+	}
+
+	// 加速键剩余步数
+	if r.game.boostTicks > 0 {
+		r.text(nextX, 9, fmt.Sprintf("BOOST: x2 %d", r.game.boostTicks), infoStyle)
+	}
+
+	// 大逃杀距离下一次缩圈的时间（向上取整到秒），缩到最小后不再显示倒计时
+	if r.game.shrinkEvery > 0 {
+		zoneText := "ZONE:  final"
+		if next := r.game.nextShrink(); next > 0 {
+			zoneText = "ZONE:  " + formatTime(next+time.Second-1)
+		}
+		r.text(nextX, 9, zoneText, infoStyle)
+	}
+
+	// 操作说明（根据当前按键映射生成）
+	// 加速键只在玩家控制的单人游戏中显示，没有二号玩家时不显示二号玩家的按键
+	actions := []input.Action{input.MoveUp, input.MoveDown, input.MoveLeft, input.MoveRight}
+	if !versus && !r.isCPU(0) {
+		actions = append(actions, input.Boost)
+	}
+	actions = append(actions, input.Pause, input.Restart, input.Back)
+	if versus && !r.isCPU(1) {
+		actions = append(actions, input.P2MoveUp, input.P2MoveDown, input.P2MoveLeft, input.P2MoveRight)
+	}
+	help := r.keys.HelpFor(actions...)
+	if r.game.gameOver && !versus {
+		help = r.results() // 单人游戏结束后改为显示本局统计
 	}
-	for i, ctrl := range controls {
-		for j, ch := range ctrl {
-			r.screen.SetContent(nextX+j, 10+i, ch, nil, infoStyle)
+	if showHeat {
+		help = nil
+		r.drawHeatLegend(nextX, 10)
+	}
+	for i, ctrl := range help {
+		for j, ch := range []rune(ctrl) {
+			r.set(nextX+j, 10+i, ch, infoStyle)
 		}
 	}
 
@@ -111,21 +335,261 @@ func (r *Renderer) Render() {
 	if r.game.paused {
 		pauseText := "PAUSED"
 		for i, ch := range pauseText {
-			r.screen.SetContent(BoardWidth/2*2+i, BoardHeight/2+2, ch, nil, infoStyle)
+			r.set(width/2*2+i, height/2+2, ch, infoStyle)
 		}
 	}
 
-	if r.game.gameOver {
-		gameOverText := "GAME OVER"
+	switch {
+	case showHeat:
+		// 热力图上不显示结束提示
+	case r.game.gameOver && versus:
+		r.drawScoreboard()
+	case r.game.gameOver:
+		// 填满面板时显示获胜画面
+		gameOverText, gameOverStyle := "GAME OVER", infoStyle
+		if r.game.won {
+			gameOverText = "YOU WIN!"
+			switch r.game.mode {
+			case ModeTimeAttack:
+				gameOverText = "TIME UP!"
+			case ModeLength:
+				gameOverText = "TARGET REACHED!"
+			}
+			gameOverStyle = tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+		}
 		for i, ch := range gameOverText {
-			r.screen.SetContent(BoardWidth/2*2+i, BoardHeight/2+2, ch, nil, infoStyle)
+			r.set(width/2*2+i, height/2+2, ch, gameOverStyle)
 		}
-		restartText := "Press R to restart"
-		for i, ch := range restartText {
-			r.screen.SetContent(BoardWidth/2*2-2+i, BoardHeight/2+4, ch, nil, infoStyle)
+		if !r.replay {
+			restartText := fmt.Sprintf("Press %s to restart", r.keys.Label(input.Restart))
+			for i, ch := range restartText {
+				r.set(width/2*2-2+i, height/2+4, ch, infoStyle)
+			}
+		}
+	}
+
+	if r.finished && !showHeat {
+		for i, ch := range "END OF REPLAY" {
+			r.set(width/2*2-2+i, height/2+6, ch, infoStyle)
 		}
 	}
 
 	// 刷新屏幕显示
 	r.screen.Show()
 }
+
+// results 单人游戏结束后信息面板中的本局统计
+//
+//	RESULTS:
+//	Hit a wall
+//	NEW RECORD!
+//	TIME:    0:42
+//	FOOD:    12
+//	TICKS/FOOD: 14.2
+//	LONGEST: 15
+//	TURNS:   48
+func (r *Renderer) results() []string {
+	s := r.game.snakes[0].stats
+	cause, elapsed := s.Death.String(), formatTime(s.Time)
+	if r.game.won {
+		cause = "Completed!"
+		switch r.game.mode {
+		case ModeTimeAttack:
+			cause = "Time's up!"
+		case ModeLength:
+			cause, elapsed = "Target reached!", formatTenths(s.Time)
+		}
+	}
+	lines := []string{"RESULTS:", cause}
+	switch {
+	case r.daily != "" && r.record:
+		lines = append(lines, "DAILY SCORE SAVED!")
+	case r.daily != "":
+		lines = append(lines, "PRACTICE (not scored)")
+	case r.record:
+		lines = append(lines, "NEW RECORD!")
+	}
+	return append(lines,
+		"TIME:    "+elapsed,
+		fmt.Sprintf("FOOD:    %d", s.Foods),
+		fmt.Sprintf("TICKS/FOOD: %.1f", s.TicksPerFood()),
+		fmt.Sprintf("LONGEST: %d", s.MaxLength),
+		fmt.Sprintf("TURNS:   %d", s.Turns),
+		"",
+		r.heatmapHint(),
+	)
+}
+
+// bestText 信息面板中最好成绩的文字：目标长度模式显示最短用时，没有记录时显示 "--"
+// 每日挑战显示当天计分的一局的得分，还没有时显示 "--"
+func (r *Renderer) bestText() string {
+	if r.daily != "" && !r.practice {
+		return "--"
+	}
+	if r.game.mode != ModeLength {
+		return fmt.Sprint(r.best)
+	}
+	if r.best == 0 {
+		return "--"
+	}
+	return formatTenths(time.Duration(r.best) * time.Millisecond)
+}
+
+// heatmapHint 游戏结束后提示如何显示热力图
+func (r *Renderer) heatmapHint() string {
+	return fmt.Sprintf("Press %s for heatmap", r.keys.Label(input.Heatmap))
+}
+
+// drawHeatmap 用热力图覆盖面板：每个格子的背景色表示蛇头经过的次数，从未经过的格子留空
+// 终端支持真彩色时使用平滑渐变，否则使用 256 色调色板（见 HeatColor）
+func (r *Renderer) drawHeatmap() {
+	heat := r.game.heat
+	most := heat.Max()
+	colors := r.screen.Colors()
+	walls := r.game.board
+	for y, row := range heat {
+		for x, n := range row {
+			if walls[y][x] == cellWall {
+				continue
+			}
+			style := tcell.StyleDefault.Background(tcell.ColorBlack)
+			if n > 0 {
+				style = style.Background(HeatColor(heat.level(n, most), colors))
+			}
+			r.set(4+x*2, y+2, ' ', style)
+			r.set(5+x*2, y+2, ' ', style)
+		}
+	}
+}
+
+// heatLegendWidth 图例中色带的格数
+const heatLegendWidth = 12
+
+// drawHeatLegend 在信息面板 (x, y) 处绘制热力图的图例
+//
+//	HEATMAP:
+//	VISITS:     214
+//	MOST:       9
+//	low ████████████ high
+//
+//	Press m to hide
+func (r *Renderer) drawHeatLegend(x, y int) {
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	heat := r.game.heat
+	r.text(x, y, "HEATMAP:", style)
+	r.text(x, y+1, fmt.Sprintf("VISITS:     %d", heat.Total()), style)
+	r.text(x, y+2, fmt.Sprintf("MOST:       %d", heat.Max()), style)
+	r.text(x, y+3, "low", style)
+	colors := r.screen.Colors()
+	for i := 0; i < heatLegendWidth; i++ {
+		t := float64(i+1) / heatLegendWidth
+		r.set(x+4+i, y+3, ' ', tcell.StyleDefault.Background(HeatColor(t, colors)))
+	}
+	r.text(x+5+heatLegendWidth, y+3, "high", style)
+	r.text(x, y+5, fmt.Sprintf("Press %s to hide", r.keys.Label(input.Heatmap)), style)
+}
+
+// formatTime 把时长格式化为 "分:秒"，例如 "1:05"
+func formatTime(d time.Duration) string {
+	secs := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// TooSmall 终端是否放不下当前面板和信息面板
+func (r *Renderer) TooSmall() bool {
+	cols, rows := r.screen.Size()
+	layoutW, layoutH := layoutSize(r.game.width, r.game.height)
+	return cols < layoutW || rows < layoutH
+}
+
+// drawTooSmall 在终端左上角提示需要的大小
+func (r *Renderer) drawTooSmall() {
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	cols, rows := r.screen.Size()
+	layoutW, layoutH := layoutSize(r.game.width, r.game.height)
+	lines := []string{
+		"Terminal too small",
+		fmt.Sprintf("need %dx%d, have %dx%d", layoutW, layoutH, cols, rows),
+		"Enlarge the window to continue",
+	}
+	for i, line := range lines {
+		drawText(r.screen, 0, i, line, style)
+	}
+}
+
+// set 绘制一个单元格，坐标相对于居中后的画面
+func (r *Renderer) set(x, y int, ch rune, style tcell.Style) {
+	r.screen.SetContent(r.offsetX+x, r.offsetY+y, ch, nil, style)
+}
+
+// text 绘制一行文字，坐标相对于居中后的画面
+func (r *Renderer) text(x, y int, text string, style tcell.Style) {
+	drawText(r.screen, r.offsetX+x, r.offsetY+y, text, style)
+}
+
+// isCPU 第 i 条蛇是否由电脑控制
+func (r *Renderer) isCPU(i int) bool {
+	return i < len(r.cpu) && r.cpu[i]
+}
+
+// scoreboardRounds 记分板最多显示的最近局数
+const scoreboardRounds = 5
+
+// drawScoreboard 多人模式一局结束时在面板上方绘制记分板
+//
+//	ROUND 3: P2 WINS
+//
+//	ROUND   P1   P2
+//	    2   40*  20
+//	    3   10   30*
+//	WINS     1    2
+func (r *Renderer) drawScoreboard() {
+	rounds := r.game.rounds
+	if len(rounds) == 0 {
+		return
+	}
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+
+	last := rounds[len(rounds)-1]
+	result := "DRAW"
+	if last.Winner >= 0 {
+		result = fmt.Sprintf("P%d WINS", last.Winner+1)
+	}
+
+	header := "ROUND"
+	for n := range r.game.snakes {
+		header += fmt.Sprintf("   P%d", n+1)
+	}
+	var lines []string
+	first := max(0, len(rounds)-scoreboardRounds)
+	for i, round := range rounds[first:] {
+		line := fmt.Sprintf("%5d", first+i+1)
+		for n, score := range round.Scores {
+			mark := " "
+			if n == round.Winner {
+				mark = "*"
+			}
+			line += fmt.Sprintf("  %3d%s", score, mark)
+		}
+		lines = append(lines, line)
+	}
+	wins := "WINS "
+	for _, w := range r.game.wins() {
+		wins += fmt.Sprintf("  %3d ", w)
+	}
+
+	// 先用空白清出一块区域，避免与面板内容重叠
+	x, y := 6, 3
+	rows := append([]string{fmt.Sprintf("ROUND %d: %s", len(rounds), result), "", header}, lines...)
+	rows = append(rows, wins, "")
+	if !r.replay {
+		rows = append(rows, fmt.Sprintf("Press %s for next round", r.keys.Label(input.Restart)))
+	}
+	rows = append(rows, r.heatmapHint())
+	boxWidth := r.game.width*2 - 5
+	for i, row := range rows {
+		r.text(x-1, y+i, fmt.Sprintf(" %-*s", boxWidth, row), style)
+	}
+	r.text(x, y, rows[0], titleStyle)
+}
@@ -0,0 +1,200 @@
+package snake
+
+import (
+	"testing"
+
+	"go-game/input"
+	"go-game/internal/screentest"
+)
+
+// chaseFood 朝食物方向走若干步（先对齐 X 再对齐 Y），得到一个有内容的画面
+func chaseFood(g *Game, steps int) {
+	for i := 0; i < steps && !g.Over(); i++ {
+		s := g.State()
+		head := s.Snake[0]
+		switch {
+		case s.Food.X < head.X:
+			g.Step(Left)
+		case s.Food.X > head.X:
+			g.Step(Right)
+		case s.Food.Y < head.Y:
+			g.Step(Up)
+		default:
+			g.Step(Down)
+		}
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func() *Game
+		replay bool
+		best   int
+		cpu    []bool
+	}{
+		{
+			name:  "start",
+			setup: func() *Game { return NewGame(WithSeed(1)) },
+		},
+		{
+			name: "midgame",
+			setup: func() *Game {
+				g := NewGame(WithSeed(1))
+				chaseFood(g, 40)
+				return g
+			},
+		},
+		{
+			name: "boost",
+			setup: func() *Game {
+				g := NewGame(WithSeed(1))
+				chaseFood(g, 5)
+				g.Boost()
+				return g
+			},
+		},
+		{
+			name: "paused",
+			setup: func() *Game {
+				g := NewGame(WithSeed(1))
+				chaseFood(g, 5)
+				g.paused = true
+				return g
+			},
+		},
+		{
+			name: "gameover",
+			setup: func() *Game {
+				g := NewGame(WithSeed(1))
+				for !g.Over() {
+					g.Step(Up)
+				}
+				return g
+			},
+		},
+		{
+			name: "won",
+			setup: func() *Game {
+				g := almostFullGame()
+				g.Step()
+				return g
+			},
+		},
+		{
+			name: "wrap",
+			best: 120,
+			setup: func() *Game {
+				g := NewGame(WithSeed(2), WithWrap(true))
+				for i := 0; i < BoardHeight/2+2; i++ {
+					g.Step(Up)
+				}
+				return g
+			},
+		},
+		{
+			name: "foods",
+			setup: func() *Game {
+				g := NewGame(WithSeed(1))
+				g.foods = nil
+				for kind := range foodInfos {
+					g.foods = append(g.foods, Food{Pos: Point{2 + kind*3, 3}, Kind: FoodKind(kind), TTL: foodInfos[kind].ttl})
+				}
+				g.foods[len(g.foods)-1].TTL = blinkTicks - 1
+				g.effect, g.effectTicks = SpeedUp, 12
+				return g
+			},
+		},
+		{
+			name: "level",
+			setup: func() *Game {
+				levels, err := FindLevels(LevelCampaign)
+				if err != nil {
+					panic(err)
+				}
+				g := NewGame(WithSeed(4), WithLevels(levels[1:]...))
+				chaseFood(g, 3)
+				return g
+			},
+		},
+		{
+			name: "entities",
+			setup: func() *Game {
+				levels, err := FindLevels("Warp")
+				if err != nil {
+					panic(err)
+				}
+				g := NewGame(WithSeed(4), WithLevels(levels...))
+				for i := 0; i < 4; i++ {
+					g.Step()
+				}
+				return g
+			},
+		},
+		{
+			name: "versus",
+			setup: func() *Game {
+				g := NewGame(WithSeed(5), WithPlayers(2))
+				g.TurnPlayer(1, Right)
+				chaseFood(g, 5)
+				return g
+			},
+		},
+		{
+			name: "scoreboard",
+			setup: func() *Game {
+				g := NewGame(WithSeed(5), WithPlayers(2))
+				for round := 0; round < 3; round++ {
+					for !g.Over() {
+						g.TurnPlayer(1, Direction(round%2+2))
+						chaseFood(g, 1)
+					}
+					if round < 2 {
+						g.reset()
+					}
+				}
+				return g
+			},
+		},
+		{
+			name: "opponents",
+			cpu:  []bool{false, true, true, true},
+			setup: func() *Game {
+				g := NewGame(WithSeed(5), WithPlayers(4))
+				chaseFood(g, 3)
+				return g
+			},
+		},
+		{
+			name: "royale",
+			cpu:  []bool{false, true, true},
+			setup: func() *Game {
+				g := NewGame(WithSeed(2), WithSize(BoardWidth, BoardHeight), WithPlayers(3), WithShrink(DefaultShrinkEvery))
+				g.closeRing()
+				chaseFood(g, 3)
+				return g
+			},
+		},
+		{
+			name:   "replay",
+			replay: true,
+			setup: func() *Game {
+				g := NewGame(WithSeed(3))
+				chaseFood(g, 20)
+				return g
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+			r := NewRenderer(screen, tt.setup(), input.DefaultSnake())
+			r.replay = tt.replay
+			r.best = tt.best
+			r.cpu = tt.cpu
+			r.Render()
+			screentest.AssertScreen(t, "render_"+tt.name, screen)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package snake
+
+import "time"
+
+// ============================================
+// 大逃杀 - 不断缩小的安全区域
+// ============================================
+// 多条蛇在同一面板上对战，每隔 shrinkEvery 的游戏时间，安全区域从四周向中心缩小一圈：
+// 圈上的格子记入墙壁遮罩（mask），在面板上标记为 cellWall，之后由 collides 与障碍物一样处理。
+// 缩圈时身体有任何一节在圈上的蛇被压死，圈上的食物消失。最后活着的蛇获胜（见 endRound）。
+//
+// 游戏时间按每步的移动间隔累计（与 Stats.Time 相同），因此缩圈的时机只取决于种子和操作，
+// 录像回放时完全一致
+
+// DefaultShrinkEvery 大逃杀默认的缩圈间隔
+const DefaultShrinkEvery = 10 * time.Second
+
+// royaleMinSide 安全区域的最小边长，缩到这个大小后不再缩圈
+const royaleMinSide = 4
+
+// SetShrink 设置缩圈间隔，0 表示不缩圈（普通对战）
+func (g *Game) SetShrink(every time.Duration) {
+	g.shrinkEvery = max(every, 0)
+}
+
+// newMask 创建与面板同样大小、没有关闭任何格子的墙壁遮罩
+func newMask(width, height int) [][]bool {
+	mask := make([][]bool, height)
+	for y := range mask {
+		mask[y] = make([]bool, width)
+	}
+	return mask
+}
+
+// closeCell 把 p 记入墙壁遮罩，面板上标记为墙
+func (g *Game) closeCell(p Point) {
+	g.mask[p.Y][p.X] = true
+	g.board[p.Y][p.X] = cellWall
+}
+
+// closedCells 返回墙壁遮罩中所有已关闭的格子
+func (g *Game) closedCells() []Point {
+	var cells []Point
+	for y, row := range g.mask {
+		for x, closed := range row {
+			if closed {
+				cells = append(cells, Point{x, y})
+			}
+		}
+	}
+	return cells
+}
+
+// canShrink 再缩小一圈后安全区域是否仍不小于 royaleMinSide
+func (g *Game) canShrink() bool {
+	inner := 2 * (g.ring + 1)
+	return g.width-inner >= royaleMinSide && g.height-inner >= royaleMinSide
+}
+
+// nextShrink 距离下一次缩圈的游戏时间，没有开启大逃杀或已经缩到最小时返回 0
+func (g *Game) nextShrink() time.Duration {
+	if g.shrinkEvery <= 0 || !g.canShrink() {
+		return 0
+	}
+	return time.Duration(g.ring+1)*g.shrinkEvery - g.elapsed
+}
+
+// advanceZone 本局游戏时间到达下一次缩圈的时间时，关闭安全区域的最外一圈
+// 由 move 在累计游戏时间后调用（在判定本局是否结束之前）
+func (g *Game) advanceZone() {
+	if g.shrinkEvery <= 0 {
+		return
+	}
+	if g.canShrink() && g.elapsed >= time.Duration(g.ring+1)*g.shrinkEvery {
+		g.closeRing()
+	}
+}
+
+// closeRing 关闭安全区域的最外一圈
+// 圈上的食物消失（被关闭的苹果在安全区域内重新生成），身体压在圈上的蛇撞死
+func (g *Game) closeRing() {
+	r := g.ring
+	g.ring++
+	for y := r; y < g.height-r; y++ {
+		for x := r; x < g.width-r; x++ {
+			if x == r || y == r || x == g.width-1-r || y == g.height-1-r {
+				g.closeCell(Point{x, y})
+			}
+		}
+	}
+
+	apples := 0
+	kept := g.foods[:0]
+	for _, f := range g.foods {
+		if g.mask[f.Pos.Y][f.Pos.X] {
+			if f.Kind == Apple {
+				apples++
+			}
+			continue
+		}
+		kept = append(kept, f)
+	}
+	g.foods = kept
+
+	for _, p := range g.snakes {
+		if !p.alive {
+			continue
+		}
+		for _, c := range p.body {
+			if g.mask[c.Y][c.X] {
+				p.alive = false
+				p.stats.Death, p.stats.Killer = DeathZone, -1
+				break
+			}
+		}
+	}
+
+	for ; apples > 0; apples-- {
+		g.spawnFood()
+	}
+}
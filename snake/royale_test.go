@@ -0,0 +1,90 @@
+package snake
+
+import (
+	"testing"
+	"time"
+)
+
+// newRoyaleTestGame 两条蛇的大逃杀，速度恒定为 SpeedNormal，便于计算缩圈的时机
+func newRoyaleTestGame(t *testing.T, every time.Duration) *Game {
+	t.Helper()
+	g := NewGame(WithSeed(1), WithSize(BoardWidth, BoardHeight), WithPlayers(2), WithShrink(every))
+	d, err := ParseDifficulty("normal", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetDifficulty(d)
+	return g
+}
+
+// TestRoyaleShrink 到时间时关闭最外一圈，压在圈上的蛇撞死，最后活着的蛇获胜
+func TestRoyaleShrink(t *testing.T) {
+	g := newRoyaleTestGame(t, time.Second)
+
+	// 一号蛇一直向上走，第 7 步到达顶端；二号蛇向右再向下，留在圈内
+	g.TurnPlayer(1, Right)
+	g.TurnPlayer(1, Down)
+	for i := 0; i < 6; i++ {
+		g.Step()
+	}
+	if s := g.State(); s.Ring != 0 || s.NextShrink != 1000-6*SpeedNormal || len(s.Walls) != 0 {
+		t.Fatalf("before shrink: ring %d next %d walls %d", s.Ring, s.NextShrink, len(s.Walls))
+	}
+
+	g.Step()
+	s := g.State()
+	if want := 2*(BoardWidth+BoardHeight) - 4; s.Ring != 1 || len(s.Walls) != want {
+		t.Errorf("after shrink: ring %d walls %d, want 1 %d", s.Ring, len(s.Walls), want)
+	}
+	if !s.GameOver || len(s.Rounds) != 1 || s.Rounds[0].Winner != 1 {
+		t.Errorf("game over %v rounds %+v, want player 2 to win", s.GameOver, s.Rounds)
+	}
+	if d := s.Snakes[0].Stats.Death; d != DeathZone {
+		t.Errorf("player 1 death = %v, want %v", d, DeathZone)
+	}
+	for _, f := range s.Foods {
+		if g.mask[f.Pos.Y][f.Pos.X] {
+			t.Errorf("food %+v left inside the closed zone", f)
+		}
+	}
+}
+
+// TestRoyaleCollidesWithZone 撞上已关闭的格子与撞墙一样，撞死原因为 DeathZone
+func TestRoyaleCollidesWithZone(t *testing.T) {
+	g := newRoyaleTestGame(t, time.Hour)
+	g.closeRing()
+
+	// 一号蛇从 x=6 向左走，第 6 步进入已关闭的 x=0
+	g.TurnPlayer(0, Left)
+	for i := 0; i < 5; i++ {
+		g.Step()
+	}
+	if !g.State().Snakes[0].Alive {
+		t.Fatal("player 1 died too early")
+	}
+	g.Step()
+	if s := g.State().Snakes[0]; s.Alive || s.Stats.Death != DeathZone {
+		t.Errorf("alive %v death %v, want dead by %v", s.Alive, s.Stats.Death, DeathZone)
+	}
+}
+
+// TestRoyaleMinimum 安全区域缩到最小边长后不再缩圈，重新开始时恢复整个面板
+func TestRoyaleMinimum(t *testing.T) {
+	g := newRoyaleTestGame(t, time.Second)
+	for g.canShrink() {
+		g.closeRing()
+	}
+	if g.ring != 5 || g.nextShrink() != 0 {
+		t.Errorf("ring %d next %v, want 5 rings and no more shrinking", g.ring, g.nextShrink())
+	}
+	g.elapsed += time.Hour
+	g.advanceZone()
+	if g.ring != 5 {
+		t.Errorf("zone kept shrinking to ring %d", g.ring)
+	}
+
+	g.reset()
+	if s := g.State(); s.Ring != 0 || len(s.Walls) != 0 || s.NextShrink != 1000 {
+		t.Errorf("after reset: ring %d walls %d next %d", s.Ring, len(s.Walls), s.NextShrink)
+	}
+}
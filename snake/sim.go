@@ -0,0 +1,200 @@
+package snake
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ============================================
+// Headless 模拟接口
+// ============================================
+// 不依赖终端即可驱动游戏：固定种子 + 逐步推进 + 只读快照
+// 适用于测试、机器人和训练脚本
+//
+// 用法：
+//   g := snake.NewGame(snake.WithSeed(42))
+//   for !g.Over() {
+//       g.Step(snake.Left)
+//       state := g.State()
+//   }
+
+// directionNames 方向名称，与 input.Action 的配置名一致，录像文件中使用
+var directionNames = []string{"MoveUp", "MoveDown", "MoveLeft", "MoveRight"}
+
+// String 返回方向名称
+func (d Direction) String() string {
+	if int(d) < len(directionNames) {
+		return directionNames[d]
+	}
+	return "Unknown"
+}
+
+const (
+	MaxPlayers = 2 // 同一键盘上最多的玩家数
+	MaxSnakes  = 4 // 同一面板上最多的蛇数（玩家加电脑对手）
+)
+
+// ParseDirection 根据名称查找方向
+func ParseDirection(name string) (Direction, bool) {
+	for i, n := range directionNames {
+		if n == name {
+			return Direction(i), true
+		}
+	}
+	return Up, false
+}
+
+// Reset 使用新的种子重新开始
+func (g *Game) Reset(seed int64) {
+	g.seed, g.rng = seed, rand.New(rand.NewSource(seed))
+	g.reset()
+}
+
+// Turn 请求一号玩家转向：加入转向队列，之后每移动一格执行一个
+// 与前一个方向相反的请求会被忽略
+func (g *Game) Turn(d Direction) {
+	g.TurnPlayer(0, d)
+}
+
+// TurnPlayer 请求第 i 个玩家（从 0 开始）转向，规则同 Turn
+func (g *Game) TurnPlayer(i int, d Direction) {
+	if i >= 0 && i < len(g.snakes) {
+		g.snakes[i].queueTurn(d)
+	}
+}
+
+// Boost 按下加速键：接下来 BoostTicks 步移动间隔减半、得分翻倍，再次按下重新计数
+// 只在单人游戏中有效（多人对战所有蛇共用一个节拍）
+func (g *Game) Boost() {
+	if g.players == 1 && !g.gameOver {
+		g.boostTicks = BoostTicks
+	}
+}
+
+// SetDifficulty 设置速度曲线（见 Difficulties），只影响 State().Speed 和实时游戏的节奏
+func (g *Game) SetDifficulty(d Difficulty) {
+	g.difficulty = d
+}
+
+// Step 依次将一号玩家的转向请求加入队列，然后让所有蛇移动一格（各消耗一个转向）
+func (g *Game) Step(turns ...Direction) {
+	if g.gameOver {
+		return
+	}
+	for _, d := range turns {
+		g.Turn(d)
+	}
+	g.move()
+}
+
+// Over 游戏是否已结束
+func (g *Game) Over() bool {
+	return g.gameOver
+}
+
+// Heatmap 返回本关蛇头经过每个格子的次数（副本）
+// 多人模式下合计所有蛇；进入下一关或重新开始时清零
+func (g *Game) Heatmap() Heatmap {
+	return g.heat.clone()
+}
+
+// opposite 返回相反方向
+func opposite(d Direction) Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	default:
+		return Left
+	}
+}
+
+// ============================================
+// 只读快照
+// ============================================
+
+// SnakeState 一条蛇的状态
+type SnakeState struct {
+	Body      []Point   // 蛇身体，Body[0] 为头部
+	Direction Direction // 当前移动方向
+	Score     int       // 得分
+	Length    int       // 目标长度
+	Alive     bool      // 是否还活着
+	Stats     Stats     // 本局的统计
+}
+
+// State 某一时刻的游戏状态，所有切片都是副本，修改它不会影响游戏
+// Snake、Direction、Score、Length 是一号玩家的状态，多人模式下每条蛇的状态见 Snakes
+type State struct {
+	Width, Height int       // 面板尺寸
+	Walls         []Point   // 障碍物（包括大逃杀中已关闭的格子）
+	Portals       []Portal  // 传送门
+	Hazards       []Hazard  // 巡逻障碍的当前位置和方向
+	Level         string    // 当前关卡名，没有关卡时为空
+	Target        int       // 当前关卡的目标长度，0 表示没有目标
+	Snake         []Point   // 蛇身体，Snake[0] 为头部
+	Food          Point     // 苹果位置
+	Foods         []Food    // 面板上的所有食物（包括苹果）
+	Direction     Direction // 当前移动方向
+	Score         int       // 当前得分
+	Length        int       // 目标长度
+	Speed         int       // 当前移动间隔（毫秒），包含加速/减速效果和加速键
+	Boost         int       // 加速键剩余的步数，0 表示没有加速
+	Ring          int       // 大逃杀中已经关闭的圈数
+	NextShrink    int       // 大逃杀中距离下一次缩圈的游戏时间（毫秒），0 表示不会再缩圈
+	Elapsed       int       // 本局的游戏时间（毫秒）：每步按移动间隔累计，挑战模式按它计时
+	Wrap          bool      // 是否为穿墙模式
+	GameOver      bool      // 游戏是否结束
+	Won           bool      // 是否获胜（填满面板或完成最后一关）
+
+	Snakes []SnakeState // 所有蛇的状态
+	Rounds []Round      // 多人模式下已结束的各局结果
+}
+
+// State 返回当前状态的快照
+func (g *Game) State() State {
+	s := State{
+		Width:      g.width,
+		Height:     g.height,
+		Walls:      append(append([]Point(nil), g.walls...), g.closedCells()...),
+		Portals:    append([]Portal(nil), g.portals...),
+		Hazards:    append([]Hazard(nil), g.hazards...),
+		Food:       g.apple(),
+		Foods:      append([]Food(nil), g.foods...),
+		Speed:      g.getSpeed(),
+		Boost:      g.boostTicks,
+		Ring:       g.ring,
+		NextShrink: int(g.nextShrink() / time.Millisecond),
+		Elapsed:    int(g.elapsed / time.Millisecond),
+		Wrap:       g.wrap,
+		GameOver:   g.gameOver,
+		Won:        g.won,
+	}
+	// 竞技场开始时可能还没有蛇
+	if len(g.snakes) > 0 {
+		p := g.snakes[0]
+		s.Snake = append([]Point(nil), p.body...)
+		s.Direction, s.Score, s.Length = p.direction, p.score, p.length
+	}
+	for _, p := range g.snakes {
+		s.Snakes = append(s.Snakes, SnakeState{
+			Body:      append([]Point(nil), p.body...),
+			Direction: p.direction,
+			Score:     p.score,
+			Length:    p.length,
+			Alive:     p.alive,
+			Stats:     p.stats,
+		})
+	}
+	for _, r := range g.rounds {
+		r.Scores = append([]int(nil), r.Scores...)
+		s.Rounds = append(s.Rounds, r)
+	}
+	if lvl := g.currentLevel(); lvl != nil {
+		s.Level, s.Target = lvl.Name, lvl.Target
+	}
+	return s
+}
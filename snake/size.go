@@ -0,0 +1,88 @@
+package snake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================
+// 面板尺寸 - 预设和自适应终端
+// ============================================
+// 没有关卡的面板可以使用预设尺寸、指定的 WxH，或在开始时按终端大小自动计算（SizeAuto）。
+// 不同尺寸的成绩不可比较，经典尺寸之外的每个尺寸有自己的高分榜
+
+// BoardSize 面板尺寸预设
+type BoardSize struct {
+	Name          string
+	Width, Height int
+}
+
+// BoardSizes 面板尺寸预设，Classic 即 BoardWidth x BoardHeight
+var BoardSizes = []BoardSize{
+	{"classic", BoardWidth, BoardHeight},
+	{"small", 14, 10},
+	{"large", 30, 20},
+	{"huge", 40, 25},
+}
+
+const (
+	SizeAuto = "auto" // 按终端大小计算面板尺寸
+
+	// 面板尺寸的范围
+	MinBoardWidth  = 10
+	MinBoardHeight = 8
+	MaxBoardWidth  = 100
+	MaxBoardHeight = 50
+)
+
+// ParseBoardSize 解析面板尺寸：预设名称、"WxH" 或 SizeAuto，空字符串表示经典尺寸
+// cols 和 rows 是终端的大小，只用于 SizeAuto（为 0 时使用经典尺寸）
+func ParseBoardSize(spec string, cols, rows int) (int, int, error) {
+	if spec == "" {
+		return BoardWidth, BoardHeight, nil
+	}
+	if strings.EqualFold(spec, SizeAuto) {
+		if cols <= 0 || rows <= 0 {
+			return BoardWidth, BoardHeight, nil
+		}
+		w, h := FitBoard(cols, rows)
+		return w, h, nil
+	}
+	for _, s := range BoardSizes {
+		if strings.EqualFold(s.Name, spec) {
+			return s.Width, s.Height, nil
+		}
+	}
+	var w, h int
+	if n, err := fmt.Sscanf(strings.ToLower(spec), "%dx%d", &w, &h); err != nil || n != 2 ||
+		fmt.Sprintf("%dx%d", w, h) != strings.ToLower(spec) {
+		return 0, 0, fmt.Errorf("invalid board size %q (valid: %s, auto or WxH)", spec, strings.Join(BoardSizeNames(), ", "))
+	}
+	if w < MinBoardWidth || h < MinBoardHeight || w > MaxBoardWidth || h > MaxBoardHeight {
+		return 0, 0, fmt.Errorf("board size %dx%d out of range (%dx%d to %dx%d)",
+			w, h, MinBoardWidth, MinBoardHeight, MaxBoardWidth, MaxBoardHeight)
+	}
+	return w, h, nil
+}
+
+// BoardSizeNames 返回预设名称列表
+func BoardSizeNames() []string {
+	names := make([]string, len(BoardSizes))
+	for i, s := range BoardSizes {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// FitBoard 返回能完整显示在 cols x rows 终端中的最大面板尺寸（限制在允许的范围内）
+// 每个格子占两列，右侧留出信息面板
+func FitBoard(cols, rows int) (int, int) {
+	w := (cols - layoutMarginX - panelWidth) / 2
+	h := rows - layoutMarginY
+	return min(max(w, MinBoardWidth), MaxBoardWidth), min(max(h, MinBoardHeight), MaxBoardHeight)
+}
+
+// layoutSize 返回 width x height 的面板连同信息面板所需的终端大小
+func layoutSize(width, height int) (int, int) {
+	return width*2 + layoutMarginX + panelWidth, max(height+layoutMarginY, panelHeight)
+}
@@ -0,0 +1,120 @@
+package snake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+)
+
+func TestParseBoardSize(t *testing.T) {
+	fitW, fitH := FitBoard(120, 40)
+	tests := []struct {
+		spec       string
+		cols, rows int
+		w, h       int
+		wantErr    bool
+	}{
+		{spec: "", w: BoardWidth, h: BoardHeight},
+		{spec: "classic", w: BoardWidth, h: BoardHeight},
+		{spec: "Large", w: 30, h: 20},
+		{spec: "24x18", w: 24, h: 18},
+		{spec: "auto", cols: 120, rows: 40, w: fitW, h: fitH},
+		{spec: "auto", w: BoardWidth, h: BoardHeight},
+		{spec: "5x5", wantErr: true},
+		{spec: "500x20", wantErr: true},
+		{spec: "30x20x", wantErr: true},
+		{spec: "enormous", wantErr: true},
+	}
+	for _, tt := range tests {
+		w, h, err := ParseBoardSize(tt.spec, tt.cols, tt.rows)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBoardSize(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (w != tt.w || h != tt.h) {
+			t.Errorf("ParseBoardSize(%q, %d, %d) = %dx%d, want %dx%d", tt.spec, tt.cols, tt.rows, w, h, tt.w, tt.h)
+		}
+	}
+}
+
+// TestFitBoard 自动计算的面板连同信息面板正好放进终端
+func TestFitBoard(t *testing.T) {
+	for _, size := range [][2]int{{80, 24}, {120, 40}, {200, 60}, {95, 31}} {
+		w, h := FitBoard(size[0], size[1])
+		layoutW, layoutH := layoutSize(w, h)
+		if layoutW > size[0] || layoutH > size[1] {
+			t.Errorf("FitBoard(%d, %d) = %dx%d needs %dx%d", size[0], size[1], w, h, layoutW, layoutH)
+		}
+		if layoutW+2 <= size[0] && w < MaxBoardWidth {
+			t.Errorf("FitBoard(%d, %d) = %dx%d leaves columns unused", size[0], size[1], w, h)
+		}
+	}
+}
+
+// TestTableNameBySize 经典尺寸之外的空白面板有自己的高分榜
+func TestTableNameBySize(t *testing.T) {
+	tests := []struct {
+		game *Game
+		want string
+	}{
+		{NewGame(WithSeed(1), WithSize(BoardWidth, BoardHeight)), "snake"},
+		{NewGame(WithSeed(1), WithSize(30, 20)), "snake-30x20"},
+		{NewGame(WithSeed(1), WithSize(14, 10), WithWrap(true)), "snake-wrap-14x10"},
+	}
+	for _, tt := range tests {
+		if got := tt.game.tableName(); got != tt.want {
+			t.Errorf("tableName() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRenderSizeGolden(t *testing.T) {
+	tests := []struct {
+		name       string
+		cols, rows int
+		game       *Game
+	}{
+		{"render_large", 120, 30, NewGame(WithSeed(1), WithSize(30, 20))},
+		{"render_toosmall", 50, 12, NewGame(WithSeed(1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := screentest.NewScreen(t, tt.cols, tt.rows)
+			chaseFood(tt.game, 5)
+			NewRenderer(screen, tt.game, input.DefaultSnake()).Render()
+			screentest.AssertScreen(t, tt.name, screen)
+		})
+	}
+}
+
+// TestRunResizePauses 游戏中途终端变小时暂停并提示，变回原来的大小后可以继续
+func TestRunResizePauses(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	done := make(chan bool)
+	go func() {
+		time.Sleep(2 * SpeedNormal * time.Millisecond)
+		screen.SetSize(40, 10)
+		screen.PostEvent(tcell.NewEventResize(40, 10))
+		time.Sleep(20 * time.Millisecond)
+		small := screentest.Contains(screen, "Terminal too small")
+
+		// 恢复大小并继续：暂停状态要保留到玩家按下暂停键
+		screen.SetSize(screentest.Width, screentest.Height)
+		screen.PostEvent(tcell.NewEventResize(screentest.Width, screentest.Height))
+		time.Sleep(20 * time.Millisecond)
+		paused := screentest.Contains(screen, "PAUSED")
+		screentest.Type(screen, 5*time.Millisecond, screentest.Key(tcell.KeyEscape))
+		done <- small && paused
+	}()
+	if err := Run(screen, input.DefaultSnake(), Config{Seed: 1}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !<-done {
+		t.Errorf("expected the too-small notice and then a paused game:\n%s", screentest.Dump(screen))
+	}
+}
@@ -1,11 +1,207 @@
 package snake
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"go-game/daily"
+	"go-game/input"
+	"go-game/replay"
+	"go-game/scores"
 )
 
+// DailyGame 每日挑战中贪吃蛇的名称（用于计算种子和记录历史）
+const DailyGame = "snake"
+
+// ============================================
+// 启动参数
+// ============================================
+
+// Config 一局游戏的启动参数
+// 带 json 标签的字段由选项菜单保存，其余为一次性参数
+type Config struct {
+	Wrap       bool   `json:"wrap"`       // 穿墙模式
+	Level      string `json:"level"`      // 关卡名，LevelCampaign 表示战役，空表示空白面板（只用于单人游戏）
+	Players    int    `json:"players"`    // 玩家人数，0 和 1 都表示单人游戏，2 表示同一键盘上的双人对战
+	Opponents  int    `json:"opponents"`  // 电脑对手的数量
+	Autopilot  string `json:"autopilot"`  // 一号玩家由电脑控制（演示模式）时使用的控制器名称，空表示手动
+	Size       string `json:"size"`       // 空白面板的尺寸：预设名称、"WxH" 或 SizeAuto，空表示经典尺寸
+	Difficulty string `json:"difficulty"` // 难度名称（见 Difficulties），空表示普通难度
+	Constant   bool   `json:"constant"`   // 保持难度的初始速度，不随得分提速
+	Shrink     int    `json:"shrink"`     // 大逃杀：每隔多少秒缩小一圈，0 表示普通对战（只用于多条蛇）
+	Mode       string `json:"mode"`       // 单人游戏的模式（见 ParseMode），空表示无尽模式；挑战模式总是使用空白面板
+	Target     int    `json:"target"`     // 目标长度模式的目标，0 表示 DefaultLengthTarget
+	Seed       int64  `json:"-"`          // 随机种子，0 表示随机生成
+	Daily      string `json:"-"`          // 每日挑战的日期（见 daily.Today），非空时忽略其他选项，按日期的种子玩经典单人模式
+	Record     string `json:"-"`          // 录像保存路径，空表示不录制
+	Heatmap    string `json:"-"`          // 离开时把最后一局的热力图导出为 CSV 的路径，空表示不导出
+}
+
+// snakes 返回面板上蛇的总数（玩家加电脑对手，不超过 MaxSnakes）
+func (c Config) snakes() int {
+	return min(max(c.Players, 1)+c.Opponents, MaxSnakes)
+}
+
+// Validate 检查选项的组合：挑战模式和关卡只用于单人游戏，大逃杀至少需要两条蛇
+// 命令行和选项菜单使用同一个检查，菜单中保存的选项不会开始命令行拒绝的游戏
+func (c Config) Validate() error {
+	mode, err := ParseMode(c.Mode)
+	if err != nil {
+		return err
+	}
+	switch {
+	case mode != ModeEndless && (c.snakes() > 1 || c.Level != ""):
+		return fmt.Errorf("mode %s is a single-player challenge on an open board", mode)
+	case c.Level != "" && c.snakes() > 1:
+		return fmt.Errorf("level %q is single-player", c.Level)
+	case c.Shrink > 0 && c.snakes() < 2:
+		return errors.New("battle royale needs at least 2 snakes")
+	}
+	if c.Target != 0 {
+		return CheckTarget(c.Target)
+	}
+	return nil
+}
+
+// newGame 按配置创建游戏（多条蛇对战总是使用空白面板）
+// Size 为 SizeAuto 时需要先由 Run 按终端大小换算为 "WxH"
+func (c Config) newGame(seed int64) (*Game, error) {
+	width, height, err := ParseBoardSize(c.Size, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	difficulty, err := ParseDifficulty(c.Difficulty, c.Constant)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := ParseMode(c.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if c.Target != 0 {
+		if err := CheckTarget(c.Target); err != nil {
+			return nil, err
+		}
+	}
+	opts := []Option{
+		WithSeed(seed), WithWrap(c.Wrap), WithSize(width, height), WithPlayers(c.snakes()),
+		WithDifficulty(difficulty), WithMode(mode, c.Target),
+	}
+	if c.snakes() > 1 {
+		opts = append(opts, WithShrink(time.Duration(c.Shrink)*time.Second))
+	} else if mode == ModeEndless {
+		levels, err := FindLevels(c.Level)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithLevels(levels...))
+	}
+	if c.Daily != "" {
+		opts = append(opts, WithFixedSeed())
+	}
+	return NewGame(opts...), nil
+}
+
+// pilots 按配置为每条蛇创建控制器，玩家手动控制的蛇为 nil
+// 自动演示时一号玩家由 Autopilot 控制，玩家之后的蛇都是电脑对手
+func (c Config) pilots() ([]Controller, error) {
+	pilots := make([]Controller, c.snakes())
+	if c.Autopilot != "" {
+		ctrl, err := NewController(c.Autopilot)
+		if err != nil {
+			return nil, err
+		}
+		pilots[0] = ctrl
+	}
+	for i := max(c.Players, 1); i < c.snakes(); i++ {
+		ctrl, err := NewController(OpponentController)
+		if err != nil {
+			return nil, err
+		}
+		pilots[i] = ctrl
+	}
+	return pilots, nil
+}
+
+// apply 执行一个会改变游戏状态的动作（录像中的动作名）
+// Run 和 Replay 共用这一入口，保证录像回放与实际对局完全一致
+func apply(game *Game, action string) {
+	switch action {
+	case replay.Tick:
+		game.move()
+	case input.Restart.String():
+		game.reset()
+	case input.Boost.String():
+		game.Boost()
+	default:
+		// 方向控制（防止快速反向导致自杀）
+		if i, d, ok := parseTurn(action); ok {
+			game.TurnPlayer(i, d)
+		}
+	}
+}
+
+// tableName 返回当前模式对应的高分榜名称
+// 例如 "snake"、"snake-wrap"、"snake-campaign"、"snake-wrap-pillars"、"snake-30x20"、"snake-hard"、"snake-length-30"
+// 经典尺寸之外的空白面板按尺寸区分，普通难度之外按难度和恒定速度区分，挑战模式按模式和目标区分
+func (g *Game) tableName() string {
+	name := "snake"
+	if g.wrap {
+		name += "-wrap"
+	}
+	name += g.modeSuffix()
+	switch {
+	case len(g.levels) > 1:
+		name += "-" + LevelCampaign
+	case len(g.levels) == 1:
+		name += "-" + strings.ToLower(strings.ReplaceAll(g.levels[0].Name, " ", "-"))
+	case g.width != BoardWidth || g.height != BoardHeight:
+		name += fmt.Sprintf("-%dx%d", g.width, g.height)
+	}
+	return name + g.difficulty.tableSuffix()
+}
+
+// submitScore 游戏结束时提交成绩，返回是否刷新了最好成绩
+// 目标长度模式只记录完成的对局，按用时排名
+func (g *Game) submitScore(seed int64) bool {
+	p := g.snakes[0]
+	var rank int
+	if g.mode == ModeLength {
+		if !g.won {
+			return false
+		}
+		rank, _ = scores.Submit(g.tableName(), scores.LowerIsBetter, scores.Entry{
+			Value:  g.elapsed.Milliseconds(),
+			Label:  formatTenths(g.elapsed),
+			Detail: fmt.Sprintf("SCORE %d", p.score),
+			Seed:   seed,
+		})
+	} else {
+		rank, _ = scores.Submit(g.tableName(), scores.HigherIsBetter, scores.Entry{
+			Value:  int64(p.score),
+			Label:  fmt.Sprint(p.score),
+			Detail: fmt.Sprintf("LENGTH %d", len(p.body)),
+			Seed:   seed,
+		})
+	}
+	return rank == 1
+}
+
+// bestScore 读取当前模式高分榜的最好成绩（目标长度模式为用时的毫秒数），没有记录时返回 0
+// 经典模式、穿墙模式、每个关卡/战役和每种挑战各有一张高分榜
+func (g *Game) bestScore() int {
+	table, err := scores.Get(g.tableName())
+	if err != nil {
+		return 0
+	}
+	best, _ := table.Best()
+	return int(best.Value)
+}
+
 // ============================================
 // 游戏入口 - 主循环
 // ============================================
@@ -16,43 +212,183 @@ import (
 // 2. 根据时间间隔自动移动蛇
 // 3. 渲染游戏画面
 //
-// 输入处理：
-// - 方向键：控制蛇的移动方向（防止快速反向）
-// - P 键：暂停/继续游戏
-// - R 键：游戏结束时重新开始
-// - Esc 键：返回主菜单
-func Run(screen tcell.Screen) {
-	game := NewGame(screen)
-	renderer := NewRenderer(screen, game)
-	game.spawnFood()
+// 输入处理（按键由 keys 映射为动作，默认按键见 input.DefaultSnake）：
+// - MoveUp/Down/Left/Right：控制蛇的移动方向（防止快速反向）
+// - Boost：单人游戏中加速（按住时移动加快、得分翻倍）
+// - P2MoveUp/Down/Left/Right：双人模式下控制二号玩家的蛇
+// - Pause：暂停/继续游戏
+// - Restart：重新开始
+// - Heatmap：游戏结束后显示/隐藏热力图
+// - Back：返回主菜单（设置了 cfg.Heatmap 时导出最后一局的热力图）
+//
+// 返回关卡加载、每日成绩和统计保存、录像保存或热力图导出时的第一个错误
+func Run(screen tcell.Screen, keys *input.Keymap, cfg Config) error {
+	// 每日挑战：所有人使用同样的设置和当天的种子
+	if cfg.Daily != "" {
+		cfg = Config{Daily: cfg.Daily, Seed: daily.Seed(DailyGame, cfg.Daily), Record: cfg.Record, Heatmap: cfg.Heatmap}
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	// 按终端大小确定面板尺寸，录像中记录实际尺寸
+	cols, rows := screen.Size()
+	width, height, err := ParseBoardSize(cfg.Size, cols, rows)
+	if err != nil {
+		return err
+	}
+	cfg.Size = ""
+	if width != BoardWidth || height != BoardHeight {
+		cfg.Size = fmt.Sprintf("%dx%d", width, height)
+	}
+	game, err := cfg.newGame(seed)
+	if err != nil {
+		return err
+	}
+	pilots, err := cfg.pilots()
+	if err != nil {
+		return err
+	}
+	// 多人对战和自动演示不计入排行榜
+	versus := game.players > 1
+	demo := pilots[0] != nil
+	renderer := NewRenderer(screen, game, keys)
+	for _, ctrl := range pilots {
+		renderer.cpu = append(renderer.cpu, ctrl != nil)
+	}
+	if !versus {
+		renderer.best = game.bestScore()
+	}
+	// 每日挑战当天已经有成绩时都是练习，信息面板显示当天的成绩
+	practice := false
+	if cfg.Daily != "" {
+		today, ok, err := daily.Get(DailyGame, cfg.Daily)
+		if err != nil {
+			return err
+		}
+		practice = ok
+		renderer.daily, renderer.practice, renderer.best = cfg.Daily, ok, int(today.Value)
+	}
+	// 终端放不下时先暂停，调整大小后按暂停键继续
+	game.paused = renderer.TooSmall()
 	renderer.Render()
 
+	// 录像：记录本次会话中所有改变状态的动作
+	rec := replay.New("snake", seed)
+	rec.Wrap = cfg.Wrap
+	rec.Level = cfg.Level
+	rec.Size = cfg.Size
+	rec.Difficulty, rec.Constant = cfg.Difficulty, cfg.Constant
+	rec.Daily = cfg.Daily
+	if !versus {
+		rec.Mode, rec.Target = cfg.Mode, cfg.Target
+	}
+	if versus {
+		rec.Level, rec.Players, rec.Shrink = "", game.players, cfg.Shrink
+	}
+	var played time.Duration // 会话总游戏时间（不含暂停），用作录像时间轴
+
+	// saveErr 游戏过程中保存每日成绩或累计统计失败的原因，离开时由 Run 返回
+	var saveErr error
+	keep := func(err error) {
+		if saveErr == nil {
+			saveErr = err
+		}
+	}
+
+	// finish 结束每日挑战当天计分的一局：记录得分，之后的对局都是练习
+	// 游戏结束、中途重新开始和中途离开都会用掉这次机会（还没有开始移动时不算）
+	finish := func() error {
+		if cfg.Daily == "" || practice || game.elapsed == 0 {
+			return nil
+		}
+		practice = true
+		p := game.snakes[0]
+		_, err := daily.Record(DailyGame, daily.Result{
+			Date:   cfg.Daily,
+			Value:  int64(p.score),
+			Label:  fmt.Sprint(p.score),
+			Detail: fmt.Sprintf("LENGTH %d", len(p.body)),
+		})
+		renderer.practice, renderer.record, renderer.best = true, game.gameOver && err == nil, p.score
+		return err
+	}
+	// save 离开时保存所有结果，返回第一个错误（包括游戏过程中的）
+	save := func() error {
+		keep(finish())
+		if cfg.Heatmap != "" {
+			keep(writeHeatmap(cfg.Heatmap, game.Heatmap()))
+		}
+		if cfg.Record != "" {
+			keep(rec.Save(cfg.Record))
+		}
+		return saveErr
+	}
+
+	// do 执行并记录一个动作，单人游戏刚结束时提交成绩并累计统计（多人对战只记入记分板）
+	// 每日挑战的成绩记入每日历史而不是高分榜（练习可以无限重玩同一个种子）
+	do := func(action string) {
+		wasOver := game.gameOver
+		if !wasOver && action == input.Restart.String() {
+			keep(finish())
+		}
+		apply(game, action)
+		rec.Record(played, action)
+		if !wasOver && game.gameOver && !versus && !demo {
+			if cfg.Daily != "" {
+				keep(finish())
+			} else {
+				renderer.record = game.submitScore(seed)
+				renderer.best = game.bestScore()
+			}
+			keep(game.recordLifetime())
+		}
+	}
+
 	lastMove := time.Now()
+	lastFrame := time.Now()
 
 	for {
+		// 累计游戏时间
+		now := time.Now()
+		if !game.gameOver && !game.paused {
+			played += now.Sub(lastFrame)
+		}
+		lastFrame = now
+
 		// ---------- 处理用户输入 ----------
 		if screen.HasPendingEvent() {
 			event := screen.PollEvent()
 			if event != nil {
 				switch ev := event.(type) {
 				case *tcell.EventKey:
+					action, ok := keys.Lookup(ev)
+					if !ok {
+						continue
+					}
+
 					// 返回主菜单
-					if ev.Key() == tcell.KeyEscape {
-						return
+					if action == input.Back {
+						return save()
 					}
 
-					// 游戏结束时的操作
+					// 游戏结束时的操作：重新开始，或显示/隐藏热力图
 					if game.gameOver {
-						if ev.Rune() == 'r' || ev.Rune() == 'R' {
-							game.reset()
+						switch action {
+						case input.Restart:
+							renderer.heatmap, renderer.record = false, false
+							do(action.String())
+							renderer.Render()
+						case input.Heatmap:
+							renderer.heatmap = !renderer.heatmap
 							renderer.Render()
 						}
 						continue
 					}
 
 					// 暂停/继续
-					if ev.Rune() == 'p' || ev.Rune() == 'P' {
-						game.paused = !game.paused
+					if action == input.Pause {
+						game.paused = !game.paused || renderer.TooSmall()
 						renderer.Render()
 						continue
 					}
@@ -60,32 +396,30 @@ func Run(screen tcell.Screen) {
 						continue
 					}
 
-					// 方向控制（防止快速反向导致自杀）
-					switch ev.Key() {
-					case tcell.KeyUp:
-						if game.direction != Down {
-							game.nextDir = Up
-						}
-					case tcell.KeyDown:
-						if game.direction != Up {
-							game.nextDir = Down
+					switch action {
+					case input.MoveUp, input.MoveDown, input.MoveLeft, input.MoveRight:
+						if !demo {
+							do(action.String())
 						}
-					case tcell.KeyLeft:
-						if game.direction != Right {
-							game.nextDir = Left
+					case input.Boost:
+						if !demo && !versus {
+							do(action.String())
 						}
-					case tcell.KeyRight:
-						if game.direction != Left {
-							game.nextDir = Right
-						}
-					case tcell.KeyRune:
-						if ev.Rune() == 'r' || ev.Rune() == 'R' {
-							game.reset()
+					case input.Restart:
+						do(action.String())
+					case input.P2MoveUp, input.P2MoveDown, input.P2MoveLeft, input.P2MoveRight:
+						if cfg.Players > 1 {
+							do(action.String())
 						}
 					}
 					renderer.Render()
 
 				case *tcell.EventResize:
+					// 游戏中途终端变得放不下时暂停
+					if renderer.TooSmall() && !game.gameOver {
+						game.paused = true
+					}
+					screen.Sync()
 					renderer.Render()
 				}
 			}
@@ -93,11 +427,107 @@ func Run(screen tcell.Screen) {
 
 		// ---------- 自动移动 ----------
 		if !game.gameOver && !game.paused && time.Since(lastMove) > time.Duration(game.getSpeed())*time.Millisecond {
-			game.move()
+			// 电脑控制的蛇在移动前选择方向，转向和玩家的操作一样记入录像
+			state := game.State()
+			for i, ctrl := range pilots {
+				if s := state.Snakes[i]; ctrl != nil && s.Alive {
+					if d := ctrl.Next(state, i); d != s.Direction {
+						do(turnAction(i, d))
+					}
+				}
+			}
+			do(replay.Tick)
 			renderer.Render()
 			lastMove = time.Now()
-		} else if !game.gameOver && !game.paused {
+		} else {
 			time.Sleep(10 * time.Millisecond)
 		}
 	}
 }
+
+// ============================================
+// 录像回放
+// ============================================
+// Replay 按录制时的节奏回放一段贪吃蛇录像
+//
+// 回放时只响应以下动作：
+// - Pause: 暂停/继续回放
+// - Heatmap: 录像中的一局结束后显示/隐藏热力图
+// - Back: 结束回放
+func Replay(screen tcell.Screen, keys *input.Keymap, rp *replay.Replay) error {
+	cfg := Config{
+		Daily: rp.Daily, Wrap: rp.Wrap, Level: rp.Level, Players: rp.Players, Size: rp.Size, Shrink: rp.Shrink,
+		Difficulty: rp.Difficulty, Constant: rp.Constant, Mode: rp.Mode, Target: rp.Target,
+	}
+	game, err := cfg.newGame(rp.Seed)
+	if err != nil {
+		return err
+	}
+	renderer := NewRenderer(screen, game, keys)
+	renderer.replay = true
+	renderer.Render()
+
+	player := replay.NewPlayer(rp)
+	var played time.Duration
+	lastFrame := time.Now()
+
+	for {
+		// 推进回放时间轴
+		now := time.Now()
+		if !game.paused {
+			played += now.Sub(lastFrame)
+		}
+		lastFrame = now
+
+		// ---------- 处理用户输入 ----------
+		for screen.HasPendingEvent() {
+			switch ev := screen.PollEvent().(type) {
+			case *tcell.EventKey:
+				action, _ := keys.Lookup(ev)
+				switch action {
+				case input.Back:
+					return nil
+				case input.Pause:
+					game.paused = !game.paused || renderer.TooSmall()
+				case input.Heatmap:
+					renderer.heatmap = game.gameOver && !renderer.heatmap
+				}
+			case *tcell.EventResize:
+				if renderer.TooSmall() {
+					game.paused = true
+				}
+				screen.Sync()
+			case nil:
+				return nil
+			}
+			renderer.Render()
+		}
+
+		// ---------- 执行到期的动作 ----------
+		if due := player.Due(played); len(due) > 0 {
+			for _, ev := range due {
+				apply(game, ev.Action)
+			}
+			if !game.gameOver {
+				renderer.heatmap = false // 录像中重新开始了新的一局
+			}
+			renderer.finished = player.Done()
+			renderer.Render()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeHeatmap 把热力图以 CSV 格式写入 path
+func writeHeatmap(path string, heat Heatmap) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := heat.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
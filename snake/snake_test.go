@@ -0,0 +1,113 @@
+package snake
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+	"go-game/replay"
+)
+
+// TestRunScriptedSession 通过模拟按键完整地玩一段，再用录像复现同一画面
+func TestRunScriptedSession(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	path := filepath.Join(t.TempDir(), "session.json")
+	keys := input.DefaultSnake()
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	screentest.Type(screen, 5*time.Millisecond,
+		screentest.Key(tcell.KeyLeft),
+		screentest.Rune('j'),
+		screentest.Rune('p'),
+		screentest.Key(tcell.KeyEscape),
+	)
+	if err := Run(screen, keys, Config{Seed: 42, Record: path}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !screentest.Contains(screen, "PAUSED") {
+		t.Errorf("last frame should show PAUSED:\n%s", screentest.Dump(screen))
+	}
+
+	rp, err := replay.Load(path)
+	if err != nil {
+		t.Fatalf("load replay: %v", err)
+	}
+	if rp.Game != "snake" || rp.Seed != 42 || rp.Wrap {
+		t.Errorf("replay header = %q seed %d wrap %v", rp.Game, rp.Seed, rp.Wrap)
+	}
+
+	// 录像中的动作重新执行后，画面应与实际对局的最后一帧完全一致
+	g := NewGame(WithSeed(rp.Seed), WithWrap(rp.Wrap))
+	for _, ev := range rp.Events {
+		apply(g, ev.Action)
+	}
+	g.paused = true
+	want := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewRenderer(want, g, keys).Render()
+	if got, want := screentest.Dump(screen), screentest.Dump(want); got != want {
+		t.Errorf("replayed frame differs from the live one:\nlive:\n%s\nreplayed:\n%s", got, want)
+	}
+}
+
+// TestRunRestartAfterGameOver 撞墙后按 R 重新开始
+func TestRunRestartAfterGameOver(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+
+	// 蛇从中间向上移动，BoardHeight/2+1 步后撞墙；等待足够时间后重开并退出
+	wait := time.Duration(BoardHeight/2+3) * SpeedNormal * time.Millisecond
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	go func() {
+		time.Sleep(wait)
+		screentest.Type(screen, 5*time.Millisecond, screentest.Rune('r'), screentest.Rune('p'), screentest.Key(tcell.KeyEscape))
+	}()
+	if err := Run(screen, input.DefaultSnake(), Config{Seed: 1}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if screentest.Contains(screen, "GAME OVER") || !screentest.Contains(screen, "SCORE: 0") {
+		t.Errorf("expected a fresh game after restart:\n%s", screentest.Dump(screen))
+	}
+
+	// 结束的一局计入累计统计
+	if l, err := LoadLifetime(); err != nil || l.Games != 1 || l.Deaths["border"] != 1 {
+		t.Errorf("lifetime = %+v, %v; want one game lost at the border", l, err)
+	}
+}
+
+// TestRunAutopilot 自动演示时电脑的转向记入录像，玩家的方向键不起作用
+func TestRunAutopilot(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	RegisterController("stub", func() Controller { return stubController(Left) })
+	t.Cleanup(func() { delete(controllers, "stub") })
+	path := filepath.Join(t.TempDir(), "demo.json")
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	go func() {
+		screentest.Type(screen, 5*time.Millisecond, screentest.Key(tcell.KeyRight))
+		time.Sleep(3 * SpeedNormal * time.Millisecond)
+		screentest.Type(screen, 5*time.Millisecond, screentest.Rune('p'), screentest.Key(tcell.KeyEscape))
+	}()
+	if err := Run(screen, input.DefaultSnake(), Config{Seed: 1, Autopilot: "stub", Record: path}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !screentest.Contains(screen, "SNAKE DEMO") {
+		t.Errorf("title should mark the demo:\n%s", screentest.Dump(screen))
+	}
+
+	rp, err := replay.Load(path)
+	if err != nil {
+		t.Fatalf("load replay: %v", err)
+	}
+	var turns []string
+	for _, ev := range rp.Events {
+		if ev.Action != replay.Tick {
+			turns = append(turns, ev.Action)
+		}
+	}
+	if len(turns) != 1 || turns[0] != Left.String() {
+		t.Errorf("recorded turns = %v, want only the autopilot's %v", turns, Left)
+	}
+}
@@ -0,0 +1,253 @@
+package snake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/internal/store"
+)
+
+// ============================================
+// 统计 - 每局的统计和累计统计
+// ============================================
+// 每条蛇在移动过程中记录本局的统计（见 player.stats），游戏结束时显示在结果面板上。
+// 单人游戏（非自动演示）结束时把本局统计累加到存档中的累计统计，可以从主菜单查看
+
+// Death 蛇撞死的原因
+type Death int
+
+const (
+	DeathNone   Death = iota // 还活着
+	DeathBorder              // 撞上面板边界
+	DeathSelf                // 撞上自己的身体
+	DeathSnake               // 撞上其他蛇的身体（Stats.Killer 为那条蛇的下标）
+	DeathHeadOn              // 与其他蛇迎头相撞（Stats.Killer 为那条蛇的下标）
+	DeathWall                // 撞上关卡中的墙
+	DeathPortal              // 传送门的出口被挡住
+	DeathHazard              // 撞上巡逻障碍，或被巡逻障碍撞上
+	DeathZone                // 大逃杀中撞上已关闭的区域，或被缩圈压在圈上
+)
+
+// deathInfos 撞死原因的存档名称和显示文字，下标为 Death
+var deathInfos = [...]struct {
+	key, text string
+}{
+	DeathNone:   {"none", "Still alive"},
+	DeathBorder: {"border", "Hit the border"},
+	DeathSelf:   {"self", "Ran into itself"},
+	DeathSnake:  {"snake", "Ran into a snake"},
+	DeathHeadOn: {"head-on", "Head-on collision"},
+	DeathWall:   {"wall", "Hit a wall"},
+	DeathPortal: {"portal", "Portal exit blocked"},
+	DeathHazard: {"hazard", "Hit a hazard"},
+	DeathZone:   {"zone", "Caught by the zone"},
+}
+
+// String 返回撞死原因的显示文字，例如 "Hit a wall"
+func (d Death) String() string {
+	if int(d) < len(deathInfos) {
+		return deathInfos[d].text
+	}
+	return "Unknown"
+}
+
+// Stats 一条蛇一局的统计（关卡之间累计，重新开始时清零）
+type Stats struct {
+	Ticks     int           // 存活的步数
+	Time      time.Duration // 存活的游戏时间：每步按当时的移动间隔累计
+	Foods     int           // 吃到的食物数（所有种类）
+	MaxLength int           // 最长的长度
+	Turns     int           // 转向次数
+	Death     Death         // 撞死的原因，活着时为 DeathNone
+	Killer    int           // DeathSnake 和 DeathHeadOn 时撞上的蛇的下标
+}
+
+// TicksPerFood 平均每吃到一个食物所用的步数，还没吃到食物时为 0
+func (s Stats) TicksPerFood() float64 {
+	if s.Foods == 0 {
+		return 0
+	}
+	return float64(s.Ticks) / float64(s.Foods)
+}
+
+// deathCause 判断第 i 条蛇撞死的原因，检查顺序与 collides 一致
+func (g *Game) deathCause(i int, heads []Point) (Death, int) {
+	head := heads[i]
+	if head.X < 0 || head.X >= g.width || head.Y < 0 || head.Y >= g.height {
+		return DeathBorder, -1
+	}
+	if g.hazardAt(head) {
+		return DeathHazard, -1
+	}
+	for j, q := range g.snakes {
+		if j != i && q.alive && heads[j] == head {
+			return DeathHeadOn, j
+		}
+	}
+	switch g.board[head.Y][head.X] {
+	case cellWall:
+		if g.mask[head.Y][head.X] {
+			return DeathZone, -1
+		}
+		return DeathWall, -1
+	case cellPortal:
+		return DeathPortal, -1
+	}
+	for j, q := range g.snakes {
+		if slices.Contains(q.body, head) {
+			if j == i {
+				return DeathSelf, -1
+			}
+			return DeathSnake, j
+		}
+	}
+	return DeathNone, -1
+}
+
+// ============================================
+// 累计统计
+// ============================================
+
+// lifetimeFile 累计统计在存档目录中的文件名
+const lifetimeFile = "snake-stats.json"
+
+// Lifetime 所有单人游戏的累计统计
+type Lifetime struct {
+	Games      int            `json:"games"`
+	Wins       int            `json:"wins"`
+	Score      int            `json:"score"` // 总得分
+	BestScore  int            `json:"best_score"`
+	Ticks      int            `json:"ticks"`
+	Time       time.Duration  `json:"time"`
+	Foods      int            `json:"foods"`
+	Turns      int            `json:"turns"`
+	BestLength int            `json:"best_length"`
+	Deaths     map[string]int `json:"deaths"` // 撞死原因的存档名称 -> 次数
+}
+
+// LoadLifetime 读取累计统计，还没有玩过时返回零值
+func LoadLifetime() (Lifetime, error) {
+	var l Lifetime
+	if err := store.Load(lifetimeFile, &l); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Lifetime{}, err
+	}
+	return l, nil
+}
+
+// add 累加一局的统计
+func (l *Lifetime) add(s Stats, score int, won bool) {
+	l.Games++
+	if won {
+		l.Wins++
+	}
+	l.Score += score
+	l.BestScore = max(l.BestScore, score)
+	l.Ticks += s.Ticks
+	l.Time += s.Time
+	l.Foods += s.Foods
+	l.Turns += s.Turns
+	l.BestLength = max(l.BestLength, s.MaxLength)
+	if s.Death != DeathNone {
+		if l.Deaths == nil {
+			l.Deaths = map[string]int{}
+		}
+		l.Deaths[deathInfos[s.Death].key]++
+	}
+}
+
+// recordLifetime 把一号玩家这一局的统计累加到存档
+func (g *Game) recordLifetime() error {
+	l, err := LoadLifetime()
+	if err != nil {
+		return err
+	}
+	p := g.snakes[0]
+	l.add(p.stats, p.score, g.won)
+	return store.Save(lifetimeFile, l)
+}
+
+// ============================================
+// StatsScreen - 累计统计界面
+// ============================================
+// 从主菜单进入，按 Esc / Enter / Q 返回
+
+type StatsScreen struct {
+	screen   tcell.Screen
+	lifetime Lifetime
+	err      error // 读取存档失败的原因，界面上会显示出来
+}
+
+// NewStatsScreen 读取累计统计并创建界面
+func NewStatsScreen(screen tcell.Screen) *StatsScreen {
+	l, err := LoadLifetime()
+	return &StatsScreen{screen: screen, lifetime: l, err: err}
+}
+
+// Render 绘制累计统计：左列是总数，右列是各种撞死原因的次数
+func (s *StatsScreen) Render() {
+	s.screen.Clear()
+	s.screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack))
+
+	titleStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true)
+	normalStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	hintStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+
+	drawText(s.screen, 10, 3, "SNAKE STATISTICS", titleStyle)
+
+	l := s.lifetime
+	pace := Stats{Ticks: l.Ticks, Foods: l.Foods}.TicksPerFood()
+	rows := []string{
+		fmt.Sprintf("%-14s %d", "GAMES PLAYED", l.Games),
+		fmt.Sprintf("%-14s %d", "GAMES WON", l.Wins),
+		fmt.Sprintf("%-14s %d", "TOTAL SCORE", l.Score),
+		fmt.Sprintf("%-14s %d", "BEST SCORE", l.BestScore),
+		fmt.Sprintf("%-14s %s", "TIME PLAYED", formatTime(l.Time)),
+		fmt.Sprintf("%-14s %d", "FOOD EATEN", l.Foods),
+		fmt.Sprintf("%-14s %.1f", "TICKS/FOOD", pace),
+		fmt.Sprintf("%-14s %d", "LONGEST SNAKE", l.BestLength),
+		fmt.Sprintf("%-14s %d", "TURNS", l.Turns),
+	}
+	for i, row := range rows {
+		drawText(s.screen, 8, 6+i, row, normalStyle)
+	}
+
+	drawText(s.screen, 40, 6, "CAUSES OF DEATH", normalStyle)
+	y := 8
+	for d := DeathBorder; int(d) < len(deathInfos); d++ {
+		if n := l.Deaths[deathInfos[d].key]; n > 0 {
+			drawText(s.screen, 40, y, fmt.Sprintf("%-20s %d", d, n), normalStyle)
+			y++
+		}
+	}
+	if y == 8 {
+		drawText(s.screen, 40, y, "None yet", hintStyle)
+	}
+
+	if s.err != nil {
+		drawText(s.screen, 8, 8+len(rows), "Could not read statistics: "+s.err.Error(), normalStyle)
+	}
+	drawText(s.screen, 8, 10+len(rows), "Esc : Back", hintStyle)
+
+	s.screen.Show()
+}
+
+// Run 显示界面直到玩家返回
+func (s *StatsScreen) Run() {
+	s.Render()
+	for {
+		switch ev := s.screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyEnter || ev.Rune() == 'q' || ev.Rune() == 'Q' {
+				return
+			}
+		case *tcell.EventResize:
+			s.Render()
+		case nil:
+			return
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package snake
+
+import (
+	"testing"
+	"time"
+
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+)
+
+// TestDeathCauses 每种撞死方式都记录正确的原因
+func TestDeathCauses(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T) *Game
+		i      int
+		want   Death
+		killer int
+	}{
+		{"border", func(t *testing.T) *Game {
+			g := NewGame(WithSeed(1))
+			place(g, 0, Up, Point{5, 0}, Point{5, 1}, Point{5, 2})
+			return g
+		}, 0, DeathBorder, -1},
+		{"self", func(t *testing.T) *Game {
+			g := NewGame(WithSeed(1))
+			place(g, 0, Up, Point{5, 5}, Point{6, 5}, Point{6, 4}, Point{5, 4}, Point{4, 4})
+			return g
+		}, 0, DeathSelf, -1},
+		{"wall", func(t *testing.T) *Game {
+			return NewGame(WithSeed(1), WithLevels(testLevel(t, "..#..\n..^..\n.....\n.....\n")))
+		}, 0, DeathWall, -1},
+		{"portal", func(t *testing.T) *Game {
+			return NewGame(WithSeed(1), WithLevels(testLevel(t, ".....\n..1..\n..^..\n.....\n2...2\n1....\n")))
+		}, 0, DeathPortal, -1},
+		{"hazard", func(t *testing.T) *Game {
+			return NewGame(WithSeed(1), WithLevels(testLevel(t, "hazard-period: 100\n..=..\n..^..\n.....\n.....\n")))
+		}, 0, DeathHazard, -1},
+		{"snake", func(t *testing.T) *Game {
+			g := versusGame()
+			place(g, 0, Up, Point{5, 6}, Point{5, 7}, Point{5, 8})
+			place(g, 1, Right, Point{6, 5}, Point{5, 5}, Point{4, 5})
+			return g
+		}, 0, DeathSnake, 1},
+		{"head-on", func(t *testing.T) *Game {
+			g := versusGame()
+			place(g, 0, Right, Point{5, 5}, Point{4, 5}, Point{3, 5})
+			place(g, 1, Left, Point{7, 5}, Point{8, 5}, Point{9, 5})
+			return g
+		}, 1, DeathHeadOn, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.setup(t)
+			if len(g.snakes) == 1 {
+				setFood(g, Point{g.width - 1, g.height - 1})
+			}
+			g.Step()
+			s := g.State().Snakes[tt.i]
+			if s.Alive || s.Stats.Death != tt.want || s.Stats.Killer != tt.killer {
+				t.Errorf("alive %v death %v killer %d, want %v %d", s.Alive, s.Stats.Death, s.Stats.Killer, tt.want, tt.killer)
+			}
+		})
+	}
+}
+
+// TestStatsTracking 存活步数、时间、食物、最长长度和转向次数
+func TestStatsTracking(t *testing.T) {
+	g := NewGame(WithSeed(1))
+	head := g.snakes[0].head()
+	setFood(g, Point{head.X - 1, head.Y - 1})
+	g.Step()      // 向上
+	g.Step(Left)  // 吃到苹果
+	g.Step(Down)  // 转向
+	g.Step(Right) // 转向
+
+	s := g.State().Snakes[0].Stats
+	if s.Ticks != 4 || s.Foods != 1 || s.Turns != 3 || s.MaxLength != startLength+1 || s.Death != DeathNone {
+		t.Errorf("stats = %+v", s)
+	}
+	// 吃到苹果后速度略有提高，时间按每步的移动间隔累计
+	if want := time.Duration(2*SpeedNormal+2*(SpeedNormal-2)) * time.Millisecond; s.Time != want {
+		t.Errorf("time = %v, want %v", s.Time, want)
+	}
+	if s.TicksPerFood() != 4 {
+		t.Errorf("ticks per food = %v, want 4", s.TicksPerFood())
+	}
+
+	g.reset()
+	if s := g.State().Snakes[0].Stats; s.Ticks != 0 || s.Foods != 0 || s.Turns != 0 {
+		t.Errorf("stats after reset = %+v", s)
+	}
+}
+
+// TestStatsCarryAcrossLevels 换关时统计保留
+func TestStatsCarryAcrossLevels(t *testing.T) {
+	first := testLevel(t, "name: One\ntarget: 4\n......\n..^...\n......\n......\n")
+	second := testLevel(t, "name: Two\n#.......\n#....<..\n#.......\n")
+	g := NewGame(WithSeed(1), WithLevels(first, second))
+	setFood(g, Point{2, 0})
+	g.Step()
+	if s := g.State(); s.Level != "Two" || s.Snakes[0].Stats.Foods != 1 || s.Snakes[0].Stats.Ticks != 1 {
+		t.Errorf("after the first level: %s %+v", s.Level, s.Snakes[0].Stats)
+	}
+}
+
+// TestLifetime 每局结束后累加到存档，再次读取得到相同的结果
+func TestLifetime(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	if l, err := LoadLifetime(); err != nil || l.Games != 0 {
+		t.Fatalf("LoadLifetime() = %+v, %v", l, err)
+	}
+
+	for _, score := range []int{30, 50} {
+		g := NewGame(WithSeed(1))
+		place(g, 0, Up, Point{5, 0}, Point{5, 1}, Point{5, 2})
+		g.snakes[0].score = score
+		g.snakes[0].stats.Foods = score / 10
+		g.Step()
+		if err := g.recordLifetime(); err != nil {
+			t.Fatalf("recordLifetime: %v", err)
+		}
+	}
+
+	l, err := LoadLifetime()
+	if err != nil {
+		t.Fatalf("LoadLifetime: %v", err)
+	}
+	if l.Games != 2 || l.Score != 80 || l.BestScore != 50 || l.Foods != 8 || l.Deaths["border"] != 2 {
+		t.Errorf("lifetime = %+v", l)
+	}
+}
+
+func TestStatsScreenGolden(t *testing.T) {
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	s := &StatsScreen{screen: screen, lifetime: Lifetime{
+		Games: 12, Wins: 1, Score: 1230, BestScore: 320, Ticks: 1800, Time: 4*time.Minute + 30*time.Second,
+		Foods: 120, Turns: 456, BestLength: 32,
+		Deaths: map[string]int{"border": 5, "self": 4, "wall": 2},
+	}}
+	s.Render()
+	screentest.AssertScreen(t, "stats", screen)
+}
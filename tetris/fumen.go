@@ -0,0 +1,487 @@
+package tetris
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ============================================
+// Fumen - 社区通用的面板分享格式
+// ============================================
+// fumen 字符串形如 "v115@vhAAgH"：版本前缀之后是 64 进制编码的若干页，
+// 每页依次是面板（与上一页的差异，游程编码）、当前方块（形状、朝向、位置和标志位）
+// 以及可选的注释。这里只读写第一页，只支持 v115 版本
+//
+// fumen 的面板有 23 行可见区域和底部一行垃圾行，y 从下往上数；本游戏的面板
+// 对应其中最下面的 BoardHeight 行，垃圾行忽略。方块位置是 SRS 旋转中心的坐标
+
+// fumenTable 64 进制编码使用的字符，值为下标，多位数字低位在前
+const fumenTable = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// fumen 面板的尺寸
+const (
+	fumenTop    = 23                          // 可见区域的行数
+	fumenBlocks = (fumenTop + 1) * BoardWidth // 含垃圾行的格子数
+)
+
+// fumenTypes fumen 的方块编号，下标为形状索引（0 空白，8 灰色）
+var fumenTypes = []int{1, 3, 5, 7, 4, 6, 2}
+
+// fumenGray fumen 中灰色方块的编号
+const fumenGray = 8
+
+// fumen 的方块朝向编号
+const (
+	fumenReverse = 0 // 倒转 180 度
+	fumenRight   = 1 // 顺时针 90 度
+	fumenSpawn   = 2 // 出生时的朝向
+	fumenLeft    = 3 // 逆时针 90 度
+)
+
+// fumenBlocksAt 出生朝向时各格子相对旋转中心的偏移（x 向右，y 向上），下标为形状索引
+var fumenBlocksAt = [][4][2]int{
+	{{0, 0}, {-1, 0}, {1, 0}, {2, 0}},  // I
+	{{0, 0}, {1, 0}, {0, 1}, {1, 1}},   // O
+	{{0, 0}, {-1, 0}, {1, 0}, {0, 1}},  // T
+	{{0, 0}, {-1, 0}, {0, 1}, {1, 1}},  // S
+	{{0, 0}, {1, 0}, {0, 1}, {-1, 1}},  // Z
+	{{0, 0}, {-1, 0}, {1, 0}, {-1, 1}}, // J
+	{{0, 0}, {-1, 0}, {1, 0}, {1, 1}},  // L
+}
+
+// fumenShift 写入 fumen 时部分方块的中心坐标需要的修正（x 向右、y 向上），读取时反向修正
+// 修正后 O 方块的四个朝向、I/S/Z 方块方向相反的两个朝向写出同一个位置
+// 下标为形状索引和朝向编号
+var fumenShift = [][4][2]int{
+	{{-1, 0}, {0, 0}, {0, 0}, {0, 1}},  // I
+	{{-1, 0}, {0, 0}, {0, 1}, {-1, 1}}, // O
+	{},                                 // T
+	{{0, 0}, {1, 0}, {0, 1}, {0, 0}},   // S
+	{{0, 0}, {0, 0}, {0, 1}, {-1, 0}},  // Z
+	{},                                 // J
+	{},                                 // L
+}
+
+// fumenCells 返回方块在给定朝向时各格子相对旋转中心的偏移
+func fumenCells(piece, rotation int) [4][2]int {
+	cells := fumenBlocksAt[piece]
+	for i, c := range cells {
+		switch rotation {
+		case fumenRight:
+			cells[i] = [2]int{c[1], -c[0]}
+		case fumenReverse:
+			cells[i] = [2]int{-c[0], -c[1]}
+		case fumenLeft:
+			cells[i] = [2]int{-c[1], c[0]}
+		}
+	}
+	return cells
+}
+
+// Fumen 一页 fumen 的内容：面板和当前方块
+type Fumen struct {
+	Board   [][]int // BoardHeight 行 x BoardWidth 列，取值与 Game 的面板相同
+	Piece   int     // 当前方块的形状索引，-1 表示没有方块
+	Shape   [][]int // 当前方块（已旋转）的形状数据
+	X, Y    int     // 当前方块左上角在面板上的坐标（Y 可以为负，即方块有一部分在面板上方）
+	Comment string  // 注释
+}
+
+// Fumen 返回当前面板和当前方块（固定序列用完时没有方块）
+func (g *Game) Fumen() Fumen {
+	f := Fumen{Board: copyGrid(g.board), Piece: -1}
+	if g.currShape != nil {
+		f.Piece, f.Shape, f.X, f.Y = g.currPiece, copyGrid(g.currShape), g.pieceX, g.pieceY
+	}
+	return f
+}
+
+// NewFumenGame 从 fumen 的面板和当前方块开始一局马拉松，之后的方块按种子随机生成
+// fumen 中没有方块时随机生成第一个方块；重新开始时恢复同样的面板
+func NewFumenGame(f Fumen, seed int64) *Game {
+	g := NewSeededGame(ModeMarathon, seed)
+	g.start = copyGrid(f.Board)
+	g.board = copyGrid(f.Board)
+	if f.Piece >= 0 {
+		g.currPiece, g.currShape, g.pieceX, g.pieceY = f.Piece, copyGrid(f.Shape), f.X, f.Y
+	}
+	return g
+}
+
+// ============================================
+// 编码
+// ============================================
+
+// EncodeFumen 把面板和当前方块编码为 fumen 字符串
+func EncodeFumen(f Fumen) string {
+	var b strings.Builder
+	b.WriteString("v115@")
+
+	// 面板：与空面板的差异（差值+8），按从上到下、从左到右的顺序游程编码
+	cells := make([]int, fumenBlocks)
+	for row, line := range f.Board {
+		for x, v := range line {
+			cells[(row+fumenTop-BoardHeight)*BoardWidth+x] = fumenBlock(v)
+		}
+	}
+	runs := 0
+	for start, i := 0, 1; i <= len(cells); i++ {
+		if i == len(cells) || cells[i] != cells[start] {
+			writeFumen(&b, (cells[start]+8)*fumenBlocks+i-start-1, 2)
+			start = i
+			runs++
+		}
+	}
+	if runs == 1 && cells[0] == 0 {
+		// 面板与上一页（第一页是空面板）相同时紧跟一位重复页数
+		writeFumen(&b, 0, 1)
+	}
+
+	// 当前方块：形状、朝向、中心位置和标志位（锁定、注释、着色、镜像、上升）
+	piece, rotation, loc := 0, 0, 0
+	if x, y, r, ok := f.center(); ok {
+		shift := fumenShift[f.Piece][r]
+		piece, rotation = fumenTypes[f.Piece], r
+		loc = (fumenTop-(y+shift[1])-1)*BoardWidth + x + shift[0]
+	}
+	comment := escapeComment(f.Comment)
+	flags := 0 // 不锁定（0 表示方块在这一页放下）
+	flags = flags*2 + boolBit(comment != "")
+	flags = flags*2 + 1 // 着色
+	flags = flags * 2   // 镜像
+	flags = flags * 2   // 上升
+	writeFumen(&b, ((flags*fumenBlocks+loc)*4+rotation)*8+piece, 3)
+
+	// 注释：长度，然后每 4 个字符一组按 96 进制写成 5 位
+	if comment != "" {
+		writeFumen(&b, len(comment), 2)
+		for i := 0; i < len(comment); i += 4 {
+			v := 0
+			for j := min(i+4, len(comment)) - 1; j >= i; j-- {
+				v = v*96 + strings.IndexByte(commentTable, comment[j])
+			}
+			writeFumen(&b, v, 5)
+		}
+	}
+	return b.String()
+}
+
+// center 返回当前方块在 fumen 坐标系中的旋转中心和朝向
+// 本游戏的形状都是 SRS 形状的旋转，按格子的相对位置找出对应的朝向
+func (f Fumen) center() (x, y, rotation int, ok bool) {
+	if f.Piece < 0 || f.Shape == nil {
+		return 0, 0, 0, false
+	}
+	var cells [][2]int
+	for sy, row := range f.Shape {
+		for sx, cell := range row {
+			if cell == 1 {
+				cells = append(cells, [2]int{f.X + sx, BoardHeight - 1 - (f.Y + sy)})
+			}
+		}
+	}
+	minX, minY := bounds(cells)
+	for _, r := range []int{fumenSpawn, fumenRight, fumenReverse, fumenLeft} {
+		offsets := fumenCells(f.Piece, r)
+		offMinX, offMinY := bounds(offsets[:])
+		matched := 0
+		for _, o := range offsets {
+			for _, c := range cells {
+				if o[0]-offMinX == c[0]-minX && o[1]-offMinY == c[1]-minY {
+					matched++
+				}
+			}
+		}
+		if matched == len(cells) && len(cells) == len(offsets) {
+			return minX - offMinX, minY - offMinY, r, true
+		}
+	}
+	// 不会发生：形状数据总是 SRS 形状的旋转
+	return 0, 0, 0, false
+}
+
+// bounds 返回一组坐标的最小 x 和最小 y
+func bounds(cells [][2]int) (minX, minY int) {
+	minX, minY = cells[0][0], cells[0][1]
+	for _, c := range cells {
+		minX, minY = min(minX, c[0]), min(minY, c[1])
+	}
+	return minX, minY
+}
+
+// fumenBlock 把面板上的值转换为 fumen 的方块编号
+func fumenBlock(v int) int {
+	switch v {
+	case 0:
+		return 0
+	case Garbage:
+		return fumenGray
+	}
+	return fumenTypes[v-1]
+}
+
+// writeFumen 把 v 写成 n 位 64 进制数字，低位在前
+func writeFumen(b *strings.Builder, v, n int) {
+	for range n {
+		b.WriteByte(fumenTable[v%64])
+		v /= 64
+	}
+}
+
+// boolBit 把布尔值转换为 0 或 1
+func boolBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ============================================
+// 解码
+// ============================================
+
+// fumenReader 按位读取 64 进制数据
+type fumenReader struct {
+	data string
+	pos  int
+}
+
+// read 读取 n 位 64 进制数字，低位在前
+func (r *fumenReader) read(n int) (int, error) {
+	if r.pos+n > len(r.data) {
+		return 0, errors.New("fumen data is truncated")
+	}
+	v := 0
+	for i := r.pos + n - 1; i >= r.pos; i-- {
+		d := strings.IndexByte(fumenTable, r.data[i])
+		if d < 0 {
+			return 0, fmt.Errorf("invalid character %q in fumen data", r.data[i])
+		}
+		v = v*64 + d
+	}
+	r.pos += n
+	return v, nil
+}
+
+// DecodeFumen 解析 fumen 字符串的第一页
+// 可以是完整的网址（例如 https://fumen.zui.jp/?v115@...），数据中用于换行的 '?' 会被忽略
+func DecodeFumen(s string) (Fumen, error) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndex(s, "115@")
+	if i < 1 || !strings.ContainsRune("vmd", rune(s[i-1])) {
+		return Fumen{}, errors.New("not a v115 fumen (expected \"v115@...\")")
+	}
+	r := &fumenReader{data: strings.ReplaceAll(s[i+len("115@"):], "?", "")}
+
+	// 面板
+	f := Fumen{Board: make([][]int, BoardHeight), Piece: -1}
+	for y := range f.Board {
+		f.Board[y] = make([]int, BoardWidth)
+	}
+	for i := 0; i < fumenBlocks; {
+		v, err := r.read(2)
+		if err != nil {
+			return Fumen{}, err
+		}
+		block, count := v/fumenBlocks-8, v%fumenBlocks+1
+		if block < 0 || block > fumenGray || i+count > fumenBlocks {
+			return Fumen{}, errors.New("invalid fumen field")
+		}
+		if v == 8*fumenBlocks+fumenBlocks-1 {
+			// 整个面板与上一页相同，紧跟一位重复页数
+			if _, err := r.read(1); err != nil {
+				return Fumen{}, err
+			}
+		}
+		for ; count > 0; count, i = count-1, i+1 {
+			row, x := i/BoardWidth-(fumenTop-BoardHeight), i%BoardWidth
+			switch {
+			case block == 0 || row >= BoardHeight:
+				// 空白，或者是底部的垃圾行（忽略）
+			case row < 0:
+				return Fumen{}, fmt.Errorf("fumen field is taller than the %d-row board", BoardHeight)
+			case block == fumenGray:
+				f.Board[row][x] = Garbage
+			default:
+				f.Board[row][x] = slices.Index(fumenTypes, block) + 1
+			}
+		}
+	}
+
+	// 当前方块
+	v, err := r.read(3)
+	if err != nil {
+		return Fumen{}, err
+	}
+	piece, rotation, loc := v%8, v/8%4, v/32%fumenBlocks
+	flags := v / 32 / fumenBlocks // 上升、镜像、着色、注释、不锁定，低位在前
+	if piece > 0 && piece < fumenGray {
+		f.Piece = slices.Index(fumenTypes, piece)
+		shift := fumenShift[f.Piece][rotation]
+		x, y := loc%BoardWidth-shift[0], fumenTop-loc/BoardWidth-1-shift[1]
+		if err := f.place(x, y, rotation); err != nil {
+			return Fumen{}, err
+		}
+	}
+
+	// 注释
+	if flags>>3&1 == 1 {
+		n, err := r.read(2)
+		if err != nil {
+			return Fumen{}, err
+		}
+		var comment []byte
+		for len(comment) < n {
+			v, err := r.read(5)
+			if err != nil {
+				return Fumen{}, err
+			}
+			for range 4 {
+				if v%96 >= len(commentTable) {
+					return Fumen{}, errors.New("invalid fumen comment")
+				}
+				comment = append(comment, commentTable[v%96])
+				v /= 96
+			}
+		}
+		f.Comment = unescapeComment(string(comment[:n]))
+	}
+	return f, nil
+}
+
+// place 把旋转中心在 fumen 坐标 (x, y) 的方块放到面板上，方块必须在面板范围内且不与已有方块重叠
+func (f *Fumen) place(x, y, rotation int) error {
+	cells := fumenCells(f.Piece, rotation)
+	var rows, cols []int
+	for _, c := range cells {
+		col, row := x+c[0], BoardHeight-1-(y+c[1])
+		if col < 0 || col >= BoardWidth || row >= BoardHeight || row < BoardHeight-fumenTop {
+			return errors.New("fumen piece is outside the board")
+		}
+		if row >= 0 && f.Board[row][col] != 0 {
+			return errors.New("fumen piece overlaps the board")
+		}
+		rows, cols = append(rows, row), append(cols, col)
+	}
+	f.X, f.Y = slices.Min(cols), slices.Min(rows)
+	f.Shape = make([][]int, slices.Max(rows)-f.Y+1)
+	for i := range f.Shape {
+		f.Shape[i] = make([]int, slices.Max(cols)-f.X+1)
+	}
+	for i := range rows {
+		f.Shape[rows[i]-f.Y][cols[i]-f.X] = 1
+	}
+	return nil
+}
+
+// ============================================
+// 文字显示
+// ============================================
+
+// Text 把面板画成文字，从最高的非空行画到底部
+// 不着色时与谜题文件的面板格式相同：'.' 空白，'X' 垃圾方块，形状字母为对应颜色的方块，
+// 当前方块用小写字母表示；着色时按游戏中的颜色画方块（ANSI 真彩色），两侧和底部有边框
+func (f Fumen) Text(color bool) string {
+	board := copyGrid(f.Board)
+	top := BoardHeight - 1
+	for row, line := range board {
+		if slices.ContainsFunc(line, func(v int) bool { return v != 0 }) {
+			top = min(top, row)
+		}
+	}
+	// 当前方块记为负数，与已锁定的方块区分
+	for sy, line := range f.Shape {
+		for sx, cell := range line {
+			if row := f.Y + sy; cell == 1 && row >= 0 {
+				board[row][f.X+sx] = -(f.Piece + 1)
+				top = min(top, row)
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range board[top:] {
+		if color {
+			b.WriteByte('|')
+		}
+		for _, v := range line {
+			piece := max(v, -v) // 形状索引+1 或 Garbage
+			switch {
+			case v == 0 && color:
+				b.WriteString("  ")
+			case v == 0:
+				b.WriteByte('.')
+			case color:
+				r, g, bl := getColor(cellColor(piece)).RGB()
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm■ \x1b[0m", r, g, bl)
+			case v == Garbage:
+				b.WriteByte('X')
+			case v < 0:
+				b.WriteByte(pieceLetters[piece-1] + 'a' - 'A')
+			default:
+				b.WriteByte(pieceLetters[piece-1])
+			}
+		}
+		if color {
+			b.WriteByte('|')
+		}
+		b.WriteByte('\n')
+	}
+	if color {
+		b.WriteString("+" + strings.Repeat("-", BoardWidth*2) + "+\n")
+	}
+	return b.String()
+}
+
+// ============================================
+// 注释
+// ============================================
+
+// commentTable 注释使用的字符（ASCII 32~126），值为下标，每 4 个字符按 96 进制打包
+const commentTable = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+// escapeComment 按 JavaScript 的 escape 转义注释：
+// 字母、数字和 @*_+-./ 保持不变，其余 UTF-16 码元写成 %XX 或 %uXXXX
+func escapeComment(s string) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		switch {
+		case u < 0x80 && (u >= 'a' && u <= 'z' || u >= 'A' && u <= 'Z' || u >= '0' && u <= '9' || strings.ContainsRune("@*_+-./", rune(u))):
+			b.WriteByte(byte(u))
+		case u < 0x100:
+			fmt.Fprintf(&b, "%%%02X", u)
+		default:
+			fmt.Fprintf(&b, "%%u%04X", u)
+		}
+	}
+	return b.String()
+}
+
+// unescapeComment escapeComment 的逆操作，无法识别的 % 原样保留
+func unescapeComment(s string) string {
+	var units []uint16
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' {
+			if i+6 <= len(s) && s[i+1] == 'u' {
+				if v, err := strconv.ParseUint(s[i+2:i+6], 16, 16); err == nil {
+					units = append(units, uint16(v))
+					i += 5
+					continue
+				}
+			}
+			if i+3 <= len(s) {
+				if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+					units = append(units, uint16(v))
+					i += 2
+					continue
+				}
+			}
+		}
+		units = append(units, uint16(s[i]))
+	}
+	return string(utf16.Decode(units))
+}
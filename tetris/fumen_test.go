@@ -0,0 +1,206 @@
+package tetris
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+)
+
+// TestEncodeEmptyFumen 空面板、没有方块的一页是 fumen 网站生成的 "v115@vhAAgH"
+func TestEncodeEmptyFumen(t *testing.T) {
+	f := Fumen{Board: NewGame().board, Piece: -1}
+	if got := EncodeFumen(f); got != "v115@vhAAgH" {
+		t.Errorf("EncodeFumen = %q, want v115@vhAAgH", got)
+	}
+	decoded, err := DecodeFumen("v115@vhAAgH")
+	if err != nil {
+		t.Fatalf("DecodeFumen: %v", err)
+	}
+	if decoded.Piece != -1 || !sameBoard(decoded.Board, f.Board) {
+		t.Errorf("decoded = %+v", decoded)
+	}
+}
+
+// TestDecodeFumenField 面板按从上到下的顺序编码，fumen 最下面四行对应面板最下面四行
+func TestDecodeFumenField(t *testing.T) {
+	f, err := DecodeFumen("v115@9gF8DeF8DeF8DeF8NeAgH")
+	if err != nil {
+		t.Fatalf("DecodeFumen: %v", err)
+	}
+	want := strings.Repeat("XXXXXX....\n", 4)
+	if got := f.Text(false); got != want {
+		t.Errorf("Text:\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestFumenRoundTrip 每种方块的每个朝向编码后再解码，面板和方块位置保持不变
+func TestFumenRoundTrip(t *testing.T) {
+	for piece := range Shapes {
+		for turns := 0; turns < 4; turns++ {
+			g := NewSeededGame(ModeMarathon, 9)
+			playPieces(g, 5)
+			g.board[BoardHeight-1][0] = Garbage
+			g.currPiece, g.currShape = piece, Shapes[piece]
+			g.pieceX, g.pieceY = 3, 2
+			for range turns {
+				g.rotate()
+			}
+			want := g.Fumen()
+			want.Comment = "setup #1: 开局"
+
+			s := EncodeFumen(want)
+			got, err := DecodeFumen(s)
+			if err != nil {
+				t.Fatalf("%c x%d: DecodeFumen(%q): %v", pieceLetters[piece], turns, s, err)
+			}
+			if got.Text(false) != want.Text(false) || got.Comment != want.Comment {
+				t.Errorf("%c x%d: round trip of %q:\n%s%q\nwant\n%s%q", pieceLetters[piece], turns, s, got.Text(false), got.Comment, want.Text(false), want.Comment)
+			}
+		}
+	}
+}
+
+// TestFumenReference 与 fumen 参考实现（tetris-fumen）的编码一致：I、O、S、Z 方块的各个朝向都落在正确的位置
+// 对称的朝向解码后是同一个形状，再次编码得到生成方向或向右的朝向
+func TestFumenReference(t *testing.T) {
+	vertical := strings.Repeat("...i......\n", 4)
+	tests := []struct {
+		name  string
+		fumen string
+		text  string
+		again string // 重新编码的结果，空表示与 fumen 相同
+	}{
+		{"I spawn", "v115@vhARQJ", "...iiii...\n", ""},
+		{"I right", "v115@vhApFJ", vertical, ""},
+		{"I reverse", "v115@vhABQJ", "...iiii...\n", "v115@vhARQJ"},
+		{"I left", "v115@vhA5FJ", vertical, "v115@vhApFJ"},
+		{"O spawn", "v115@vhAzKJ", "...oo.....\n...oo.....\n", ""},
+		{"O right", "v115@vhArKJ", "...oo.....\n...oo.....\n", "v115@vhAzKJ"},
+		{"O reverse", "v115@vhAjKJ", "...oo.....\n...oo.....\n", "v115@vhAzKJ"},
+		{"O left", "v115@vhA7KJ", "...oo.....\n...oo.....\n", "v115@vhAzKJ"},
+		{"O in the corner", "v115@vhATJJ", "oo........\noo........\n", ""},
+		{"S spawn", "v115@vhAXLJ", "....ss....\n...ss.....\n", ""},
+		{"S right", "v115@vhAPLJ", "...s......\n...ss.....\n....s.....\n", ""},
+		{"S reverse", "v115@vhAHLJ", "....ss....\n...ss.....\n", "v115@vhAXLJ"},
+		{"S left", "v115@vhAfLJ", "...s......\n...ss.....\n....s.....\n", "v115@vhAPLJ"},
+		{"Z spawn", "v115@vhAULJ", "...zz.....\n....zz....\n", ""},
+		{"Z right", "v115@vhAsKJ", "....z.....\n...zz.....\n...z......\n", ""},
+		{"Z reverse", "v115@vhAELJ", "...zz.....\n....zz....\n", "v115@vhAULJ"},
+		{"Z left", "v115@vhA8KJ", "....z.....\n...zz.....\n...z......\n", "v115@vhAsKJ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := DecodeFumen(tt.fumen)
+			if err != nil {
+				t.Fatalf("DecodeFumen(%q): %v", tt.fumen, err)
+			}
+			if got := f.Text(false); got != tt.text {
+				t.Errorf("DecodeFumen(%q):\n%s\nwant\n%s", tt.fumen, got, tt.text)
+			}
+			want := tt.again
+			if want == "" {
+				want = tt.fumen
+			}
+			if got := EncodeFumen(f); got != want {
+				t.Errorf("EncodeFumen = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestFumenGame 从 fumen 开始的对局保留面板和当前方块，编码回去得到同一个 fumen
+func TestFumenGame(t *testing.T) {
+	g := NewSeededGame(ModeMarathon, 4)
+	playPieces(g, 6)
+	g.Do(ActionRotate)
+	s := EncodeFumen(g.Fumen())
+
+	f, err := DecodeFumen("https://fumen.zui.jp/?" + s[:20] + "?" + s[20:])
+	if err != nil {
+		t.Fatalf("DecodeFumen: %v", err)
+	}
+	loaded := NewFumenGame(f, 1)
+	if got := EncodeFumen(loaded.Fumen()); got != s {
+		t.Errorf("game from fumen encodes to %q, want %q", got, s)
+	}
+	loaded.Do(ActionHardDrop)
+	loaded.reset()
+	if !sameBoard(loaded.board, f.Board) {
+		t.Errorf("restart should restore the fumen board:\n%s", dumpBoard(loaded))
+	}
+}
+
+func TestFumenText(t *testing.T) {
+	p, err := FindPuzzle("T-Spin Double")
+	if err != nil {
+		t.Fatalf("FindPuzzle: %v", err)
+	}
+	g := NewPuzzleGame(p)
+	for _, a := range append([]Action{ActionRotate, ActionRotate, ActionLeft}, repeat(ActionSoftDrop, 16)...) {
+		g.Do(a)
+	}
+	want := strings.Join([]string{
+		"...ttt....",
+		"XXXXtXXXX.",
+		"XXX..XXXXX",
+		"XXXX.XXXXX",
+		"",
+	}, "\n")
+	if got := g.Fumen().Text(false); got != want {
+		t.Errorf("Text:\n%s\nwant\n%s", got, want)
+	}
+	colored := g.Fumen().Text(true)
+	if lines := strings.Split(colored, "\n"); len(lines) != 6 || !strings.Contains(colored, "\x1b[38;2;") {
+		t.Errorf("colored text should have 4 rows, a border and ANSI colors:\n%s", colored)
+	}
+}
+
+// TestRunCopyFumen 复制键把当前面板以 fumen 格式写入终端剪贴板，信息面板给出提示
+func TestRunCopyFumen(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	screentest.Type(screen, 5*time.Millisecond, screentest.Rune(' '), screentest.Rune('c'), screentest.Key(tcell.KeyEscape))
+	if err := Run(screen, input.DefaultTetris(), Config{Puzzle: "Three for Four"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !screentest.Contains(screen, "FUMEN COPIED") {
+		t.Errorf("expected a notice:\n%s", screentest.Dump(screen))
+	}
+
+	p, err := FindPuzzle("Three for Four")
+	if err != nil {
+		t.Fatalf("FindPuzzle: %v", err)
+	}
+	g := NewPuzzleGame(p)
+	g.Do(ActionHardDrop)
+	if got, want := string(screen.GetClipboardData()), EncodeFumen(g.Fumen()); got != want {
+		t.Errorf("clipboard = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeFumenErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"no prefix", "vhAAgH", "not a v115 fumen"},
+		{"old version", "v110@7eAA4G", "not a v115 fumen"},
+		{"truncated", "v115@vhAAg", "truncated"},
+		{"bad character", "v115@vh!AgH", "invalid character"},
+		{"too tall", "v115@A8uhAgH", "taller"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeFumen(tt.s)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
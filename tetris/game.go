@@ -1,6 +1,10 @@
 package tetris
 
-import "math/rand"
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
 
 // ============================================
 // 常量定义 - 游戏参数配置
@@ -11,6 +15,41 @@ const (
 	BoardHeight = 20 // 游戏面板高度（格子数）
 )
 
+// ============================================
+// 游戏模式
+// ============================================
+
+type Mode int
+
+const (
+	ModeMarathon Mode = iota // 马拉松：无尽模式，等级随消除行数提升
+	ModeSprint               // 竞速：以最短时间消除 SprintLines 行
+)
+
+// SprintLines 竞速模式需要消除的行数
+const SprintLines = 40
+
+// modeNames 模式名称（命令行参数与录像文件中使用）
+var modeNames = []string{"marathon", "sprint"}
+
+// String 返回模式名称
+func (m Mode) String() string {
+	if int(m) < len(modeNames) {
+		return modeNames[m]
+	}
+	return "unknown"
+}
+
+// ParseMode 根据名称查找模式
+func ParseMode(name string) (Mode, error) {
+	for i, n := range modeNames {
+		if n == name {
+			return Mode(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown mode %q (valid modes: %v)", name, modeNames)
+}
+
 // ============================================
 // 形状定义 - 7种经典俄罗斯方块
 // ============================================
@@ -54,6 +93,11 @@ func (r randRNG) Intn(n int) int {
 	return rand.Intn(n)
 }
 
+// seededRNG 按种子创建标准库的随机数生成器（默认的随机数工厂，见 SetRNG）
+func seededRNG(seed int64) RNG {
+	return rand.New(rand.NewSource(seed))
+}
+
 // ============================================
 // Game 结构体 - 核心游戏状态
 // ============================================
@@ -71,15 +115,36 @@ type Game struct {
 	pieceX int // 方块左上角在面板的X坐标
 	pieceY int // 方块左上角在面板的Y坐标
 
+	// 当前方块最后一次成功的操作是旋转（用于判定 T-spin，移动或下落后清除）
+	lastRotate bool
+
+	// 固定的起始面板和方块序列（谜题，见 NewFixedGame）
+	start   [][]int    // 起始面板，重新开始时恢复；nil 表示空面板
+	queue   []int      // 固定的方块序列（形状索引），nil 表示随机生成
+	queued  int        // 已经从 queue 取出的方块数
+	puzzle  *Puzzle    // 当前谜题，nil 表示普通游戏
+	history []snapshot // 每个方块开始时的状态，用于撤销（只在固定序列和练习时记录）
+	future  []snapshot // 撤销掉的状态，用于重做；放下新的方块后清空
+
+	// 练习（见 NewPracticeGame）：随机序列也可以撤销和重做，没有重力
+	practice bool
+	draws    int // 从 seed 开始已经取过的随机数个数，撤销时据此恢复随机数的位置
+
 	// 游戏状态
-	score    int  // 当前得分
-	lines    int  // 消除的总行数
-	level    int  // 当前等级（影响下落速度）
-	paused   bool // 游戏是否暂停
-	gameOver bool // 游戏是否结束
+	mode     Mode          // 游戏模式
+	score    int           // 当前得分
+	lines    int           // 消除的总行数
+	level    int           // 当前等级（影响下落速度）
+	elapsed  time.Duration // 游戏时间（不含暂停）
+	paused   bool          // 游戏是否暂停
+	gameOver bool          // 游戏是否结束
+	won      bool          // 是否完成目标（竞速模式消除足够行数）
 
 	// 依赖组件
-	rng RNG // 随机数生成器
+	rng    RNG                  // 随机数生成器
+	newRNG func(seed int64) RNG // 按种子创建随机数生成器的工厂（见 SetRNG），撤销时也用它从种子重新生成
+	seed   int64                // 创建游戏时的种子（NewSeededGame）
+	fixed  bool                 // 每次重新开始都从 seed 重新生成随机数（每日挑战：每局的方块序列都相同）
 }
 
 // ============================================
@@ -100,6 +165,7 @@ func NewGame() *Game {
 		pieceY:  0,
 		level:   1,
 		rng:     randRNG{},
+		newRNG:  seededRNG,
 	}
 }
 
@@ -116,9 +182,21 @@ func NewGame() *Game {
 // 4. 预生成下一个方块
 // 5. 检查方块是否还能放置（无法放置则游戏结束）
 func (g *Game) spawnPiece() {
+	// 放下了新的方块，之前撤销掉的状态不能再重做
+	g.future = nil
+
 	// 选择当前方块
-	if g.nextPiece == 0 {
-		g.currPiece = g.rng.Intn(len(Shapes))
+	if g.queue != nil {
+		// 固定序列：依次取出，取完后没有当前方块，本局结束
+		if g.queued >= len(g.queue) {
+			g.currShape, g.nextPiece = nil, 0
+			g.gameOver = true
+			return
+		}
+		g.currPiece = g.queue[g.queued]
+		g.queued++
+	} else if g.nextPiece == 0 {
+		g.currPiece = g.randomPiece()
 	} else {
 		// nextPiece 存储的是颜色索引+1，所以需要减1
 		g.currPiece = g.nextPiece - 1
@@ -128,9 +206,20 @@ func (g *Game) spawnPiece() {
 	// 设置方块位置（居中）
 	g.pieceX = BoardWidth/2 - len(g.currShape[0])/2
 	g.pieceY = 0
+	g.lastRotate = false
 
 	// 预生成下一个方块（+1 是因为0表示"未设置"状态）
-	g.nextPiece = g.rng.Intn(len(Shapes)) + 1
+	if g.queue != nil {
+		g.nextPiece = 0
+		if g.queued < len(g.queue) {
+			g.nextPiece = g.queue[g.queued] + 1
+		}
+	} else {
+		g.nextPiece = g.randomPiece() + 1
+	}
+	if g.queue != nil || g.practice {
+		g.history = append(g.history, g.snapshot())
+	}
 
 	// 检查碰撞：如果新方块无法放置，游戏结束
 	if g.collides() {
@@ -166,12 +255,17 @@ func (g *Game) collides() bool {
 	return false
 }
 
+// rotateKicks 谜题中原地旋转发生碰撞时依次尝试的偏移（dx, dy）：先左右，再向下一格
+// 向下的偏移让 T 方块可以转进有遮挡的槽里（T-spin）
+// 其他模式不使用偏移，保持原有的旋转规则（旧录像按原规则回放）
+var rotateKicks = [][2]int{{-1, 0}, {1, 0}, {0, 1}}
+
 // rotate 旋转当前方块（顺时针90度）
 //
 // 旋转算法：
 // 1. 创建一个新的矩阵，行列互换
 // 2. 通过 formula: rotated[x][rows-1-y] = cell 实现顺时针旋转
-// 3. 如果旋转后发生碰撞，则回滚到原形状
+// 3. 如果旋转后发生碰撞，谜题中依次尝试 rotateKicks 中的偏移，都不行则回滚到原形状
 func (g *Game) rotate() {
 	rows := len(g.currShape)
 	cols := len(g.currShape[0])
@@ -189,12 +283,22 @@ func (g *Game) rotate() {
 		}
 	}
 
-	// 尝试应用旋转，碰撞则回滚
+	// 尝试应用旋转，碰撞则尝试偏移，都不行则回滚
 	oldShape := g.currShape
 	g.currShape = rotated
-	if g.collides() {
-		g.currShape = oldShape
+	if !g.collides() {
+		g.lastRotate = true
+		return
 	}
+	if g.puzzle != nil {
+		for _, kick := range rotateKicks {
+			if g.move(kick[0], kick[1]) {
+				g.lastRotate = true
+				return
+			}
+		}
+	}
+	g.currShape = oldShape
 }
 
 // move 尝试移动方块
@@ -208,6 +312,7 @@ func (g *Game) move(dx, dy int) bool {
 		g.pieceY -= dy
 		return false
 	}
+	g.lastRotate = false
 	return true
 }
 
@@ -215,10 +320,12 @@ func (g *Game) move(dx, dy int) bool {
 // 返回值：如果方块落地返回 false，否则返回 true
 func (g *Game) drop() bool {
 	if !g.move(0, 1) {
-		// 方块落地，执行锁定、消除、生成新方块
+		// 方块落地，执行锁定、消除、生成新方块，谜题检查是否达成目标
+		spin := g.isTSpin()
 		g.lockPiece()
-		g.clearLines()
+		cleared := g.clearLines()
 		g.spawnPiece()
+		g.checkGoal(spin, cleared)
 		return false
 	}
 	return true
@@ -258,7 +365,9 @@ func (g *Game) lockPiece() {
 // 消除2行: 300 * level
 // 消除3行: 500 * level
 // 消除4行: 800 * level
-func (g *Game) clearLines() {
+//
+// 返回消除的行数
+func (g *Game) clearLines() int {
 	linesCleared := 0
 
 	// 从底部向上扫描
@@ -300,7 +409,14 @@ func (g *Game) clearLines() {
 
 		// 每消除10行升一级
 		g.level = g.lines/10 + 1
+
+		// 竞速模式：达到目标行数即完成
+		if g.mode == ModeSprint && g.lines >= SprintLines {
+			g.won = true
+			g.gameOver = true
+		}
 	}
+	return linesCleared
 }
 
 // ============================================
@@ -316,6 +432,10 @@ func (g *Game) clearLines() {
 // 3. 当无法下落时，返回最后的有效位置
 func (g *Game) getGhostPosition() (int, int) {
 	ghostY := g.pieceY
+	if g.currShape == nil {
+		// 固定序列的方块已经用完，没有当前方块
+		return g.pieceX, ghostY
+	}
 
 	for {
 		canMove := true
@@ -355,20 +475,30 @@ func (g *Game) getGhostPosition() (int, int) {
 
 // reset 重置游戏到初始状态
 func (g *Game) reset() {
-	// 清空面板
+	if g.fixed {
+		g.rng, g.draws = g.newRNG(g.seed), 0
+	}
+
+	// 清空面板（固定起始面板时恢复起始面板）
 	for y := range g.board {
 		for x := range g.board[y] {
 			g.board[y][x] = 0
+			if g.start != nil {
+				g.board[y][x] = g.start[y][x]
+			}
 		}
 	}
+	g.queued, g.history, g.future = 0, nil, nil
 
 	// 重置状态
 	g.score = 0
 	g.lines = 0
 	g.level = 1
 	g.nextPiece = 0
+	g.elapsed = 0
 	g.paused = false
 	g.gameOver = false
+	g.won = false
 
 	// 生成第一个方块
 	g.spawnPiece()
@@ -0,0 +1,103 @@
+package tetris
+
+import "time"
+
+// ============================================
+// 撤销与重做 - 每个方块开始时的状态快照
+// ============================================
+// 固定方块序列的对局（谜题）和练习在每个方块出现时（也就是上一个方块放下之后）记下一份快照，
+// 撤销时回到上一个方块出现时的状态，可以一直撤销到第一个方块；撤销掉的快照保留下来用于重做，
+// 放下新的方块后清空
+//
+// 随机序列的快照记录随机数已经取过的个数，恢复时用创建游戏时的工厂（见 SetRNG）从种子重新生成
+// 并跳过同样多个，因此撤销之后出现的方块与撤销前完全相同
+
+// snapshot 一个方块出现时的游戏状态
+type snapshot struct {
+	board   [][]int // 已锁定的方块
+	piece   int     // 当前方块的形状索引
+	next    int     // 下一个方块（+1 存储，0 表示没有）
+	queued  int     // 已经从固定序列取出的方块数
+	draws   int     // 已经取过的随机数个数（随机序列）
+	score   int
+	lines   int
+	level   int
+	elapsed time.Duration // 游戏时间，撤销时一起倒回
+}
+
+// snapshot 记录当前状态
+func (g *Game) snapshot() snapshot {
+	return snapshot{
+		board:   copyGrid(g.board),
+		piece:   g.currPiece,
+		next:    g.nextPiece,
+		queued:  g.queued,
+		draws:   g.draws,
+		score:   g.score,
+		lines:   g.lines,
+		level:   g.level,
+		elapsed: g.elapsed,
+	}
+}
+
+// restore 恢复到快照的状态：方块回到顶部的出生位置，本局重新开始进行
+func (g *Game) restore(s snapshot) {
+	g.board = copyGrid(s.board)
+	g.currPiece, g.nextPiece, g.queued = s.piece, s.next, s.queued
+	g.score, g.lines, g.level, g.elapsed = s.score, s.lines, s.level, s.elapsed
+	g.currShape = Shapes[g.currPiece]
+	g.pieceX = BoardWidth/2 - len(g.currShape[0])/2
+	g.pieceY = 0
+	g.lastRotate = false
+	g.paused, g.gameOver, g.won = false, false, false
+	if g.queue == nil {
+		g.seek(s.draws)
+	}
+	// 重做到堆到顶的那一步时本局仍然是结束的
+	g.gameOver = g.collides()
+}
+
+// seek 从种子重新生成随机数，跳过前 draws 个
+func (g *Game) seek(draws int) {
+	g.rng, g.draws = g.newRNG(g.seed), 0
+	for g.draws < draws {
+		g.randomPiece()
+	}
+}
+
+// randomPiece 随机选择一个形状并计数（见 seek）
+func (g *Game) randomPiece() int {
+	g.draws++
+	return g.rng.Intn(len(Shapes))
+}
+
+// Undo 撤销上一个方块：回到它出现时的状态
+// 本局因方块用完而结束时回到最后一个方块出现时；没有可以撤销的方块时返回 false
+func (g *Game) Undo() bool {
+	n := len(g.history)
+	switch {
+	case n > 0 && g.currShape == nil:
+		// 方块已经用完，最后一份快照就是最后一个方块
+	case n > 1:
+		g.future = append(g.future, g.history[n-1])
+		g.history = g.history[:n-1]
+		n--
+	default:
+		return false
+	}
+	g.restore(g.history[n-1])
+	return true
+}
+
+// Redo 重做上一次撤销：回到撤销前下一个方块出现时的状态；没有可以重做的方块时返回 false
+func (g *Game) Redo() bool {
+	n := len(g.future)
+	if n == 0 {
+		return false
+	}
+	s := g.future[n-1]
+	g.future = g.future[:n-1]
+	g.history = append(g.history, s)
+	g.restore(s)
+	return true
+}
@@ -0,0 +1,184 @@
+package tetris
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+	"go-game/replay"
+)
+
+// sameState 两个状态的面板、方块序列和计分是否相同
+func sameState(a, b State) bool {
+	return sameBoard(a.Board, b.Board) && a.Piece == b.Piece && a.Next == b.Next &&
+		a.Score == b.Score && a.Lines == b.Lines && a.Level == b.Level
+}
+
+// TestPracticeUndoRedo 练习中撤销回到之前的方块，重做回到撤销前，撤销后出现的方块序列不变
+func TestPracticeUndoRedo(t *testing.T) {
+	g := NewPracticeGame(11)
+	var states []State
+	for i := 0; i < 8; i++ {
+		states = append(states, g.State())
+		playPieces(g, 1)
+	}
+	states = append(states, g.State())
+
+	for i := 0; i < 3; i++ {
+		if !g.Undo() {
+			t.Fatalf("undo %d failed", i+1)
+		}
+	}
+	if !sameState(g.State(), states[5]) {
+		t.Errorf("after three undos the game should be back at piece 6:\n%s", dumpBoard(g))
+	}
+	if !g.Redo() || !sameState(g.State(), states[6]) {
+		t.Errorf("redo should return to piece 7:\n%s", dumpBoard(g))
+	}
+
+	// 撤销后按同样的方式放下方块，随机数回到了撤销时的位置，得到与第一次完全相同的对局
+	g.Undo()
+	for i := 5; i < 8; i++ {
+		playPieces(g, 1)
+		if !sameState(g.State(), states[i+1]) {
+			t.Fatalf("piece %d differs after undo: got piece %d next %d, want %d next %d",
+				i+2, g.State().Piece, g.State().Next, states[i+1].Piece, states[i+1].Next)
+		}
+	}
+	if g.Redo() {
+		t.Error("placing a new piece should discard the redo history")
+	}
+
+	for g.Undo() {
+	}
+	if !sameState(g.State(), states[0]) {
+		t.Errorf("undoing everything should return to the first piece:\n%s", dumpBoard(g))
+	}
+}
+
+// cycleRNG 按固定步长循环取值的随机数，与标准库的序列完全不同
+type cycleRNG struct{ next int }
+
+func (r *cycleRNG) Intn(n int) int {
+	v := r.next % n
+	r.next += 3
+	return v
+}
+
+// TestUndoInjectedRNG 撤销跨过新方块出现时，用注入的随机数工厂从种子重新生成，游戏时间一起倒回
+func TestUndoInjectedRNG(t *testing.T) {
+	g := NewPracticeGame(5)
+	g.SetRNG(func(seed int64) RNG { return &cycleRNG{next: int(seed)} })
+
+	// 每个方块用一秒：第 i 个方块出现时的游戏时间是 i 秒
+	var states []State
+	for i := 0; i < 4; i++ {
+		states = append(states, g.State())
+		g.elapsed += time.Second
+		g.Do(ActionHardDrop) // Do 不推进游戏时间
+	}
+	// 注入的序列：5, 8, 11, 14, ... 对 7 取余
+	for i, s := range states {
+		if want := (5 + 3*i) % len(Shapes); s.Piece != want {
+			t.Fatalf("piece %d = %d, want %d from the injected RNG", i+1, s.Piece, want)
+		}
+	}
+
+	if !g.Undo() || !g.Undo() {
+		t.Fatal("Undo failed")
+	}
+	if got := g.State(); !sameState(got, states[2]) || got.Elapsed != states[2].Elapsed {
+		t.Errorf("after two undos: piece %d next %d elapsed %v, want piece %d next %d elapsed %v",
+			got.Piece, got.Next, got.Elapsed, states[2].Piece, states[2].Next, states[2].Elapsed)
+	}
+	// 放下方块后出现的仍是注入序列中的下一个
+	g.Do(ActionHardDrop)
+	if got := g.State(); got.Piece != states[3].Piece || got.Next != (5+3*4)%len(Shapes) {
+		t.Errorf("after undo: piece %d next %d, want the injected sequence", got.Piece, got.Next)
+	}
+}
+
+// TestPracticeUndoTopOut 练习中堆到顶后可以撤销继续，重做到堆到顶的那一步仍然是结束的
+func TestPracticeUndoTopOut(t *testing.T) {
+	g := NewPracticeGame(2)
+	for !g.Over() {
+		g.Do(ActionHardDrop)
+	}
+	if !g.Undo() || g.Over() {
+		t.Fatal("undo should resume the game")
+	}
+	if !g.Redo() || !g.Over() {
+		t.Error("redo of the losing placement should end the game again")
+	}
+}
+
+// TestUndoWithoutHistory 普通对局不记录快照，不能撤销
+func TestUndoWithoutHistory(t *testing.T) {
+	g := NewSeededGame(ModeMarathon, 1)
+	playPieces(g, 3)
+	if g.Undo() || g.Redo() {
+		t.Error("a normal game should not support undo")
+	}
+}
+
+// TestRunPractice 练习没有重力，按键撤销和重做，录像回放得到同一局面
+func TestRunPractice(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	path := filepath.Join(t.TempDir(), "practice.json")
+	keys := input.DefaultTetris()
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	screentest.Type(screen, 5*time.Millisecond,
+		screentest.Rune(' '),
+		screentest.Key(tcell.KeyLeft),
+		screentest.Rune(' '),
+		screentest.Rune('u'),
+		screentest.Rune('u'),
+		screentest.Key(tcell.KeyCtrlR),
+		screentest.Key(tcell.KeyRight),
+		screentest.Rune(' '),
+	)
+	go func() {
+		// 等待足够久，确认方块没有自己下落
+		time.Sleep(time.Second)
+		screentest.Type(screen, 0, screentest.Key(tcell.KeyEscape))
+	}()
+	if err := Run(screen, keys, Config{Practice: true, Seed: 5, Record: path}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !screentest.Contains(screen, "PRACTICE") {
+		t.Errorf("expected the practice mode label:\n%s", screentest.Dump(screen))
+	}
+
+	want := NewPracticeGame(5)
+	for _, a := range [][]Action{{ActionHardDrop}, {ActionRight, ActionHardDrop}} {
+		for _, action := range a {
+			want.Do(action)
+		}
+	}
+	if i := len(want.history); i != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", i)
+	}
+
+	rp, err := replay.Load(path)
+	if err != nil {
+		t.Fatalf("load replay: %v", err)
+	}
+	if !rp.Practice {
+		t.Error("replay should be marked as practice")
+	}
+	got := NewPracticeGame(rp.Seed)
+	for _, ev := range rp.Events {
+		apply(got, ev.Action)
+	}
+	if !sameState(got.State(), want.State()) || got.State().Y != 0 {
+		t.Errorf("replayed practice:\n%s\nwant\n%s", dumpBoard(got), dumpBoard(want))
+	}
+	if screentest.Contains(screen, "GAME OVER") {
+		t.Errorf("practice should not end:\n%s", screentest.Dump(screen))
+	}
+}
@@ -0,0 +1,354 @@
+package tetris
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-game/internal/store"
+	"go-game/internal/textfile"
+)
+
+// ============================================
+// Puzzle - 谜题
+// ============================================
+// 谜题文件是纯文本格式，例如：
+//
+//   # 以 # 加空格开头的行是注释
+//   name: T-Spin Triple
+//   goal: tspin 3
+//   pieces: T
+//   XXX...XXXX
+//   XXXX.XXXXX
+//   XXX..XXXXX
+//   XXXX.XXXXX
+//
+// - "键: 值" 形式的行是属性：name（谜题名，默认取文件名）、goal（目标）、pieces（方块序列）
+// - goal 可以是 "lines N"（一共消除 N 行）、"tspin N"（用 T-spin 一次消除 N 行，1~3）
+//   或 "perfect-clear"（消除后面板全空）
+// - pieces 是按顺序出现的方块，用形状字母表示，可以用空格分隔：I O T S Z J L
+// - 其余行是面板，每行 BoardWidth 个字符，从底部对齐（最多 BoardHeight 行）
+//   '.' 空白  'X' 或 '#' 灰色的垃圾方块  'I' 'O' 'T' 'S' 'Z' 'J' 'L' 对应颜色的方块
+//
+// 谜题没有重力，方块只在玩家软降或硬降时下落；方块用完仍未达成目标即为失败
+
+type Puzzle struct {
+	Name   string  // 谜题名
+	Goal   Goal    // 目标
+	Pieces []int   // 方块序列（形状索引）
+	Board  [][]int // 起始面板，BoardHeight 行 x BoardWidth 列
+}
+
+// GoalKind 谜题目标的种类
+type GoalKind int
+
+const (
+	GoalLines        GoalKind = iota // 一共消除 N 行
+	GoalTSpin                        // 用 T-spin 一次消除 N 行
+	GoalPerfectClear                 // 消除后面板全空
+)
+
+// Goal 谜题目标
+type Goal struct {
+	Kind  GoalKind
+	Lines int // GoalLines 和 GoalTSpin 的行数
+}
+
+// tspinNames T-spin 按一次消除的行数的名称
+var tspinNames = []string{"", "single", "double", "triple"}
+
+// String 返回目标的说明，例如 "Clear 4 lines"、"T-spin triple"
+func (g Goal) String() string {
+	switch g.Kind {
+	case GoalTSpin:
+		return "T-spin " + tspinNames[g.Lines]
+	case GoalPerfectClear:
+		return "Perfect clear"
+	}
+	if g.Lines == 1 {
+		return "Clear 1 line"
+	}
+	return fmt.Sprintf("Clear %d lines", g.Lines)
+}
+
+// parseGoal 解析 goal 属性
+func parseGoal(value string) (Goal, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 1 && fields[0] == "perfect-clear" {
+		return Goal{Kind: GoalPerfectClear}, nil
+	}
+	if len(fields) == 2 {
+		n, err := strconv.Atoi(fields[1])
+		switch {
+		case err != nil:
+		case fields[0] == "lines" && n > 0:
+			return Goal{Kind: GoalLines, Lines: n}, nil
+		case fields[0] == "tspin" && n >= 1 && n < len(tspinNames):
+			return Goal{Kind: GoalTSpin, Lines: n}, nil
+		}
+	}
+	return Goal{}, fmt.Errorf("invalid goal %q (want \"lines N\", \"tspin 1-3\" or \"perfect-clear\")", value)
+}
+
+// pieceLetters 形状字母，下标为形状索引（与 Shapes 的顺序一致）
+const pieceLetters = "IOTSZJL"
+
+// pieceT T 方块的形状索引
+const pieceT = 2
+
+// Garbage 面板上垃圾方块的值（大于所有形状索引+1，显示为灰色）
+const Garbage = 8
+
+// ParsePuzzle 解析谜题文件，name 为未指定 name 属性时使用的默认谜题名
+func ParsePuzzle(name string, r io.Reader) (*Puzzle, error) {
+	p := &Puzzle{Name: name}
+	var rows [][]int
+	hasGoal := false
+
+	// 属性行
+	prop := func(key, value string) error {
+		switch key {
+		case "name":
+			p.Name = value
+		case "goal":
+			goal, err := parseGoal(value)
+			if err != nil {
+				return err
+			}
+			p.Goal, hasGoal = goal, true
+		case "pieces":
+			for _, ch := range strings.ReplaceAll(value, " ", "") {
+				i := strings.IndexRune(pieceLetters, ch)
+				if i < 0 {
+					return fmt.Errorf("unknown piece %q (valid: %s)", ch, pieceLetters)
+				}
+				p.Pieces = append(p.Pieces, i)
+			}
+		default:
+			return fmt.Errorf("unknown property %q", key)
+		}
+		return nil
+	}
+
+	// 面板行
+	boardRow := func(text string) error {
+		if len(text) != BoardWidth {
+			return fmt.Errorf("row width %d, want %d", len(text), BoardWidth)
+		}
+		row := make([]int, BoardWidth)
+		for x, ch := range text {
+			switch {
+			case ch == '.':
+			case ch == 'X' || ch == '#':
+				row[x] = Garbage
+			case strings.ContainsRune(pieceLetters, ch):
+				row[x] = strings.IndexRune(pieceLetters, ch) + 1
+			default:
+				return fmt.Errorf("unexpected %q in board", ch)
+			}
+		}
+		rows = append(rows, row)
+		return nil
+	}
+	if err := textfile.Scan(r, prop, boardRow); err != nil {
+		return nil, err
+	}
+
+	// 校验
+	if p.Name == "" {
+		return nil, errors.New("puzzle has no name")
+	}
+	if !hasGoal {
+		return nil, errors.New("puzzle has no goal")
+	}
+	if len(p.Pieces) == 0 {
+		return nil, errors.New("puzzle has no pieces")
+	}
+	if len(rows) > BoardHeight-2 {
+		return nil, fmt.Errorf("board has %d rows, at most %d leave room for new pieces", len(rows), BoardHeight-2)
+	}
+	p.Board = make([][]int, BoardHeight)
+	for y := range p.Board {
+		p.Board[y] = make([]int, BoardWidth)
+	}
+	for i, row := range rows {
+		full := true
+		for _, cell := range row {
+			full = full && cell != 0
+		}
+		if full {
+			return nil, fmt.Errorf("board row %d is already full", i+1)
+		}
+		copy(p.Board[BoardHeight-len(rows)+i], row)
+	}
+	return p, nil
+}
+
+// ============================================
+// 谜题对局
+// ============================================
+
+// NewFixedGame 从给定的面板和固定的方块序列开始一局（马拉松模式的计分）
+// board 为 BoardHeight 行 x BoardWidth 列（会被复制，nil 表示空面板），pieces 为形状索引；
+// 方块用完时本局结束。重新开始时恢复同样的面板和序列，每个方块都可以撤销（见 Undo）
+func NewFixedGame(board [][]int, pieces []int) *Game {
+	g := NewGame()
+	if board != nil {
+		g.start = copyGrid(board)
+		g.board = copyGrid(board)
+	}
+	g.queue = append([]int{}, pieces...)
+	g.spawnPiece()
+	return g
+}
+
+// NewPuzzleGame 开始一个谜题
+func NewPuzzleGame(p *Puzzle) *Game {
+	g := NewFixedGame(p.Board, p.Pieces)
+	g.puzzle = p
+	return g
+}
+
+// isTSpin 当前方块锁定前判断是否为 T-spin（三角规则）：
+// 方块是 T，最后一次成功的操作是旋转，且 T 中心周围四个斜角中至少三个被占据（边界外算占据）
+func (g *Game) isTSpin() bool {
+	if g.currPiece != pieceT || !g.lastRotate {
+		return false
+	}
+	// 中心是形状中上下左右有三个相邻格子的那一格
+	cx, cy := -1, -1
+	for y, row := range g.currShape {
+		for x, cell := range row {
+			if cell == 1 && g.shapeNeighbours(x, y) == 3 {
+				cx, cy = g.pieceX+x, g.pieceY+y
+			}
+		}
+	}
+	corners := 0
+	for _, d := range [][2]int{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+		x, y := cx+d[0], cy+d[1]
+		if x < 0 || x >= BoardWidth || y >= BoardHeight || y >= 0 && g.board[y][x] != 0 {
+			corners++
+		}
+	}
+	return corners >= 3
+}
+
+// shapeNeighbours 返回当前方块形状中 (x, y) 上下左右相邻的格子数
+func (g *Game) shapeNeighbours(x, y int) int {
+	n := 0
+	for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		nx, ny := x+d[0], y+d[1]
+		if ny >= 0 && ny < len(g.currShape) && nx >= 0 && nx < len(g.currShape[ny]) && g.currShape[ny][nx] == 1 {
+			n++
+		}
+	}
+	return n
+}
+
+// checkGoal 方块锁定并消除后检查谜题目标，spin 为这个方块是否是 T-spin，cleared 为消除的行数
+// 达成目标时本局结束并记为完成；方块用完或堆到顶时本局结束（失败）
+func (g *Game) checkGoal(spin bool, cleared int) {
+	if g.puzzle == nil {
+		return
+	}
+	done := false
+	switch goal := g.puzzle.Goal; goal.Kind {
+	case GoalLines:
+		done = g.lines >= goal.Lines
+	case GoalTSpin:
+		done = spin && cleared == goal.Lines
+	case GoalPerfectClear:
+		done = cleared > 0 && g.boardEmpty()
+	}
+	if done {
+		g.won, g.gameOver = true, true
+	}
+}
+
+// boardEmpty 面板上是否没有任何已锁定的方块
+func (g *Game) boardEmpty() bool {
+	for _, row := range g.board {
+		for _, cell := range row {
+			if cell != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Remaining 固定方块序列中还没有出现的方块数（不含当前方块），随机序列时返回 -1
+func (g *Game) Remaining() int {
+	if g.queue == nil {
+		return -1
+	}
+	return len(g.queue) - g.queued
+}
+
+// ============================================
+// 谜题包
+// ============================================
+
+// puzzleDir 存档目录下存放用户自定义谜题的子目录
+const puzzleDir = "tetris-puzzles"
+
+// puzzleExt 谜题文件扩展名
+const puzzleExt = ".txt"
+
+// builtinPuzzles 内置谜题包，按文件名排序
+//
+//go:embed puzzles/*.txt
+var builtinPuzzles embed.FS
+
+// LoadPuzzles 加载全部谜题：先是内置谜题，然后是存档目录 tetris-puzzles 下的用户谜题
+// 两组各自按文件名排序；无法解析的用户谜题会被跳过，错误合并后一起返回
+func LoadPuzzles() ([]*Puzzle, error) {
+	return textfile.Load(builtinPuzzles, "puzzles", puzzleDir, puzzleExt, ParsePuzzle)
+}
+
+// FindPuzzle 按谜题名查找谜题（不区分大小写）
+func FindPuzzle(name string) (*Puzzle, error) {
+	puzzles, err := LoadPuzzles()
+	if p, ok := textfile.Find(puzzles, name, func(p *Puzzle) string { return p.Name }); ok {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("unknown puzzle %q", name)
+}
+
+// ============================================
+// 解题记录
+// ============================================
+
+// solvedFile 解题记录在存档目录中的文件名
+const solvedFile = "tetris-puzzles.json"
+
+// LoadSolved 读取已解开的谜题：谜题名 -> 第一次解开的时间
+func LoadSolved() (map[string]time.Time, error) {
+	solved := map[string]time.Time{}
+	if err := store.Load(solvedFile, &solved); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return solved, nil
+}
+
+// markSolved 记录谜题已解开，已经解开过的谜题保留第一次的时间
+func markSolved(name string) error {
+	solved, err := LoadSolved()
+	if err != nil {
+		return err
+	}
+	if _, ok := solved[name]; ok {
+		return nil
+	}
+	solved[name] = time.Now().Truncate(time.Second)
+	return store.Save(solvedFile, solved)
+}
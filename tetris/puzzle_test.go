@@ -0,0 +1,378 @@
+package tetris
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+)
+
+// repeat 返回 n 个相同的操作
+func repeat(a Action, n int) []Action {
+	actions := make([]Action, n)
+	for i := range actions {
+		actions[i] = a
+	}
+	return actions
+}
+
+// solutions 内置谜题的解法：每个方块的操作序列（不推进重力，与谜题一致）
+var solutions = map[string][][]Action{
+	"Warm Up": {
+		append([]Action{ActionRotate}, append(repeat(ActionRight, 6), ActionHardDrop)...),
+	},
+	"Three for Four": {
+		append(repeat(ActionRight, 3), ActionHardDrop),
+		append(repeat(ActionRight, 3), ActionHardDrop),
+		append([]Action{ActionRotate}, append(repeat(ActionRight, 6), ActionHardDrop)...),
+	},
+	"Perfect Clear": {
+		append(repeat(ActionRight, 2), ActionHardDrop),
+		append([]Action{ActionRotate, ActionRotate}, append(repeat(ActionRight, 3), ActionHardDrop)...),
+		{ActionLeft, ActionHardDrop},
+	},
+	"T-Spin Double": {
+		append([]Action{ActionRotate, ActionRotate, ActionLeft}, append(repeat(ActionSoftDrop, 16), ActionRotate, ActionHardDrop)...),
+	},
+	"T-Spin Triple": {
+		append([]Action{ActionRotate}, append(repeat(ActionRight, 6), ActionHardDrop)...),
+		append([]Action{ActionRotate, ActionRotate, ActionLeft}, append(repeat(ActionSoftDrop, 16), ActionRotate, ActionHardDrop)...),
+	},
+}
+
+// solve 按解法逐个方块执行操作
+func solve(g *Game, steps [][]Action) {
+	for _, actions := range steps {
+		for _, a := range actions {
+			g.Do(a)
+		}
+	}
+}
+
+// TestBuiltinPuzzlesSolvable 每个内置谜题都能按解法完成，且少一个方块时不算完成
+func TestBuiltinPuzzlesSolvable(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	puzzles, err := LoadPuzzles()
+	if err != nil {
+		t.Fatalf("LoadPuzzles: %v", err)
+	}
+	if len(puzzles) != len(solutions) {
+		t.Errorf("%d builtin puzzles, %d solutions", len(puzzles), len(solutions))
+	}
+	for _, p := range puzzles {
+		t.Run(p.Name, func(t *testing.T) {
+			steps, ok := solutions[p.Name]
+			if !ok {
+				t.Fatal("no solution")
+			}
+			g := NewPuzzleGame(p)
+			solve(g, steps[:len(steps)-1])
+			if g.Over() {
+				t.Fatalf("game over before the last piece:\n%s", dumpBoard(g))
+			}
+			solve(g, steps[len(steps)-1:])
+			if !g.won || !g.Over() {
+				t.Errorf("puzzle not solved (goal %s):\n%s", p.Goal, dumpBoard(g))
+			}
+		})
+	}
+}
+
+// TestPuzzleFailsWhenPiecesRunOut 方块用完仍未达成目标时本局以失败结束
+func TestPuzzleFailsWhenPiecesRunOut(t *testing.T) {
+	p, err := FindPuzzle("t-spin double")
+	if err != nil {
+		t.Fatalf("FindPuzzle: %v", err)
+	}
+	g := NewPuzzleGame(p)
+	// 不旋转直接放进槽里：消除两行但不是 T-spin
+	g.Do(ActionHardDrop)
+	if !g.Over() || g.won {
+		t.Errorf("over = %v, won = %v; want a failed puzzle", g.Over(), g.won)
+	}
+	if g.State().Lines != 0 {
+		t.Errorf("lines = %d, a flat T cannot clear a line here", g.State().Lines)
+	}
+}
+
+// TestRotateKicksOnlyInPuzzles 靠墙旋转时只有谜题会尝试偏移，其他模式保持原有的旋转规则
+func TestRotateKicksOnlyInPuzzles(t *testing.T) {
+	for _, puzzle := range []bool{false, true} {
+		g := NewSeededGame(ModeMarathon, 1)
+		if puzzle {
+			g.puzzle = &Puzzle{Name: "kick"}
+		}
+		// 竖着的 I 方块靠近右墙，横过来会超出面板一格
+		g.currPiece, g.currShape = 0, [][]int{{1}, {1}, {1}, {1}}
+		g.pieceX, g.pieceY = BoardWidth-3, 2
+		g.rotate()
+		if kicked := len(g.currShape) == 1; kicked != puzzle {
+			t.Errorf("puzzle %v: rotated = %v, want %v", puzzle, kicked, puzzle)
+		}
+		if puzzle && g.pieceX != BoardWidth-4 {
+			t.Errorf("kicked I piece at x = %d, want %d", g.pieceX, BoardWidth-4)
+		}
+	}
+}
+
+// TestRotateKickDown T-spin 谜题的最后一转要靠向下一格的偏移才能转进槽里：
+// 去掉 {0, 1} 之后同样的解法无法完成
+func TestRotateKickDown(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	for _, name := range []string{"T-Spin Double", "T-Spin Triple"} {
+		t.Run(name, func(t *testing.T) {
+			p, err := FindPuzzle(name)
+			if err != nil {
+				t.Fatalf("FindPuzzle: %v", err)
+			}
+			for _, kicks := range [][][2]int{rotateKicks, {{-1, 0}, {1, 0}}} {
+				saved := rotateKicks
+				rotateKicks = kicks
+				g := NewPuzzleGame(p)
+				solve(g, solutions[name])
+				rotateKicks = saved
+				if want := len(kicks) == len(saved); g.won != want {
+					t.Errorf("kicks %v: solved = %v, want %v\n%s", kicks, g.won, want, dumpBoard(g))
+				}
+			}
+		})
+	}
+}
+
+// TestPuzzleUndo 撤销回到上一个方块出现时的状态，用完方块后也能撤销，重来回到起始面板
+func TestPuzzleUndo(t *testing.T) {
+	p, err := FindPuzzle("Three for Four")
+	if err != nil {
+		t.Fatalf("FindPuzzle: %v", err)
+	}
+	g := NewPuzzleGame(p)
+	if g.Undo() {
+		t.Error("nothing to undo before the first piece is placed")
+	}
+	start := g.State()
+
+	steps := solutions["Three for Four"]
+	solve(g, steps[:1])
+	afterFirst := g.State()
+	solve(g, steps[1:2])
+	if !g.Undo() {
+		t.Fatal("Undo failed")
+	}
+	if got := g.State(); !sameBoard(got.Board, afterFirst.Board) || got.Piece != afterFirst.Piece || g.Remaining() != 1 {
+		t.Errorf("undo should return to the second piece:\n%s", dumpBoard(g))
+	}
+
+	// 故意放错最后一个方块，方块用完后撤销再放对
+	solve(g, steps[1:2])
+	g.Do(ActionHardDrop)
+	if !g.Over() || g.won {
+		t.Fatal("a misplaced I should fail the puzzle")
+	}
+	if !g.Undo() || g.Over() {
+		t.Fatal("undo after the last piece should resume the game")
+	}
+	solve(g, steps[2:])
+	if !g.won {
+		t.Errorf("puzzle should be solved after undoing the mistake:\n%s", dumpBoard(g))
+	}
+
+	g.reset()
+	if got := g.State(); !sameBoard(got.Board, start.Board) || got.Piece != start.Piece || got.Next != start.Next || g.Over() {
+		t.Errorf("restart should restore the starting board:\n%s", dumpBoard(g))
+	}
+}
+
+// TestTSpinNeedsRotation 三角规则：T 方块最后一次成功的操作必须是旋转
+func TestTSpinNeedsRotation(t *testing.T) {
+	p, err := FindPuzzle("T-Spin Double")
+	if err != nil {
+		t.Fatalf("FindPuzzle: %v", err)
+	}
+	g := NewPuzzleGame(p)
+	for _, a := range append([]Action{ActionRotate, ActionRotate, ActionLeft}, append(repeat(ActionSoftDrop, 16), ActionRotate)...) {
+		g.Do(a)
+	}
+	if !g.isTSpin() {
+		t.Fatalf("T kicked into the slot should be a T-spin:\n%s", dumpBoard(g))
+	}
+	// 旋转后再左右移动一次（移动失败不算）仍然是 T-spin，移动成功则不是
+	g.Do(ActionLeft)
+	if !g.isTSpin() {
+		t.Error("a blocked move should keep the T-spin")
+	}
+	g.lastRotate = false
+	if g.isTSpin() {
+		t.Error("without a rotation last it is not a T-spin")
+	}
+}
+
+func TestParsePuzzle(t *testing.T) {
+	src := strings.Join([]string{
+		"# comment",
+		"goal: lines 2",
+		"pieces: I O T",
+		"",
+		"IIII..XX#.",
+		"X.X.X.X.XZ",
+	}, "\n")
+	p, err := ParsePuzzle("fallback", strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParsePuzzle: %v", err)
+	}
+	if p.Name != "fallback" || p.Goal != (Goal{Kind: GoalLines, Lines: 2}) || p.Goal.String() != "Clear 2 lines" {
+		t.Errorf("name %q goal %+v (%s)", p.Name, p.Goal, p.Goal)
+	}
+	if len(p.Pieces) != 3 || p.Pieces[0] != 0 || p.Pieces[1] != 1 || p.Pieces[2] != pieceT {
+		t.Errorf("pieces = %v", p.Pieces)
+	}
+	if got := p.Board[BoardHeight-2]; got[0] != 1 || got[4] != 0 || got[6] != Garbage || got[8] != Garbage {
+		t.Errorf("second last row = %v", got)
+	}
+	if got := p.Board[BoardHeight-1][9]; got != 5 {
+		t.Errorf("Z cell = %d, want 5", got)
+	}
+}
+
+func TestParsePuzzleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no goal", "pieces: T\n", "no goal"},
+		{"no pieces", "goal: perfect-clear\n", "no pieces"},
+		{"bad goal", "goal: tspin 4\n", "invalid goal"},
+		{"bad piece", "goal: lines 1\npieces: T X\n", "unknown piece"},
+		{"bad property", "level: 3\n", "unknown property"},
+		{"row width", "goal: lines 1\npieces: I\nXXX\n", "row width"},
+		{"bad cell", "goal: lines 1\npieces: I\nXXXX?XXXXX\n", "unexpected"},
+		{"full row", "goal: lines 1\npieces: I\nXXXXXXXXXX\n", "already full"},
+		{"too tall", "goal: lines 1\npieces: I\n" + strings.Repeat("X.........\n", BoardHeight-1), "rows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePuzzle("p", strings.NewReader(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadUserPuzzles 存档目录中的用户谜题排在内置谜题之后，无法解析的文件被跳过并报告
+func TestLoadUserPuzzles(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	dir, err := store.Path(puzzleDir)
+	if err != nil {
+		t.Fatalf("store.Path: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"mine.txt":   "goal: lines 1\npieces: I\nXXXXXX....\n",
+		"broken.txt": "goal: lines 1\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	puzzles, err := LoadPuzzles()
+	if err == nil || !strings.Contains(err.Error(), "broken.txt") {
+		t.Errorf("err = %v, want the broken file reported", err)
+	}
+	if last := puzzles[len(puzzles)-1]; last.Name != "mine" || len(puzzles) != len(solutions)+1 {
+		t.Errorf("got %d puzzles, last %q", len(puzzles), last.Name)
+	}
+	if p, err := FindPuzzle("MINE"); err != nil || p.Name != "mine" {
+		t.Errorf("FindPuzzle = %v, %v", p, err)
+	}
+}
+
+// TestRunPuzzle 在界面中解开谜题：没有重力，解开后记入解题记录
+func TestRunPuzzle(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	keys := []*tcell.EventKey{
+		// 先放错再撤销，然后按解法放下长条
+		screentest.Rune(' '),
+		screentest.Rune('u'),
+		screentest.Key(tcell.KeyUp),
+	}
+	for i := 0; i < 6; i++ {
+		keys = append(keys, screentest.Key(tcell.KeyRight))
+	}
+	keys = append(keys, screentest.Rune(' '), screentest.Key(tcell.KeyEscape))
+	screentest.Type(screen, 5*time.Millisecond, keys...)
+	if err := Run(screen, input.DefaultTetris(), Config{Puzzle: "warm up", Seed: 3}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !screentest.Contains(screen, "SOLVED!") {
+		t.Errorf("expected the puzzle to be solved:\n%s", screentest.Dump(screen))
+	}
+	solved, err := LoadSolved()
+	if err != nil {
+		t.Fatalf("LoadSolved: %v", err)
+	}
+	if _, ok := solved["Warm Up"]; !ok || len(solved) != 1 {
+		t.Errorf("solved = %v, want Warm Up", solved)
+	}
+
+	if err := Run(screen, input.DefaultTetris(), Config{Puzzle: "no such puzzle"}); err == nil {
+		t.Error("expected an error for an unknown puzzle")
+	}
+}
+
+// TestRenderPuzzleGolden 谜题失败时提示撤销，信息面板显示谜题名、目标和剩余方块
+func TestRenderPuzzleGolden(t *testing.T) {
+	p, err := FindPuzzle("Perfect Clear")
+	if err != nil {
+		t.Fatalf("FindPuzzle: %v", err)
+	}
+	g := NewPuzzleGame(p)
+	solve(g, solutions[p.Name][:2])
+	g.Do(ActionHardDrop)
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewRenderer(screen, g, input.DefaultTetris()).Render()
+	screentest.AssertScreen(t, "render_puzzle", screen)
+}
+
+// sameBoard 两个面板是否相同
+func sameBoard(a, b [][]int) bool {
+	for y := range a {
+		for x := range a[y] {
+			if a[y][x] != b[y][x] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// dumpBoard 把面板和当前方块画成文字，用于测试失败时的输出
+func dumpBoard(g *Game) string {
+	s := g.State()
+	var b strings.Builder
+	for y, row := range s.Board {
+		for x, cell := range row {
+			ch := byte('.')
+			if cell != 0 {
+				ch = 'X'
+			}
+			if dy, dx := y-s.Y, x-s.X; s.Shape != nil && dy >= 0 && dy < len(s.Shape) && dx >= 0 && dx < len(s.Shape[dy]) && s.Shape[dy][dx] == 1 {
+				ch = '@'
+			}
+			b.WriteByte(ch)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -2,8 +2,11 @@ package tetris
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"go-game/input"
 )
 
 // ============================================
@@ -12,15 +15,24 @@ import (
 // 负责将游戏状态绘制到终端屏幕
 
 type Renderer struct {
-	screen tcell.Screen // tcell 屏幕对象
-	game   *Game        // 要渲染的游戏实例
+	screen   tcell.Screen  // tcell 屏幕对象
+	game     *Game         // 要渲染的游戏实例
+	keys     *input.Keymap // 当前按键映射（用于生成操作说明）
+	replay   bool          // 是否为录像回放
+	finished bool          // 录像是否已播放完毕
+	daily    string        // 每日挑战的日期，空表示普通对局
+	practice bool          // 每日挑战当天已经有成绩，之后的对局都是练习
+	scored   bool          // 刚结束的一局是每日挑战当天计分的一局
+	today    string        // 每日挑战当天计分的一局的得分
+	notice   string        // 信息面板上的提示（例如面板已复制），下一个动作后清除
 }
 
 // NewRenderer 创建渲染器实例
-func NewRenderer(screen tcell.Screen, game *Game) *Renderer {
+func NewRenderer(screen tcell.Screen, game *Game, keys *input.Keymap) *Renderer {
 	return &Renderer{
 		screen: screen,
 		game:   game,
+		keys:   keys,
 	}
 }
 
@@ -45,10 +57,10 @@ func (r *Renderer) Render() {
 	// 绘制左右边框
 	for y := 0; y < BoardHeight+2; y++ {
 		r.screen.SetContent(2, y+1, '|', nil, borderStyle)
-		r.screen.SetContent(BoardWidth*2+3, y+1, '|', nil, borderStyle)
+		r.screen.SetContent(BoardWidth*2+4, y+1, '|', nil, borderStyle)
 	}
 	// 绘制上下边框
-	for x := 0; x < BoardWidth*2+2; x++ {
+	for x := 0; x < BoardWidth*2+1; x++ {
 		r.screen.SetContent(3+x, 1, '-', nil, borderStyle)
 		r.screen.SetContent(3+x, BoardHeight+2, '-', nil, borderStyle)
 	}
@@ -58,7 +70,7 @@ func (r *Renderer) Render() {
 	for y := 0; y < BoardHeight; y++ {
 		for x := 0; x < BoardWidth; x++ {
 			if r.game.board[y][x] != 0 {
-				color := Colors[r.game.board[y][x]-1]
+				color := cellColor(r.game.board[y][x])
 				cellStyle := tcell.StyleDefault.Foreground(getColor(color))
 				r.screen.SetContent(4+x*2, y+2, '■', nil, cellStyle)
 				r.screen.SetContent(5+x*2, y+2, ' ', nil, cellStyle)
@@ -132,27 +144,94 @@ func (r *Renderer) Render() {
 		r.screen.SetContent(nextX+i, 8, ch, nil, infoStyle)
 	}
 	linesText := fmt.Sprintf("LINES: %d", r.game.lines)
+	if r.game.mode == ModeSprint && r.game.puzzle == nil {
+		linesText = fmt.Sprintf("LINES: %d/%d", r.game.lines, SprintLines)
+	}
 	for i, ch := range linesText {
 		r.screen.SetContent(nextX+i, 10, ch, nil, infoStyle)
 	}
 	levelText := fmt.Sprintf("LEVEL: %d", r.game.level)
+	switch {
+	case r.game.puzzle != nil:
+		// 谜题显示还剩几个方块（含当前方块）而不是等级
+		left := r.game.Remaining()
+		if r.game.currShape != nil {
+			left++
+		}
+		levelText = fmt.Sprintf("PIECES: %d/%d", left, len(r.game.puzzle.Pieces))
+	case r.game.mode == ModeSprint:
+		// 竞速模式显示用时而不是等级
+		levelText = "TIME: " + formatDuration(r.game.elapsed)
+	}
 	for i, ch := range levelText {
 		r.screen.SetContent(nextX+i, 12, ch, nil, infoStyle)
 	}
 
-	// 操作说明
-	controls := []string{
-		"CONTROLS:",
-		"←→ : Move",
-		"↑   : Rotate",
-		"↓   : Soft Drop",
-		"Space: Hard Drop",
-		"P   : Pause",
-		"Esc : Menu",
+	// 模式与回放标记
+	modeText := strings.ToUpper(r.game.mode.String())
+	switch {
+	case r.game.puzzle != nil:
+		modeText = "PUZZLE"
+	case r.daily != "":
+		modeText = "DAILY"
+	case r.game.practice:
+		modeText = "PRACTICE"
+	}
+	if r.replay {
+		modeText += " REPLAY"
+	}
+	for i, ch := range modeText {
+		r.screen.SetContent(nextX+10+i, 2, ch, nil, infoStyle)
 	}
-	for i, ctrl := range controls {
-		for j, ch := range ctrl {
-			r.screen.SetContent(nextX+j, 16+i, ch, nil, infoStyle)
+
+	// 每日挑战的日期和当天的成绩，计分机会用掉之后标记为练习
+	if r.daily != "" && !r.replay {
+		todayText := "TODAY: --"
+		if r.practice {
+			todayText = "TODAY: " + r.today
+		}
+		for i, ch := range r.daily {
+			r.screen.SetContent(nextX+10+i, 3, ch, nil, infoStyle)
+		}
+		for i, ch := range todayText {
+			r.screen.SetContent(nextX+10+i, 5, ch, nil, infoStyle)
+		}
+		if r.practice {
+			for i, ch := range "PRACTICE" {
+				r.screen.SetContent(nextX+10+i, 6, ch, nil, infoStyle)
+			}
+		}
+	}
+
+	// 谜题的名称和目标
+	if p := r.game.puzzle; p != nil {
+		for i, ch := range p.Name {
+			r.screen.SetContent(nextX+10+i, 3, ch, nil, infoStyle)
+		}
+		for i, ch := range "GOAL: " + p.Goal.String() {
+			r.screen.SetContent(nextX+10+i, 5, ch, nil, infoStyle)
+		}
+	}
+
+	// 提示
+	for i, ch := range r.notice {
+		r.screen.SetContent(nextX+10+i, 7, ch, nil, infoStyle)
+	}
+
+	// 操作说明（根据当前按键映射生成）
+	// 撤销和重做只在谜题和练习中显示，它们没有重力所以不显示暂停
+	free := r.game.puzzle != nil || r.game.practice
+	actions := slices.DeleteFunc(slices.Clone(r.keys.Actions), func(a input.Action) bool {
+		if free {
+			return a == input.Pause
+		}
+		return a == input.Undo || a == input.Redo
+	})
+	help := r.keys.HelpFor(actions...)
+	top := 14 - max(len(help)-11, 0) // 行数太多时向上挪，保证 25 行的终端放得下
+	for i, ctrl := range help {
+		for j, ch := range []rune(ctrl) {
+			r.screen.SetContent(nextX+j, top+i, ch, nil, infoStyle)
 		}
 	}
 
@@ -163,11 +242,57 @@ func (r *Renderer) Render() {
 		}
 	}
 	if r.game.gameOver {
-		for i, ch := range "GAME OVER" {
-			r.screen.SetContent(BoardWidth+1+i, BoardHeight/2+2, ch, nil, infoStyle)
+		switch {
+		case r.game.puzzle != nil:
+			// 谜题：解开或失败，失败时提示可以撤销
+			resultText, hint := "SOLVED!", ""
+			if !r.game.won {
+				resultText = "FAILED"
+				hint = fmt.Sprintf("%s to undo", r.keys.Label(input.Undo))
+			}
+			for i, ch := range resultText {
+				r.screen.SetContent(BoardWidth+1+i, BoardHeight/2+2, ch, nil, infoStyle)
+			}
+			for i, ch := range hint {
+				r.screen.SetContent(BoardWidth+i, BoardHeight/2+3, ch, nil, infoStyle)
+			}
+		case r.game.won:
+			for i, ch := range "FINISHED!" {
+				r.screen.SetContent(BoardWidth+1+i, BoardHeight/2+2, ch, nil, infoStyle)
+			}
+			for i, ch := range formatDuration(r.game.elapsed) {
+				r.screen.SetContent(BoardWidth+1+i, BoardHeight/2+3, ch, nil, infoStyle)
+			}
+		default:
+			for i, ch := range "GAME OVER" {
+				r.screen.SetContent(BoardWidth+1+i, BoardHeight/2+2, ch, nil, infoStyle)
+			}
+			if r.game.practice && !r.replay {
+				// 练习：堆到顶后可以撤销
+				for i, ch := range fmt.Sprintf("%s to undo", r.keys.Label(input.Undo)) {
+					r.screen.SetContent(BoardWidth+i, BoardHeight/2+3, ch, nil, infoStyle)
+				}
+			}
 		}
-		for i, ch := range "Press R to restart" {
-			r.screen.SetContent(BoardWidth-1+i, BoardHeight/2+4, ch, nil, infoStyle)
+		if r.daily != "" && !r.replay {
+			dailyText := "PRACTICE"
+			if r.scored {
+				dailyText = "SCORE SAVED"
+			}
+			for i, ch := range dailyText {
+				r.screen.SetContent(BoardWidth+1+i, BoardHeight/2+3, ch, nil, infoStyle)
+			}
+		}
+		if !r.replay {
+			restartText := fmt.Sprintf("Press %s to restart", r.keys.Label(input.Restart))
+			for i, ch := range restartText {
+				r.screen.SetContent(BoardWidth-1+i, BoardHeight/2+4, ch, nil, infoStyle)
+			}
+		}
+	}
+	if r.finished {
+		for i, ch := range "END OF REPLAY" {
+			r.screen.SetContent(BoardWidth-1+i, BoardHeight/2+6, ch, nil, infoStyle)
 		}
 	}
 
@@ -175,6 +300,14 @@ func (r *Renderer) Render() {
 	r.screen.Show()
 }
 
+// cellColor 返回面板上已锁定格子的颜色名称（v 为形状索引+1 或 Garbage）
+func cellColor(v int) string {
+	if v == Garbage {
+		return "gray"
+	}
+	return Colors[v-1]
+}
+
 // getColor 辅助函数：根据颜色名称返回 tcell.Color
 func getColor(name string) tcell.Color {
 	switch name {
@@ -192,6 +325,8 @@ func getColor(name string) tcell.Color {
 		return tcell.ColorNavy
 	case "olive":
 		return tcell.ColorOlive
+	case "gray":
+		return tcell.ColorGray
 	default:
 		return tcell.ColorWhite
 	}
@@ -0,0 +1,130 @@
+package tetris
+
+import (
+	"testing"
+
+	"go-game/input"
+	"go-game/internal/screentest"
+)
+
+// playPieces 用固定的操作序列推进若干个方块，得到一个有内容的面板
+func playPieces(g *Game, n int) {
+	moves := [][]Action{
+		{ActionLeft, ActionLeft, ActionLeft, ActionLeft},
+		{ActionRight, ActionRight, ActionRight},
+		{ActionRotate},
+		{ActionLeft, ActionLeft},
+		{ActionRight},
+		{},
+	}
+	for i := 0; i < n && !g.Over(); i++ {
+		actions := append(moves[i%len(moves)], ActionHardDrop)
+		g.Step(actions...)
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func() *Game
+		replay bool
+	}{
+		{
+			name:  "start",
+			setup: func() *Game { return NewSeededGame(ModeMarathon, 1) },
+		},
+		{
+			name: "midgame",
+			setup: func() *Game {
+				g := NewSeededGame(ModeMarathon, 1)
+				playPieces(g, 12)
+				return g
+			},
+		},
+		{
+			name: "paused",
+			setup: func() *Game {
+				g := NewSeededGame(ModeMarathon, 1)
+				playPieces(g, 3)
+				g.paused = true
+				return g
+			},
+		},
+		{
+			name: "gameover",
+			setup: func() *Game {
+				g := NewSeededGame(ModeMarathon, 2)
+				for !g.Over() {
+					g.Step(ActionHardDrop)
+				}
+				return g
+			},
+		},
+		{
+			name: "sprint",
+			setup: func() *Game {
+				g := NewSeededGame(ModeSprint, 3)
+				playPieces(g, 5)
+				return g
+			},
+		},
+		{
+			name: "practice",
+			setup: func() *Game {
+				g := NewPracticeGame(2)
+				for !g.Over() {
+					g.Do(ActionHardDrop)
+				}
+				return g
+			},
+		},
+		{
+			name:   "replay",
+			replay: true,
+			setup: func() *Game {
+				g := NewSeededGame(ModeMarathon, 4)
+				playPieces(g, 6)
+				return g
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+			r := NewRenderer(screen, tt.setup(), input.DefaultTetris())
+			r.replay = tt.replay
+			r.Render()
+			screentest.AssertScreen(t, "render_"+tt.name, screen)
+		})
+	}
+}
+
+// TestRenderCustomBindings 操作说明面板应反映当前按键绑定
+func TestRenderCustomBindings(t *testing.T) {
+	keys := input.DefaultTetris()
+	keys.Set(input.HardDrop, input.KeyRune('x'))
+	keys.Set(input.Pause, input.KeyRune('p'), input.KeyRune(' '))
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewRenderer(screen, NewSeededGame(ModeMarathon, 1), keys).Render()
+
+	for _, want := range []string{"X     : Hard Drop", "P/Space: Pause"} {
+		if !screentest.Contains(screen, want) {
+			t.Errorf("controls panel missing %q", want)
+		}
+	}
+}
+
+// TestRenderDailyGolden 每日挑战计分的一局结束后提示成绩已记录，信息面板显示日期和当天成绩
+func TestRenderDailyGolden(t *testing.T) {
+	g := NewDailyGame(2)
+	for !g.Over() {
+		g.Step(ActionHardDrop)
+	}
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	r := NewRenderer(screen, g, input.DefaultTetris())
+	r.daily, r.practice, r.scored, r.today = "2026-10-18", true, true, "120"
+	r.Render()
+	screentest.AssertScreen(t, "render_daily", screen)
+}
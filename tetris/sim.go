@@ -0,0 +1,184 @@
+package tetris
+
+import (
+	"time"
+)
+
+// ============================================
+// Headless 模拟接口
+// ============================================
+// 不依赖终端即可驱动游戏：固定种子 + 逐步推进 + 只读快照
+// 适用于测试、机器人和训练脚本
+//
+// 用法：
+//   g := tetris.NewSeededGame(tetris.ModeMarathon, 42)
+//   for !g.Over() {
+//       g.Step(tetris.ActionLeft, tetris.ActionHardDrop)
+//       state := g.State()
+//   }
+
+// Action 玩家可以执行的操作
+type Action int
+
+const (
+	ActionNone     Action = iota // 不操作
+	ActionLeft                   // 左移
+	ActionRight                  // 右移
+	ActionRotate                 // 顺时针旋转
+	ActionSoftDrop               // 下落一格
+	ActionHardDrop               // 直接落到底
+)
+
+// actionNames 操作名称，与 input.Action 的配置名一致，录像文件中使用
+var actionNames = []string{"None", "MoveLeft", "MoveRight", "RotateCW", "SoftDrop", "HardDrop"}
+
+// String 返回操作名称
+func (a Action) String() string {
+	if int(a) < len(actionNames) {
+		return actionNames[a]
+	}
+	return "Unknown"
+}
+
+// ParseAction 根据名称查找操作
+func ParseAction(name string) (Action, bool) {
+	for i, n := range actionNames {
+		if n == name {
+			return Action(i), true
+		}
+	}
+	return ActionNone, false
+}
+
+// NewSeededGame 按模式和种子创建游戏并生成第一个方块
+// 相同的种子和相同的操作序列总是得到相同的对局
+func NewSeededGame(mode Mode, seed int64) *Game {
+	g := NewGame()
+	g.mode = mode
+	g.seed, g.rng = seed, g.newRNG(seed)
+	g.spawnPiece()
+	return g
+}
+
+// NewDailyGame 按种子创建每日挑战（马拉松模式）
+// 重新开始时回到同一个种子，当天的每一局（包括练习）都是同样的方块序列
+func NewDailyGame(seed int64) *Game {
+	g := NewSeededGame(ModeMarathon, seed)
+	g.fixed = true
+	return g
+}
+
+// NewPracticeGame 按种子开始一局练习（马拉松的计分，没有重力，不计入高分榜）
+// 每个方块放下后记录快照，可以撤销和重做（见 Undo、Redo）；撤销时随机数也回到当时的位置，
+// 之后出现的方块与撤销前相同，可以反复练习同一段开局
+func NewPracticeGame(seed int64) *Game {
+	g := NewGame()
+	g.seed, g.rng = seed, g.newRNG(seed)
+	g.practice = true
+	g.spawnPiece()
+	return g
+}
+
+// Reset 使用新的种子重新开始
+func (g *Game) Reset(seed int64) {
+	g.seed, g.rng, g.draws = seed, g.newRNG(seed), 0
+	g.reset()
+}
+
+// SetRNG 设置按种子创建随机数生成器的工厂（例如测试中按顺序返回预设值的实现），并以当前种子重新开始
+// 之后重新开始和撤销都通过它从种子重新生成随机数，撤销之后出现的方块仍与撤销前相同
+func (g *Game) SetRNG(newRNG func(seed int64) RNG) {
+	g.newRNG = newRNG
+	g.Reset(g.seed)
+}
+
+// Do 执行单个操作（不推进重力）
+func (g *Game) Do(a Action) {
+	if g.gameOver {
+		return
+	}
+	switch a {
+	case ActionLeft:
+		g.move(-1, 0)
+	case ActionRight:
+		g.move(1, 0)
+	case ActionRotate:
+		g.rotate()
+	case ActionSoftDrop:
+		g.drop()
+	case ActionHardDrop:
+		for g.drop() {
+		}
+	}
+}
+
+// Step 依次执行给定操作，然后推进一次重力（方块下落一格）
+// 游戏时间按当前等级的下落间隔累加，因此结果与真实时间无关
+func (g *Game) Step(actions ...Action) {
+	if g.gameOver {
+		return
+	}
+	for _, a := range actions {
+		g.Do(a)
+	}
+	if !g.gameOver {
+		g.elapsed += time.Duration(g.getDropInterval()) * time.Millisecond
+		g.drop()
+	}
+}
+
+// Over 游戏是否已结束
+func (g *Game) Over() bool {
+	return g.gameOver
+}
+
+// ============================================
+// 只读快照
+// ============================================
+
+// State 某一时刻的游戏状态，所有切片都是副本，修改它不会影响游戏
+type State struct {
+	Board    [][]int       // 已锁定的方块：0 表示空，否则为形状索引+1
+	Piece    int           // 当前方块的形状索引 (0-6)
+	Shape    [][]int       // 当前方块（已旋转）的形状数据
+	X, Y     int           // 当前方块左上角在面板上的坐标
+	GhostY   int           // 当前方块硬降后的 Y 坐标
+	Next     int           // 下一个方块的形状索引 (0-6)
+	Mode     Mode          // 游戏模式
+	Score    int           // 当前得分
+	Lines    int           // 消除的总行数
+	Level    int           // 当前等级
+	Elapsed  time.Duration // 游戏时间
+	GameOver bool          // 游戏是否结束
+	Won      bool          // 是否完成目标（竞速模式）
+}
+
+// State 返回当前状态的快照
+func (g *Game) State() State {
+	_, ghostY := g.getGhostPosition()
+	return State{
+		Board:    copyGrid(g.board),
+		Piece:    g.currPiece,
+		Shape:    copyGrid(g.currShape),
+		X:        g.pieceX,
+		Y:        g.pieceY,
+		GhostY:   ghostY,
+		Next:     g.nextPiece - 1,
+		Mode:     g.mode,
+		Score:    g.score,
+		Lines:    g.lines,
+		Level:    g.level,
+		Elapsed:  g.elapsed,
+		GameOver: g.gameOver,
+		Won:      g.won,
+	}
+}
+
+// copyGrid 深拷贝二维数组
+func copyGrid(grid [][]int) [][]int {
+	c := make([][]int, len(grid))
+	for i, row := range grid {
+		c[i] = append([]int(nil), row...)
+	}
+	return c
+}
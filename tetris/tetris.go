@@ -1,12 +1,90 @@
 package tetris
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"go-game/daily"
+	"go-game/input"
+	"go-game/replay"
+	"go-game/scores"
 )
 
+// DailyGame 每日挑战中俄罗斯方块的名称（用于计算种子和记录历史）
+const DailyGame = "tetris"
+
+// ============================================
+// 启动参数
+// ============================================
+
+// Config 一局游戏的启动参数
+type Config struct {
+	Mode   Mode   // 游戏模式
+	Seed   int64  // 随机种子，0 表示随机生成
+	Record string // 录像保存路径，空表示不录制
+	Daily  string // 每日挑战的日期（见 daily.Today），非空时忽略 Mode 和 Seed，按日期的种子玩马拉松模式
+	Puzzle string // 谜题名（见 FindPuzzle），非空时忽略其他选项
+
+	// 练习（见 NewPracticeGame）：按 Seed 的随机序列玩马拉松，没有重力，可以撤销和重做，
+	// 不计入高分榜；与 Daily、Puzzle 同时设置时忽略
+	Practice bool
+}
+
+// apply 执行一个会改变游戏状态的动作（录像中的动作名）
+// Run 和 Replay 共用这一入口，保证录像回放与实际对局完全一致
+func apply(game *Game, action string) {
+	switch action {
+	case replay.Tick:
+		game.drop()
+	case input.Restart.String():
+		game.reset()
+	case input.Undo.String():
+		game.Undo()
+	case input.Redo.String():
+		game.Redo()
+	default:
+		if a, ok := ParseAction(action); ok {
+			game.Do(a)
+		}
+	}
+}
+
+// tableName 返回当前模式对应的高分榜名称
+func (g *Game) tableName() string {
+	return "tetris-" + g.mode.String()
+}
+
+// submitScore 游戏结束时提交成绩
+// 竞速模式只记录完成的对局，按用时排名
+func (g *Game) submitScore(seed int64) {
+	if g.mode == ModeSprint {
+		if !g.won {
+			return
+		}
+		scores.Submit(g.tableName(), scores.LowerIsBetter, scores.Entry{
+			Value:  g.elapsed.Milliseconds(),
+			Label:  formatDuration(g.elapsed),
+			Detail: fmt.Sprintf("SCORE %d", g.score),
+			Seed:   seed,
+		})
+		return
+	}
+	scores.Submit(g.tableName(), scores.HigherIsBetter, scores.Entry{
+		Value:  int64(g.score),
+		Label:  fmt.Sprint(g.score),
+		Detail: fmt.Sprintf("LINES %d  LEVEL %d", g.lines, g.level),
+		Seed:   seed,
+	})
+}
+
+// formatDuration 将时长格式化为 "m:ss.mmm"
+func formatDuration(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%d:%02d.%03d", ms/60000, ms/1000%60, ms%1000)
+}
+
 // ============================================
 // 游戏入口 - 主循环
 // ============================================
@@ -17,54 +95,200 @@ import (
 // 2. 根据时间间隔自动下落方块
 // 3. 渲染游戏画面
 //
-// 输入处理：
-// - ← →: 左右移动
-// - ↑: 旋转
-// - ↓: 软降（加速下落）
-// - 空格: 硬降（直接落到底）
-// - P: 暂停/继续
-// - R: 游戏结束时重新开始
-// - Esc: 返回主菜单
-func Run(screen tcell.Screen) {
-	game := NewGame()
-	renderer := NewRenderer(screen, game)
-	game.spawnPiece()
+// 输入处理（按键由 keys 映射为动作，默认按键见 input.DefaultTetris）：
+// - MoveLeft / MoveRight: 左右移动
+// - RotateCW: 旋转
+// - SoftDrop: 软降（加速下落）
+// - HardDrop: 硬降（直接落到底）
+// - Pause: 暂停/继续
+// - Restart: 游戏结束时重新开始（谜题中随时可以重来）
+// - Undo / Redo: 谜题和练习中撤销上一个方块、重做撤销掉的方块
+// - CopyFumen: 把当前面板和方块以 fumen 格式复制到终端剪贴板（OSC 52，随时可用）
+// - Back: 返回主菜单
+// - Quit: 退出程序
+//
+// 谜题和练习没有重力，不计入高分榜；谜题解开时记入解题记录
+//
+// 返回谜题加载、每日成绩和谜题记录保存、录像保存时的第一个错误
+func Run(screen tcell.Screen, keys *input.Keymap, cfg Config) error {
+	// 每日挑战：所有人使用当天的种子
+	if cfg.Daily != "" {
+		cfg.Mode, cfg.Seed = ModeMarathon, daily.Seed(DailyGame, cfg.Daily)
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	game := NewSeededGame(cfg.Mode, seed)
+	switch {
+	case cfg.Puzzle != "":
+		p, err := FindPuzzle(cfg.Puzzle)
+		if err != nil {
+			return err
+		}
+		cfg.Daily, cfg.Mode = "", ModeMarathon
+		game = NewPuzzleGame(p)
+	case cfg.Daily != "":
+		game = NewDailyGame(seed)
+	case cfg.Practice:
+		cfg.Mode = ModeMarathon
+		game = NewPracticeGame(seed)
+	}
+	puzzle := game.puzzle != nil
+	free := puzzle || game.practice // 没有重力，随时可以撤销、重做和重来
+	renderer := NewRenderer(screen, game, keys)
+
+	// 每日挑战当天已经有成绩时都是练习，信息面板显示当天的成绩
+	practice := false
+	if cfg.Daily != "" {
+		today, ok, err := daily.Get(DailyGame, cfg.Daily)
+		if err != nil {
+			return err
+		}
+		practice = ok
+		renderer.daily, renderer.practice, renderer.today = cfg.Daily, ok, today.Label
+	}
 	renderer.Render()
 
+	// 录像：记录本次会话中所有改变状态的动作
+	rec := replay.New("tetris", seed)
+	rec.Mode = cfg.Mode.String()
+	rec.Daily = cfg.Daily
+	if puzzle {
+		rec.Puzzle = game.puzzle.Name
+	}
+	rec.Practice = game.practice
+	var played time.Duration // 会话总游戏时间（不含暂停），用作录像时间轴
+
+	// saveErr 游戏过程中保存每日成绩或谜题记录失败的原因，离开时由 Run 返回
+	var saveErr error
+	keep := func(err error) {
+		if saveErr == nil {
+			saveErr = err
+		}
+	}
+
+	// finish 结束每日挑战当天计分的一局：记录得分，之后的对局都是练习
+	// 游戏结束和中途离开都会用掉这次机会（还没有任何操作或下落时不算）
+	started := false
+	finish := func() error {
+		if cfg.Daily == "" || practice || !started {
+			return nil
+		}
+		practice = true
+		_, err := daily.Record(DailyGame, daily.Result{
+			Date:   cfg.Daily,
+			Value:  int64(game.score),
+			Label:  fmt.Sprint(game.score),
+			Detail: fmt.Sprintf("LINES %d  LEVEL %d", game.lines, game.level),
+		})
+		renderer.practice, renderer.scored, renderer.today = true, game.gameOver && err == nil, fmt.Sprint(game.score)
+		return err
+	}
+	// save 离开时保存所有结果，返回第一个错误（包括游戏过程中的）
+	save := func() error {
+		keep(finish())
+		if cfg.Record != "" {
+			keep(rec.Save(cfg.Record))
+		}
+		return saveErr
+	}
+
+	// do 执行并记录一个动作，游戏刚结束时提交成绩
+	// 每日挑战的成绩记入每日历史而不是高分榜（练习可以无限重玩同一个种子）
+	do := func(action string) {
+		wasOver := game.gameOver
+		if action == input.Restart.String() {
+			renderer.scored = false
+		}
+		renderer.notice = ""
+		apply(game, action)
+		rec.Record(played, action)
+		started = true
+		if !wasOver && game.gameOver {
+			switch {
+			case puzzle:
+				if game.won {
+					keep(markSolved(game.puzzle.Name))
+				}
+			case game.practice:
+			case cfg.Daily != "":
+				keep(finish())
+			default:
+				game.submitScore(seed)
+			}
+		}
+	}
+
 	lastDrop := time.Now()
+	lastFrame := time.Now()
 
 	for {
 		// 计算下落间隔（毫秒）
 		interval := game.getDropInterval()
 		dropInterval := time.Duration(interval) * time.Millisecond
 
+		// 累计游戏时间
+		now := time.Now()
+		if !game.gameOver && !game.paused {
+			game.elapsed += now.Sub(lastFrame)
+			played += now.Sub(lastFrame)
+		}
+		lastFrame = now
+
 		// ---------- 处理用户输入 ----------
 		if screen.HasPendingEvent() {
 			event := screen.PollEvent()
 			if event != nil {
 				switch ev := event.(type) {
 				case *tcell.EventKey:
+					action, ok := keys.Lookup(ev)
+					if !ok {
+						continue
+					}
+
 					// 返回主菜单
-					if ev.Key() == tcell.KeyEscape {
-						return
+					if action == input.Back {
+						return save()
 					}
 
 					// 退出游戏
-					if ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' || ev.Rune() == 'Q' {
+					if action == input.Quit {
+						err := save()
+						screen.Fini()
+						if err != nil {
+							fmt.Fprintf(os.Stderr, "Failed to save: %v\n", err)
+							os.Exit(1)
+						}
 						os.Exit(0)
 					}
 
+					// 复制面板：终端通过 OSC 52 写入剪贴板（终端不支持时没有效果）
+					if action == input.CopyFumen {
+						screen.SetClipboard([]byte(EncodeFumen(game.Fumen())))
+						renderer.notice = "FUMEN COPIED"
+						renderer.Render()
+						continue
+					}
+
+					// 谜题和练习中随时可以撤销、重做或重来
+					if free && (action == input.Undo || action == input.Redo || action == input.Restart) {
+						do(action.String())
+						renderer.Render()
+						continue
+					}
+
 					// 游戏结束时的操作
 					if game.gameOver {
-						if ev.Rune() == 'r' || ev.Rune() == 'R' {
-							game.reset()
+						if action == input.Restart {
+							do(action.String())
 							renderer.Render()
 						}
 						continue
 					}
 
 					// 暂停/继续
-					if ev.Rune() == 'p' || ev.Rune() == 'P' {
+					if action == input.Pause {
 						game.paused = !game.paused
 						renderer.Render()
 						continue
@@ -74,21 +298,9 @@ func Run(screen tcell.Screen) {
 					}
 
 					// 游戏控制
-					switch ev.Key() {
-					case tcell.KeyLeft:
-						game.move(-1, 0)
-					case tcell.KeyRight:
-						game.move(1, 0)
-					case tcell.KeyDown:
-						game.drop()
-					case tcell.KeyUp:
-						game.rotate()
-					case tcell.KeyRune:
-						// 空格键：硬降（方块直接落到底）
-						if ev.Rune() == ' ' {
-							for game.drop() {
-							}
-						}
+					switch action {
+					case input.MoveLeft, input.MoveRight, input.SoftDrop, input.RotateCW, input.HardDrop:
+						do(action.String())
 					}
 					renderer.Render()
 
@@ -98,14 +310,97 @@ func Run(screen tcell.Screen) {
 			}
 		}
 
-		// ---------- 自动下落 ----------
-		if !game.gameOver && !game.paused && time.Since(lastDrop) > dropInterval {
-			game.drop()
+		// ---------- 自动下落（谜题和练习没有重力） ----------
+		if !game.gameOver && !game.paused && !free && time.Since(lastDrop) > dropInterval {
+			do(replay.Tick)
 			renderer.Render()
 			lastDrop = time.Now()
 		} else if !game.gameOver && !game.paused {
 			// 避免CPU占用过高
 			time.Sleep(10 * time.Millisecond)
+			if game.mode == ModeSprint {
+				// 竞速模式需要持续刷新计时器
+				renderer.Render()
+			}
+		} else {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// ============================================
+// 录像回放
+// ============================================
+// Replay 按录制时的节奏回放一段俄罗斯方块录像
+//
+// 回放时只响应以下动作：
+// - Pause: 暂停/继续回放
+// - Back / Quit: 结束回放
+func Replay(screen tcell.Screen, keys *input.Keymap, rp *replay.Replay) error {
+	mode, err := ParseMode(rp.Mode)
+	if err != nil {
+		return err
+	}
+
+	game := NewSeededGame(mode, rp.Seed)
+	switch {
+	case rp.Puzzle != "":
+		p, err := FindPuzzle(rp.Puzzle)
+		if err != nil {
+			return err
+		}
+		game = NewPuzzleGame(p)
+	case rp.Daily != "":
+		game = NewDailyGame(rp.Seed)
+	case rp.Practice:
+		game = NewPracticeGame(rp.Seed)
+	}
+	renderer := NewRenderer(screen, game, keys)
+	renderer.replay = true
+	renderer.Render()
+
+	player := replay.NewPlayer(rp)
+	var played time.Duration
+	lastFrame := time.Now()
+
+	for {
+		// 推进回放时间轴
+		now := time.Now()
+		if !game.paused {
+			played += now.Sub(lastFrame)
+			if !game.gameOver {
+				game.elapsed += now.Sub(lastFrame)
+			}
 		}
+		lastFrame = now
+
+		// ---------- 处理用户输入 ----------
+		for screen.HasPendingEvent() {
+			switch ev := screen.PollEvent().(type) {
+			case *tcell.EventKey:
+				action, _ := keys.Lookup(ev)
+				switch {
+				case action == input.Back || action == input.Quit:
+					return nil
+				case action == input.Pause:
+					game.paused = !game.paused
+				}
+			case nil:
+				return nil
+			}
+			renderer.Render()
+		}
+
+		// ---------- 执行到期的动作 ----------
+		due := player.Due(played)
+		for _, ev := range due {
+			apply(game, ev.Action)
+		}
+		if len(due) > 0 || (game.mode == ModeSprint && !game.gameOver) {
+			renderer.finished = player.Done()
+			renderer.Render()
+		}
+
+		time.Sleep(10 * time.Millisecond)
 	}
 }
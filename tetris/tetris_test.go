@@ -0,0 +1,164 @@
+package tetris
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go-game/daily"
+	"go-game/input"
+	"go-game/internal/screentest"
+	"go-game/internal/store"
+	"go-game/replay"
+)
+
+// TestRunScriptedSession 通过模拟按键完整地玩一段，再用录像复现同一画面
+func TestRunScriptedSession(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	path := filepath.Join(t.TempDir(), "session.json")
+	keys := input.DefaultTetris()
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	screentest.Type(screen, 5*time.Millisecond,
+		screentest.Key(tcell.KeyLeft),
+		screentest.Key(tcell.KeyLeft),
+		screentest.Rune(' '),
+		screentest.Key(tcell.KeyUp),
+		screentest.Rune('l'),
+		screentest.Rune(' '),
+		screentest.Key(tcell.KeyDown),
+		screentest.Rune('p'),
+		screentest.Key(tcell.KeyEscape),
+	)
+	if err := Run(screen, keys, Config{Seed: 42, Record: path}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !screentest.Contains(screen, "PAUSED") {
+		t.Errorf("last frame should show PAUSED:\n%s", screentest.Dump(screen))
+	}
+
+	rp, err := replay.Load(path)
+	if err != nil {
+		t.Fatalf("load replay: %v", err)
+	}
+	if rp.Game != "tetris" || rp.Seed != 42 || rp.Mode != "marathon" {
+		t.Errorf("replay header = %q seed %d mode %q", rp.Game, rp.Seed, rp.Mode)
+	}
+
+	// 录像中的动作重新执行后，画面应与实际对局的最后一帧完全一致
+	g := NewSeededGame(ModeMarathon, rp.Seed)
+	for _, ev := range rp.Events {
+		apply(g, ev.Action)
+	}
+	g.paused = true
+	want := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	NewRenderer(want, g, keys).Render()
+	if got, want := screentest.Dump(screen), screentest.Dump(want); got != want {
+		t.Errorf("replayed frame differs from the live one:\nlive:\n%s\nreplayed:\n%s", got, want)
+	}
+}
+
+// TestReplayPlayback 回放录像直到结束，按 Esc 退出
+func TestReplayPlayback(t *testing.T) {
+	rp := replay.New("tetris", 7)
+	rp.Mode = "sprint"
+	for i, action := range []string{"MoveLeft", "HardDrop", "RotateCW", "HardDrop", replay.Tick} {
+		rp.Record(time.Duration(i)*time.Millisecond, action)
+	}
+
+	// 录像只有几毫秒，等待足够久后按 Esc 退出
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	screentest.Type(screen, 200*time.Millisecond, screentest.Key(tcell.KeyEscape))
+	if err := Replay(screen, input.DefaultTetris(), rp); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !screentest.Contains(screen, "END OF REPLAY") {
+		t.Errorf("replay should have finished:\n%s", screentest.Dump(screen))
+	}
+	if !screentest.Contains(screen, "SPRINT REPLAY") {
+		t.Errorf("replay should be labelled:\n%s", screentest.Dump(screen))
+	}
+}
+
+// TestReplayRejectsUnknownMode 录像中的模式无效时返回错误而不是启动游戏
+func TestReplayRejectsUnknownMode(t *testing.T) {
+	rp := replay.New("tetris", 1)
+	rp.Mode = "zen"
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	if err := Replay(screen, input.DefaultTetris(), rp); err == nil {
+		t.Fatal("expected an error for an unknown mode")
+	}
+}
+
+// TestRunDaily 当天第一局计入每日历史（中途离开也算），之后的对局都是练习，方块序列与第一局相同
+func TestRunDaily(t *testing.T) {
+	t.Setenv(store.EnvHome, t.TempDir())
+	const date = "2026-10-18"
+
+	play := func() tcell.SimulationScreen {
+		screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+		screentest.Type(screen, 5*time.Millisecond, screentest.Rune(' '), screentest.Key(tcell.KeyEscape))
+		if err := Run(screen, input.DefaultTetris(), Config{Daily: date, Mode: ModeSprint, Seed: 1}); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return screen
+	}
+
+	first := play()
+	if !screentest.Contains(first, "DAILY") || !screentest.Contains(first, "TODAY: --") || screentest.Contains(first, "PRACTICE") {
+		t.Errorf("expected the scored daily game:\n%s", screentest.Dump(first))
+	}
+	history, err := daily.History(DailyGame)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 1 || history[0].Date != date {
+		t.Fatalf("history = %+v, want one result for %s", history, date)
+	}
+
+	second := play()
+	if !screentest.Contains(second, "PRACTICE") || !screentest.Contains(second, "TODAY: "+history[0].Label) {
+		t.Errorf("the second game should be practice:\n%s", screentest.Dump(second))
+	}
+	if again, _ := daily.History(DailyGame); len(again) != 1 {
+		t.Errorf("practice should not be recorded: %+v", again)
+	}
+
+	// 重新开始后回到当天种子的方块序列
+	g := NewDailyGame(daily.Seed(DailyGame, date))
+	for i := 0; i < 3; i++ {
+		g.Step(ActionHardDrop)
+	}
+	g.reset()
+	got, want := g.State(), NewSeededGame(ModeMarathon, daily.Seed(DailyGame, date)).State()
+	if got.Piece != want.Piece || got.Next != want.Next {
+		t.Errorf("restart gave pieces %d, %d; want the daily sequence %d, %d", got.Piece, got.Next, want.Piece, want.Next)
+	}
+}
+
+// TestRunDailySaveError 每日成绩写入失败时 Run 返回错误，而不是悄悄丢掉当天唯一计分的一局
+func TestRunDailySaveError(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(store.EnvHome, home)
+	// 占住临时文件的位置，读取每日历史正常，写入失败
+	if err := os.Mkdir(filepath.Join(home, "daily.json.tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	screen := screentest.NewScreen(t, screentest.Width, screentest.Height)
+	var keys []*tcell.EventKey
+	for range 80 {
+		keys = append(keys, screentest.Rune(' ')) // 一直硬降直到堆满
+	}
+	screentest.Type(screen, 2*time.Millisecond, append(keys, screentest.Key(tcell.KeyEscape))...)
+	err := Run(screen, input.DefaultTetris(), Config{Daily: "2026-10-18"})
+	if err == nil || !strings.Contains(err.Error(), "daily.json") {
+		t.Errorf("Run = %v, want the failed daily write", err)
+	}
+	if screentest.Contains(screen, "SCORE SAVED") {
+		t.Errorf("a failed write should not be reported as saved:\n%s", screentest.Dump(screen))
+	}
+}